@@ -0,0 +1,339 @@
+package oak
+
+import (
+	"context"
+	"image"
+	"image/draw"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/shake"
+	xdraw "golang.org/x/image/draw"
+)
+
+// A CameraTarget is something a Camera can follow. Targets which also have a width and height,
+// like entities.Entity, are followed by their center.
+type CameraTarget interface {
+	X() float64
+	Y() float64
+}
+
+type sizedCameraTarget interface {
+	W() float64
+	H() float64
+}
+
+// A CameraSmoothing moves a camera from pos towards goal over dt seconds. vel is the camera's
+// velocity as last returned by the smoothing, for smoothings which need to track it.
+type CameraSmoothing func(pos, goal, vel floatgeom.Point2, dt float64) (newPos, newVel floatgeom.Point2)
+
+// CameraSnap moves a camera directly to its goal.
+func CameraSnap() CameraSmoothing {
+	return func(pos, goal, vel floatgeom.Point2, dt float64) (floatgeom.Point2, floatgeom.Point2) {
+		return goal, floatgeom.Point2{}
+	}
+}
+
+// CameraLerp moves a camera part of the way to its goal each frame. Higher rates catch up faster;
+// each second, the camera covers all but e^-rate of its remaining distance.
+func CameraLerp(rate float64) CameraSmoothing {
+	return func(pos, goal, vel floatgeom.Point2, dt float64) (floatgeom.Point2, floatgeom.Point2) {
+		progress := 1 - math.Exp(-rate*dt)
+		return pos.Add(goal.Sub(pos).MulConst(progress)), floatgeom.Point2{}
+	}
+}
+
+// CameraSpring pulls a camera towards its goal as if by a damped spring. Stiffness is how hard the
+// spring pulls, and damping how strongly the camera's velocity is resisted; damping of
+// 2*sqrt(stiffness) settles quickest without overshooting.
+func CameraSpring(stiffness, damping float64) CameraSmoothing {
+	// step in small increments, as a large step could overshoot wildly
+	const maxStep = 1.0 / 120
+	return func(pos, goal, vel floatgeom.Point2, dt float64) (floatgeom.Point2, floatgeom.Point2) {
+		for dt > 0 {
+			step := math.Min(dt, maxStep)
+			dt -= step
+			accel := goal.Sub(pos).MulConst(stiffness).Sub(vel.MulConst(damping))
+			vel = vel.Add(accel.MulConst(step))
+			pos = pos.Add(vel.MulConst(step))
+		}
+		return pos, vel
+	}
+}
+
+// lookAheadResponse is how quickly, per second, look-ahead adjusts to changes in its targets'
+// velocity. Measured velocity is noisy, as targets move on logic frames, not draw frames.
+const lookAheadResponse = 8
+
+// A Camera controls a window's viewport, following targets and zooming the drawn world.
+// Cameras are made active with Window.SetCamera, after which they are updated every draw frame.
+// A window's camera is removed when a new scene starts.
+type Camera struct {
+	mu        sync.Mutex
+	targets   []CameraTarget
+	deadZone  floatgeom.Point2
+	smoothing CameraSmoothing
+	lookAhead float64
+	zoom      float64
+
+	// center is the world position at the center of the camera's view, before shaking
+	center floatgeom.Point2
+	vel    floatgeom.Point2
+	shake  floatgeom.Point2
+	// placed is whether center has been set, either directly or from the camera's targets
+	placed    bool
+	lastFocus floatgeom.Point2
+	focusVel  floatgeom.Point2
+}
+
+// NewCamera creates a camera following the given targets, snapping to them with no dead zone,
+// look-ahead or zoom.
+func NewCamera(targets ...CameraTarget) *Camera {
+	return &Camera{
+		targets:   targets,
+		smoothing: CameraSnap(),
+		zoom:      1,
+	}
+}
+
+// Follow replaces the targets of this camera. With several targets, the camera follows the center
+// of the area they cover. With no targets, the camera stays still.
+func (c *Camera) Follow(targets ...CameraTarget) {
+	c.mu.Lock()
+	c.targets = targets
+	c.mu.Unlock()
+}
+
+// SetDeadZone sets the size of an area in the middle of the screen, in screen pixels, that targets
+// may move within without moving the camera.
+func (c *Camera) SetDeadZone(size floatgeom.Point2) {
+	c.mu.Lock()
+	c.deadZone = size
+	c.mu.Unlock()
+}
+
+// SetSmoothing sets how this camera moves towards its targets. A nil smoothing snaps to them.
+func (c *Camera) SetSmoothing(s CameraSmoothing) {
+	if s == nil {
+		s = CameraSnap()
+	}
+	c.mu.Lock()
+	c.smoothing = s
+	c.vel = floatgeom.Point2{}
+	c.mu.Unlock()
+}
+
+// SetLookAhead makes this camera lead its targets in the direction they are moving, by how far
+// they would move in the given duration at their current speed.
+func (c *Camera) SetLookAhead(d time.Duration) {
+	c.mu.Lock()
+	c.lookAhead = d.Seconds()
+	c.mu.Unlock()
+}
+
+// SetZoom sets how much this camera magnifies the world. A zoom of 2 draws everything at twice
+// its size, showing half as much of the world in each direction. Zooms not above 0 are ignored.
+func (c *Camera) SetZoom(zoom float64) {
+	if zoom <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.zoom = zoom
+	c.mu.Unlock()
+}
+
+// Zoom returns how much this camera magnifies the world.
+func (c *Camera) Zoom() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.zoom
+}
+
+// Center returns the world position at the center of this camera's view.
+func (c *Camera) Center() floatgeom.Point2 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.center.Add(c.shake)
+}
+
+// SetCenter moves this camera directly, e.g. to cut to a new area without smoothing.
+func (c *Camera) SetCenter(pt floatgeom.Point2) {
+	c.mu.Lock()
+	c.center = pt
+	c.vel = floatgeom.Point2{}
+	c.placed = true
+	c.mu.Unlock()
+}
+
+// ShiftPos offsets this camera's view without affecting how it follows its targets. It allows a
+// Camera to be shaken by a shake.Shaker.
+func (c *Camera) ShiftPos(x, y float64) {
+	c.mu.Lock()
+	c.shake = c.shake.Add(floatgeom.Point2{x, y})
+	c.mu.Unlock()
+}
+
+// Shake shakes this camera with sk, or shake.DefaultShaker if sk is nil, for the given duration or
+// until ctx is done. Shaking the screen with shake.Screen would be undone by an active camera.
+func (c *Camera) Shake(ctx context.Context, sk *shake.Shaker, dur time.Duration) {
+	if sk == nil {
+		sk = shake.DefaultShaker
+	}
+	sk.ShakeContext(ctx, c, dur)
+}
+
+// focus returns the point this camera's targets are centered on. It must be called while holding
+// the camera's lock.
+func (c *Camera) focus() (floatgeom.Point2, bool) {
+	if len(c.targets) == 0 {
+		return floatgeom.Point2{}, false
+	}
+	pts := make([]floatgeom.Point2, len(c.targets))
+	for i, t := range c.targets {
+		pts[i] = floatgeom.Point2{t.X(), t.Y()}
+		if st, ok := t.(sizedCameraTarget); ok {
+			pts[i] = pts[i].Add(floatgeom.Point2{st.W() / 2, st.H() / 2})
+		}
+	}
+	return floatgeom.NewBoundingRect2(pts...).Center(), true
+}
+
+// update advances this camera by dt seconds, for a screen of the given size, and returns the
+// top left corner of its view. If bounds is non-nil, the view is kept within it.
+func (c *Camera) update(dt float64, screen floatgeom.Point2, bounds *floatgeom.Rect2) floatgeom.Point2 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if focus, ok := c.focus(); ok {
+		if !c.placed {
+			c.center = focus
+			c.lastFocus = focus
+			c.placed = true
+		}
+		if dt > 0 {
+			vel := focus.Sub(c.lastFocus).DivConst(dt)
+			c.focusVel = c.focusVel.Add(vel.Sub(c.focusVel).MulConst(1 - math.Exp(-lookAheadResponse*dt)))
+		}
+		c.lastFocus = focus
+		focus = focus.Add(c.focusVel.MulConst(c.lookAhead))
+
+		// only move far enough to keep the focus within the dead zone
+		goal := c.center
+		half := c.deadZone.DivConst(2 * c.zoom)
+		for i := 0; i < 2; i++ {
+			if d := focus[i] - c.center[i]; d > half[i] {
+				goal[i] = focus[i] - half[i]
+			} else if d < -half[i] {
+				goal[i] = focus[i] + half[i]
+			}
+		}
+		c.center, c.vel = c.smoothing(c.center, goal, c.vel, dt)
+	}
+
+	visible := screen.DivConst(c.zoom)
+	if bounds != nil {
+		for i := 0; i < 2; i++ {
+			min := bounds.Min[i] + visible[i]/2
+			max := bounds.Max[i] - visible[i]/2
+			if max < min {
+				c.center[i] = (bounds.Min[i] + bounds.Max[i]) / 2
+			} else {
+				c.center[i] = math.Max(min, math.Min(max, c.center[i]))
+			}
+		}
+	}
+	return c.center.Add(c.shake).Sub(visible.DivConst(2))
+}
+
+// SetCamera makes c control this window's viewport, or stops camera control if c is nil.
+func (w *Window) SetCamera(c *Camera) {
+	w.cameraMutex.Lock()
+	w.camera = c
+	w.cameraUpdated = time.Time{}
+	w.cameraMutex.Unlock()
+}
+
+// Camera returns the camera controlling this window's viewport, if any.
+func (w *Window) Camera() *Camera {
+	w.cameraMutex.Lock()
+	defer w.cameraMutex.Unlock()
+	return w.camera
+}
+
+// cameraDelta returns the seconds since cameras were last updated. It must be called while holding
+// the camera lock.
+func (w *Window) cameraDelta() float64 {
+	now := time.Now()
+	dt := 0.0
+	if !w.cameraUpdated.IsZero() {
+		dt = now.Sub(w.cameraUpdated).Seconds()
+	}
+	w.cameraUpdated = now
+	return dt
+}
+
+// worldBounds returns the viewport bounds, if they are enforced.
+func (w *Window) worldBounds() *floatgeom.Rect2 {
+	if !w.useViewBounds {
+		return nil
+	}
+	b := floatgeom.NewRect2(float64(w.viewBounds.Min.X()), float64(w.viewBounds.Min.Y()),
+		float64(w.viewBounds.Max.X()), float64(w.viewBounds.Max.Y()))
+	return &b
+}
+
+// updateCamera moves the viewport to follow the window's camera, if it has one, and returns the
+// zoom to draw the world with.
+func (w *Window) updateCamera() float64 {
+	w.cameraMutex.Lock()
+	c := w.camera
+	if c == nil {
+		w.cameraMutex.Unlock()
+		return 1
+	}
+	dt := w.cameraDelta()
+	w.cameraMutex.Unlock()
+
+	view := c.update(dt, floatgeom.Point2{float64(w.ScreenWidth), float64(w.ScreenHeight)}, w.worldBounds())
+	pos := intgeom.Point2{int(math.Round(view.X())), int(math.Round(view.Y()))}
+	if pos != w.viewPos {
+		// Q: Why not SetViewport?
+		// A: SetViewport keeps a screen sized view within the viewport bounds, but a zoomed
+		//    camera shows more or less than a screen's worth of the world.
+		w.viewPos = pos
+		event.TriggerOn(w.handler(), ViewportUpdate, w.viewPos)
+	}
+	return c.Zoom()
+}
+
+// drawView draws the world as seen from view, magnified by zoom, into the rect area of buff.
+// Zoomed views are first drawn to *zoomBuffer, which is replaced if it is not the right size.
+func (w *Window) drawView(buff *image.RGBA, rect image.Rectangle, view intgeom.Point2, zoom float64, zoomBuffer **image.RGBA) {
+	if zoom == 1 {
+		if rect == buff.Bounds() {
+			w.DrawStack.DrawToScreen(buff, &view, rect.Dx(), rect.Dy())
+			return
+		}
+		// Q: Why is the view offset, and the drawn area larger than rect?
+		// A: Renderables draw in buff's coordinates, not relative to the sub image's corner. The
+		//    stack may draw some renderables outside of rect; the sub image clips them.
+		sub := buff.SubImage(rect).(*image.RGBA)
+		offset := view.Sub(intgeom.Point2{rect.Min.X, rect.Min.Y})
+		w.DrawStack.DrawToScreen(sub, &offset, rect.Max.X, rect.Max.Y)
+		return
+	}
+	width := int(math.Ceil(float64(rect.Dx()) / zoom))
+	height := int(math.Ceil(float64(rect.Dy()) / zoom))
+	zb := *zoomBuffer
+	if zb == nil || zb.Bounds().Dx() != width || zb.Bounds().Dy() != height {
+		zb = image.NewRGBA(image.Rect(0, 0, width, height))
+		*zoomBuffer = zb
+	}
+	draw.Draw(zb, zb.Bounds(), w.bkgFn(), zeroPoint, draw.Src)
+	w.DrawStack.DrawToScreen(zb, &view, width, height)
+	xdraw.NearestNeighbor.Scale(buff, rect, zb, zb.Bounds(), draw.Src, nil)
+}
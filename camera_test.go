@@ -0,0 +1,140 @@
+package oak
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/mouse"
+)
+
+type testCameraTarget struct {
+	floatgeom.Point2
+}
+
+func (t *testCameraTarget) W() float64 { return 10 }
+func (t *testCameraTarget) H() float64 { return 10 }
+
+func approxPoint(a, b floatgeom.Point2) bool {
+	return math.Abs(a.X()-b.X()) < .01 && math.Abs(a.Y()-b.Y()) < .01
+}
+
+func TestCameraFollow(t *testing.T) {
+	screen := floatgeom.Point2{100, 100}
+	target := &testCameraTarget{floatgeom.Point2{95, 95}}
+	c := NewCamera(target)
+	// sized targets are followed by their center
+	if view := c.update(0, screen, nil); !approxPoint(view, floatgeom.Point2{50, 50}) {
+		t.Fatalf("expected view at (50, 50), got %v", view)
+	}
+
+	c.SetDeadZone(floatgeom.Point2{20, 20})
+	target.Point2 = floatgeom.Point2{100, 95}
+	if view := c.update(.1, screen, nil); !approxPoint(view, floatgeom.Point2{50, 50}) {
+		t.Fatalf("expected movement within the dead zone to be ignored, got %v", view)
+	}
+	target.Point2 = floatgeom.Point2{125, 95}
+	// the focus is now 30 right of center, 20 beyond the edge of the dead zone
+	if view := c.update(.1, screen, nil); !approxPoint(view, floatgeom.Point2{70, 50}) {
+		t.Fatalf("expected camera to keep its focus at the dead zone's edge, got %v", view)
+	}
+
+	// with several targets, the camera follows their center
+	c.SetDeadZone(floatgeom.Point2{})
+	c.Follow(target, &testCameraTarget{floatgeom.Point2{125, 295}})
+	if center := c.update(.1, screen, nil).Add(screen.DivConst(2)); !approxPoint(center, floatgeom.Point2{130, 200}) {
+		t.Fatalf("expected camera centered between targets, got %v", center)
+	}
+}
+
+func TestCameraSmoothing(t *testing.T) {
+	pos, goal := floatgeom.Point2{0, 0}, floatgeom.Point2{100, 0}
+
+	lerped, _ := CameraLerp(math.Ln2)(pos, goal, floatgeom.Point2{}, 1)
+	if !approxPoint(lerped, floatgeom.Point2{50, 0}) {
+		t.Fatalf("expected lerp to cover half the distance, got %v", lerped)
+	}
+
+	spring := CameraSpring(100, 20)
+	var vel floatgeom.Point2
+	pos, vel = spring(pos, goal, vel, .05)
+	if pos.X() <= 0 || pos.X() >= 100 || vel.X() <= 0 {
+		t.Fatalf("expected spring to move towards its goal, got %v %v", pos, vel)
+	}
+	pos, _ = spring(pos, goal, vel, 5)
+	if !approxPoint(pos, goal) {
+		t.Fatalf("expected spring to settle at its goal, got %v", pos)
+	}
+}
+
+func TestCameraLookAhead(t *testing.T) {
+	screen := floatgeom.Point2{100, 100}
+	target := &testCameraTarget{floatgeom.Point2{95, 95}}
+	c := NewCamera(target)
+	c.SetLookAhead(time.Second)
+	c.update(0, screen, nil)
+	for i := 0; i < 100; i++ {
+		target.Point2 = target.Point2.Add(floatgeom.Point2{1, 0})
+		c.update(.1, screen, nil)
+	}
+	// moving 10 pixels per second, the camera leads by up to 10 pixels
+	lead := c.Center().X() - (target.X() + 5)
+	if lead < 9 || lead > 10 {
+		t.Fatalf("expected camera to lead its target by about 10, got %v", lead)
+	}
+}
+
+func TestCameraBoundsZoomAndShake(t *testing.T) {
+	screen := floatgeom.Point2{100, 100}
+	bounds := floatgeom.NewRect2(0, 0, 400, 400)
+	target := &testCameraTarget{floatgeom.Point2{0, 0}}
+	c := NewCamera(target)
+	if view := c.update(0, screen, &bounds); !approxPoint(view, floatgeom.Point2{0, 0}) {
+		t.Fatalf("expected view clamped to bounds, got %v", view)
+	}
+	c.SetZoom(2)
+	c.SetZoom(-1)
+	if c.Zoom() != 2 {
+		t.Fatalf("expected zoom 2, got %v", c.Zoom())
+	}
+	target.Point2 = floatgeom.Point2{195, 195}
+	// zoomed in, only 50x50 of the world is visible
+	if view := c.update(.1, screen, &bounds); !approxPoint(view, floatgeom.Point2{175, 175}) {
+		t.Fatalf("expected zoomed view at (175, 175), got %v", view)
+	}
+	c.ShiftPos(3, -3)
+	if view := c.update(.1, screen, &bounds); !approxPoint(view, floatgeom.Point2{178, 172}) {
+		t.Fatalf("expected shaken view at (178, 172), got %v", view)
+	}
+}
+
+func TestWindowCamera(t *testing.T) {
+	w := NewWindow()
+	w.ScreenWidth, w.ScreenHeight = 100, 100
+	if w.updateCamera() != 1 {
+		t.Fatal("expected no zoom without a camera")
+	}
+	c := NewCamera(&testCameraTarget{floatgeom.Point2{195, 195}})
+	c.SetZoom(2)
+	w.SetCamera(c)
+	if w.Camera() != c {
+		t.Fatal("expected camera to be set")
+	}
+	if zoom := w.updateCamera(); zoom != 2 {
+		t.Fatalf("expected zoom 2, got %v", zoom)
+	}
+	if w.Viewport() != (intgeom.Point2{175, 175}) {
+		t.Fatalf("expected viewport at (175, 175), got %v", w.Viewport())
+	}
+	// relative mouse positions account for zoom
+	w.TriggerMouseEvent(mouse.NewEvent(50, 50, mouse.ButtonLeft, mouse.Press))
+	if rel := w.LastRelativeMouseEvent.Point2; !approxPoint(rel, floatgeom.Point2{200, 200}) {
+		t.Fatalf("expected relative mouse event at (200, 200), got %v", rel)
+	}
+	w.SetCamera(nil)
+	if w.updateCamera() != 1 {
+		t.Fatal("expected no zoom after removing camera")
+	}
+}
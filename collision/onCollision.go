@@ -57,8 +57,8 @@ func PhaseCollisionWithBus(s *Space, tree *Tree, bus event.Handler) error {
 
 // CollisionStart/Stop: when a PhaseCollision entity starts/stops touching some label.
 var (
-	Start = event.RegisterEvent[Label]()
-	Stop  = event.RegisterEvent[Label]()
+	Start = event.RegisterNamedEvent[Label]("collision.Start")
+	Stop  = event.RegisterNamedEvent[Label]("collision.Stop")
 )
 
 func phaseCollisionEnter(id event.CallerID, handler event.Handler, _ interface{}) event.Response {
@@ -2,6 +2,7 @@ package debugstream
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -23,6 +24,7 @@ func (sc *ScopedCommands) AddDefaultsForScope(scopeID int32, controller window.W
 	dlog.ErrorCheck(sc.AddCommand(Command{ScopeID: scopeID, Name: "quit", Usage: explainQuit, Operation: quitCommands(controller)}))
 	dlog.ErrorCheck(sc.AddCommand(Command{ScopeID: scopeID, Name: "skip-scene", Operation: skipCommands(controller)}))
 	dlog.ErrorCheck(sc.AddCommand(Command{ScopeID: scopeID, Name: "move", Operation: moveWindow(controller)}))
+	dlog.ErrorCheck(sc.AddCommand(Command{ScopeID: scopeID, Name: "events", Usage: explainEvents, Operation: eventCommands(controller)}))
 
 	if sc.assumedScope != 0 {
 		return
@@ -102,6 +104,69 @@ func mouseDetails(w window.Window) func(*mouse.Event) event.Response {
 	}
 }
 
+const explainEvents = "list events with bindings and how many bindings each caller has. " +
+	"Specify 'all' to include events without bindings, or any other text to only list events whose names contain that text"
+
+func eventCommands(w window.Window) func([]string) string {
+	return func(tokenString []string) string {
+		bc, ok := w.EventHandler().(event.BindingCounter)
+		if !ok {
+			return "event handler does not support counting bindings\n"
+		}
+		counts := bc.BindingCounts()
+		cm := w.EventHandler().GetCallerMap()
+		all := false
+		filters := []string{}
+		for _, tok := range tokenString {
+			if tok == "all" {
+				all = true
+			} else {
+				filters = append(filters, tok)
+			}
+		}
+		out := &strings.Builder{}
+		for _, info := range event.Events() {
+			callers := counts[info.ID]
+			if len(callers) == 0 && !all {
+				continue
+			}
+			if !matchesAny(info.Name, filters) {
+				continue
+			}
+			total := 0
+			callerIDs := make([]event.CallerID, 0, len(callers))
+			for cid, count := range callers {
+				total += count
+				callerIDs = append(callerIDs, cid)
+			}
+			sort.Slice(callerIDs, func(i, j int) bool {
+				return callerIDs[i] < callerIDs[j]
+			})
+			fmt.Fprintf(out, "%d %s (%v): %d bindings\n", info.ID, info.Name, info.PayloadType, total)
+			for _, cid := range callerIDs {
+				if cid == event.Global {
+					fmt.Fprintf(out, "\tglobal: %d\n", callers[cid])
+					continue
+				}
+				fmt.Fprintf(out, "\t%d (%T): %d\n", cid, cm.GetEntity(cid), callers[cid])
+			}
+		}
+		return out.String()
+	}
+}
+
+func matchesAny(name string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if strings.Contains(name, f) {
+			return true
+		}
+	}
+	return false
+}
+
 const explainQuit = "close the given window"
 
 func quitCommands(w window.Window) func([]string) string {
@@ -3,6 +3,7 @@ package debugstream
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -21,6 +22,8 @@ type fakeWindow struct {
 	fullscreenCalls int
 
 	moveWindow func(x, y, w, h int)
+
+	handler event.Handler
 }
 
 func (f *fakeWindow) NextScene() {
@@ -32,6 +35,9 @@ func (f *fakeWindow) Quit() {
 }
 
 func (f *fakeWindow) EventHandler() event.Handler {
+	if f.handler != nil {
+		return f.handler
+	}
 	return event.NewBus(nil)
 }
 
@@ -199,3 +205,29 @@ func TestFadeCommands(t *testing.T) {
 		t.Fatal("got:\n" + got + "\nexpected:\n" + expected)
 	}
 }
+
+func TestEventCommands(t *testing.T) {
+	type testCaller struct {
+		event.CallerID
+	}
+	ev := event.RegisterNamedEvent[int]("debugstream.testEvent")
+	unbound := event.RegisterNamedEvent[int]("debugstream.unboundEvent")
+	bus := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+	c := &testCaller{}
+	c.CallerID = bus.GetCallerMap().Register(c)
+	event.GlobalBind(bus, ev, func(int) event.Response { return 0 })
+	event.Bind(bus, ev, c, func(*testCaller, int) event.Response { return 0 })
+	event.Bind(bus, ev, c, func(*testCaller, int) event.Response { return 0 })
+
+	fw := &fakeWindow{handler: bus}
+	got := eventCommands(fw)([]string{"debugstream"})
+	expected := fmt.Sprintf("%d debugstream.testEvent (int): 3 bindings\n\tglobal: 1\n\t%d (*debugstream.testCaller): 2\n", ev.UnsafeEventID, c.CallerID)
+	if got != expected {
+		t.Fatal("got:\n" + got + "\nexpected:\n" + expected)
+	}
+	got = eventCommands(fw)([]string{"all", "debugstream.unbound"})
+	expected = fmt.Sprintf("%d debugstream.unboundEvent (int): 0 bindings\n", unbound.UnsafeEventID)
+	if got != expected {
+		t.Fatal("got:\n" + got + "\nexpected:\n" + expected)
+	}
+}
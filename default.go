@@ -10,6 +10,7 @@ import (
 	"github.com/oakmound/oak/v4/key"
 	"github.com/oakmound/oak/v4/render"
 	"github.com/oakmound/oak/v4/scene"
+	"github.com/oakmound/oak/v4/timing"
 )
 
 var defaultWindow *Window
@@ -23,11 +24,12 @@ func initDefaultWindow() {
 }
 
 // Init calls Init on the default window. The default window
-// will be set to use render.GlobalDrawStack and event.DefaultBus.
+// will be set to use render.GlobalDrawStack, event.DefaultBus and timing.DefaultClock.
 func Init(scene string, configOptions ...ConfigOption) error {
 	initDefaultWindow()
 	defaultWindow.DrawStack = render.GlobalDrawStack
 	defaultWindow.eventHandler = event.DefaultBus
+	defaultWindow.Clock = timing.DefaultClock
 	return defaultWindow.Init(scene, configOptions...)
 }
 
@@ -67,6 +69,18 @@ func SetViewport(pt intgeom.Point2) {
 	defaultWindow.SetViewport(pt)
 }
 
+// SetCamera calls SetCamera on the default window.
+func SetCamera(c *Camera) {
+	initDefaultWindow()
+	defaultWindow.SetCamera(c)
+}
+
+// SetSplitViews calls SetSplitViews on the default window.
+func SetSplitViews(views ...*SplitView) {
+	initDefaultWindow()
+	defaultWindow.SetSplitViews(views...)
+}
+
 // UpdateViewSize calls UpdateViewSize on the default window.
 func UpdateViewSize(w, h int) error {
 	initDefaultWindow()
@@ -30,15 +30,25 @@ func (w *Window) drawLoop() {
 			w.publish()
 			draw.Draw(buff.RGBA(), buff.Bounds(), w.bkgFn(), zeroPoint, draw.Src)
 			w.DrawStack.PreDraw()
+			zoom := w.updateCamera()
 			p := w.viewPos
-			w.DrawStack.DrawToScreen(buff.RGBA(), &p, w.ScreenWidth, w.ScreenHeight)
+			if !w.drawSplitViews(buff.RGBA()) {
+				w.drawView(buff.RGBA(), buff.Bounds(), p, zoom, &w.zoomBuffer)
+			}
+			w.drawPushedScenes(buff.RGBA(), &p)
+			w.applyBlend(buff.RGBA())
 		}
 	}
 
 	drawLoadingFrame := func() {
 		buff := w.winBuffers[w.bufferIdx]
 		w.publish()
-		draw.Draw(buff.RGBA(), w.winBuffers[w.bufferIdx].Bounds(), w.bkgFn(), zeroPoint, draw.Src)
+		// while waiting to blend into the next scene, hold on the last frame of the previous scene
+		if from := w.blendingFrom(); from != nil && from.Bounds() == buff.Bounds() {
+			draw.Draw(buff.RGBA(), buff.Bounds(), from, zeroPoint, draw.Src)
+		} else {
+			draw.Draw(buff.RGBA(), w.winBuffers[w.bufferIdx].Bounds(), w.bkgFn(), zeroPoint, draw.Src)
+		}
 		if w.LoadingR != nil {
 			w.LoadingR.Draw(w.winBuffers[w.bufferIdx].RGBA(), 0, 0)
 		}
@@ -74,6 +74,8 @@ type Entity struct {
 	event.CallerID
 
 	ctx *scene.Context
+	// ownsCID is false for entities sharing their parent's caller ID
+	ownsCID bool
 
 	Rect  floatgeom.Rect2
 	Speed floatgeom.Point2
@@ -186,18 +188,39 @@ func (e *Entity) HitLabel(label collision.Label) *collision.Space {
 	return e.Tree.HitLabel(e.Space, label)
 }
 
+// Destroy undraws this entity, removes its collision space, and unbinds its bindings. If this entity
+// has its own caller ID, it is then destroyed via event.Destroy, running any other cleanups added to
+// it. The entity is undrawn and its space removed before Destroy returns, even if event.Destroy
+// finishes later, so it is not drawn or hit on the frame it is destroyed.
 func (e *Entity) Destroy() {
-	e.Renderable.Undraw()
-	e.Tree.Remove(e.Space)
+	e.cleanup()
+	if e.ownsCID {
+		// Q: Why clean up here, when the entity's cleanup is also run by event.Destroy?
+		// A: event.Destroy runs cleanups once Destroyed's bindings have run, which on an async
+		//    bus is after it returns. Cleaning up twice is harmless.
+		event.Destroy(e.ctx, e.CallerID)
+		return
+	}
+	e.cleanup()
 	e.ctx.UnbindAllFrom(e.CallerID)
 }
 
+func (e *Entity) cleanup() {
+	e.Renderable.Undraw()
+	if e.Tree != nil {
+		e.Tree.Remove(e.Space)
+	}
+}
+
 // SetMetadata sets the metadata for some key to some value. Empty value strings
 // will not be stored.
 func (e *Entity) SetMetadata(k, v string) {
 	if v == "" {
 		delete(e.metadata, k)
 	} else {
+		if e.metadata == nil {
+			e.metadata = make(map[string]string)
+		}
 		e.metadata[k] = v
 	}
 }
@@ -246,12 +269,17 @@ func New(ctx *scene.Context, opts ...Option) *Entity {
 	if g.Parent == nil {
 		cid := ctx.CallerMap.Register(e)
 		e.CallerID = cid
+		e.ownsCID = true
 	} else {
 		e.CallerID = g.Parent.CID()
 		if e.CallerID == 0 {
 			dlog.Error("entity created with uninitialized parent caller ID")
 		}
 	}
+	for _, child := range children {
+		// events triggered via TriggerBubbling on a child will continue on to this entity
+		ctx.CallerMap.SetParent(child.CID(), e.CallerID)
+	}
 
 	if !g.WithoutCollision {
 		e.Tree = ctx.CollisionTree
@@ -269,5 +297,10 @@ func New(ctx *scene.Context, opts ...Option) *Entity {
 		ctx.Draw(e.Renderable, g.DrawLayers...)
 	}
 
+	if e.ownsCID {
+		ctx.CallerMap.AddCleanup(e.CallerID, e.cleanup)
+		event.TriggerOn(ctx, event.Registered, e.CallerID)
+	}
+
 	return e
 }
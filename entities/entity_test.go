@@ -0,0 +1,59 @@
+package entities_test
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/entities"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+func TestEntity_Destroy(t *testing.T) {
+	cm := event.NewCallerMap()
+	ctx := &scene.Context{
+		CallerMap:     cm,
+		Handler:       event.NewBus(cm),
+		DrawStack:     render.NewDrawStack(render.NewDynamicHeap()),
+		CollisionTree: collision.NewTree(),
+		MouseTree:     collision.NewTree(),
+	}
+	e := entities.New(ctx,
+		entities.WithRect(floatgeom.NewRect2WH(0, 0, 10, 10)),
+		entities.WithColor(color.RGBA{255, 0, 0, 255}),
+		entities.WithDrawLayers([]int{0}),
+	)
+	probe := collision.NewUnassignedSpace(0, 0, 5, 5)
+	if len(ctx.CollisionTree.Hits(probe)) != 1 {
+		t.Fatal("expected entity's space in the collision tree")
+	}
+	destroyed := make(chan struct{})
+	event.Bind(ctx, event.Destroyed, e, func(*entities.Entity, event.CallerID) event.Response {
+		// hold up the rest of destruction until the entity has been checked
+		<-destroyed
+		return 0
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	e.Destroy()
+	// the async bus has not finished destroying the entity, but it should already be gone
+	if len(ctx.CollisionTree.Hits(probe)) != 0 {
+		t.Fatal("expected entity's space to be removed when Destroy returns")
+	}
+	if e.Renderable.GetLayer() != render.Undraw {
+		t.Fatal("expected entity to be undrawn when Destroy returns")
+	}
+	close(destroyed)
+	deadline := time.After(time.Second)
+	for cm.HasEntity(e.CID()) {
+		select {
+		case <-deadline:
+			t.Fatal("expected entity to be removed from the caller map")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
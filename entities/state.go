@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+)
+
+// A State is the saveable state of an entity: its position, movement, label and metadata. Renderables,
+// bindings and children are not included, and should be recreated by whatever restores the entity.
+type State struct {
+	Rect     floatgeom.Rect2
+	Speed    floatgeom.Point2
+	Delta    floatgeom.Point2
+	Label    collision.Label
+	Metadata map[string]string `json:",omitempty"`
+}
+
+// State returns the current state of this entity.
+func (e *Entity) State() State {
+	s := State{
+		Rect:  e.Rect,
+		Speed: e.Speed,
+		Delta: e.Delta,
+	}
+	if e.Space != nil {
+		s.Label = e.Space.Label
+	}
+	if len(e.metadata) != 0 {
+		s.Metadata = make(map[string]string, len(e.metadata))
+		for k, v := range e.metadata {
+			s.Metadata[k] = v
+		}
+	}
+	return s
+}
+
+// SetState moves and resizes this entity to match s, and replaces its speed, label and metadata.
+func (e *Entity) SetState(s State) {
+	e.Rect = s.Rect
+	e.SetPos(s.Rect.Min)
+	e.Speed = s.Speed
+	e.Delta = s.Delta
+	if e.Space != nil {
+		e.Space.Label = s.Label
+	}
+	e.metadata = make(map[string]string, len(s.Metadata))
+	for k, v := range s.Metadata {
+		e.metadata[k] = v
+	}
+}
+
+// Options returns options which will create an entity with this state's position, dimensions, speed and
+// label. Its delta and metadata must be restored after creation, e.g. via SetState.
+func (s State) Options() []Option {
+	return []Option{
+		WithRect(s.Rect),
+		WithSpeed(s.Speed),
+		WithLabel(s.Label),
+	}
+}
@@ -44,12 +44,45 @@ func (b Binding) Unbind() <-chan struct{} {
 // A BindID is a unique identifier for a binding within a bus.
 type BindID int64
 
+// A BindOption modifies the details of a binding.
+type BindOption func(BindOptions) BindOptions
+
+// BindOptions are the details of a binding which can be set through BindOptions.
+type BindOptions struct {
+	// Priority determines the order bindings are called in when their event is triggered.
+	// Bindings with a higher priority are called, and return, before those with a lower
+	// priority are called. Bindings with the same priority have no defined order relative
+	// to one another. Bindings default to priority 0.
+	Priority int
+}
+
+// WithPriority sets the priority of a binding. See BindOptions.Priority.
+func WithPriority(priority int) BindOption {
+	return func(bo BindOptions) BindOptions {
+		bo.Priority = priority
+		return bo
+	}
+}
+
+// NewBindOptions applies the given options, in order, to a zero BindOptions.
+func NewBindOptions(opts ...BindOption) BindOptions {
+	bo := BindOptions{}
+	for _, opt := range opts {
+		bo = opt(bo)
+	}
+	return bo
+}
+
 // UnsafeBind registers a callback function to be called whenever the provided event is triggered
 // against this bus. The binding is concurrently bound, and therefore may not be immediately
 // available to be triggered. When Reset is called on a Bus, all prior bindings are unbound and any
 // concurrent calls to UnsafeBind will not take effect. This call is 'unsafe' because UnsafeBindables
 // use bare interface{} types.
-func (bus *Bus) UnsafeBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable) Binding {
+func (bus *Bus) UnsafeBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable, opts ...BindOption) Binding {
+	return bus.bind(eventID, callerID, fn, NewBindOptions(opts...))
+}
+
+func (bus *Bus) bind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable, bo BindOptions) Binding {
 	expectedResetCount := bus.resetCount
 	bindID := BindID(atomic.AddInt64(bus.nextBindID, 1))
 	ch := make(chan struct{})
@@ -62,7 +95,10 @@ func (bus *Bus) UnsafeBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBi
 			return
 		}
 		bl := bus.getBindableList(eventID, callerID)
-		bl[bindID] = fn
+		bl[bindID] = bindable{
+			fn:       fn,
+			priority: bo.Priority,
+		}
 	}()
 	return Binding{
 		Handler:       bus,
@@ -78,14 +114,16 @@ func (bus *Bus) UnsafeBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBi
 // persisting the binding through bus resets. Unbinding this will not stop it from being rebound on the next
 // Bus Reset-- ClearPersistentBindings will. If called concurrently during a bus Reset, the request may not be
 // bound until the next bus Reset.
-func (bus *Bus) PersistentBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable) Binding {
-	binding := bus.UnsafeBind(eventID, callerID, fn)
+func (bus *Bus) PersistentBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable, opts ...BindOption) Binding {
+	bo := NewBindOptions(opts...)
+	binding := bus.bind(eventID, callerID, fn, bo)
 	go func() {
 		bus.mutex.Lock()
 		bus.persistentBindings = append(bus.persistentBindings, persistentBinding{
 			eventID:  eventID,
 			callerID: callerID,
 			fn:       fn,
+			opts:     bo,
 		})
 		bus.mutex.Unlock()
 	}()
@@ -116,8 +154,8 @@ type Bindable[C any, Payload any] func(C, Payload) Response
 
 // Bind will cause the function fn to be called whenever the event ev is triggered on the given event handler. The function
 // will be called with the provided caller as its first argument, and will also be called when the provided event is specifically
-// triggered on the caller's ID.
-func Bind[C Caller, Payload any](h Handler, ev EventID[Payload], caller C, fn Bindable[C, Payload]) Binding {
+// triggered on the caller's ID. BindOptions, e.g. WithPriority, may be provided to order this binding against others.
+func Bind[C Caller, Payload any](h Handler, ev EventID[Payload], caller C, fn Bindable[C, Payload], opts ...BindOption) Binding {
 	if caller.CID() == 0 {
 		dlog.Error("Bind called with CallerID 0; is this entity registered and set?")
 	}
@@ -126,18 +164,19 @@ func Bind[C Caller, Payload any](h Handler, ev EventID[Payload], caller C, fn Bi
 		ent := h.GetCallerMap().GetEntity(cid)
 		typedEntity := ent.(C)
 		return fn(typedEntity, typedPayload)
-	})
+	}, opts...)
 }
 
 // A GlobalBindable is a bindable that is not bound to a specific caller.
 type GlobalBindable[Payload any] func(Payload) Response
 
 // GlobalBind will cause the function fn to be called whenever the event ev is triggered on the given event handler.
-func GlobalBind[Payload any](h Handler, ev EventID[Payload], fn GlobalBindable[Payload]) Binding {
+// BindOptions, e.g. WithPriority, may be provided to order this binding against others.
+func GlobalBind[Payload any](h Handler, ev EventID[Payload], fn GlobalBindable[Payload], opts ...BindOption) Binding {
 	return h.UnsafeBind(ev.UnsafeEventID, Global, func(cid CallerID, h Handler, payload interface{}) Response {
 		typedPayload := payload.(Payload)
 		return fn(typedPayload)
-	})
+	}, opts...)
 }
 
 // UnsafeBindable defines the underlying signature of all bindings.
@@ -3,6 +3,8 @@ package event
 import (
 	"sync"
 	"time"
+
+	"github.com/oakmound/oak/v4/timing"
 )
 
 // A Bus stores bindables to be triggered by events.
@@ -28,6 +30,7 @@ type persistentBinding struct {
 	eventID  UnsafeEventID
 	callerID CallerID
 	fn       UnsafeBindable
+	opts     BindOptions
 }
 
 // NewBus returns an empty event bus with an assigned caller map. If nil
@@ -69,7 +72,7 @@ func (bus *Bus) Reset() {
 	bus.bindingMap = make(map[UnsafeEventID]map[CallerID]bindableList)
 	repersist := make([]Binding, len(bus.persistentBindings))
 	for i, pb := range bus.persistentBindings {
-		repersist[i] = bus.UnsafeBind(pb.eventID, pb.callerID, pb.fn)
+		repersist[i] = bus.bind(pb.eventID, pb.callerID, pb.fn, pb.opts)
 	}
 	bus.mutex.Unlock()
 	for _, bnd := range repersist {
@@ -77,6 +80,27 @@ func (bus *Bus) Reset() {
 	}
 }
 
+// BindingCounts returns, for each event with at least one binding, how many
+// bindings each caller currently has to that event. Pending binds and unbinds
+// may not be reflected.
+func (bus *Bus) BindingCounts() map[UnsafeEventID]map[CallerID]int {
+	bus.mutex.RLock()
+	defer bus.mutex.RUnlock()
+	counts := make(map[UnsafeEventID]map[CallerID]int)
+	for eventID, callers := range bus.bindingMap {
+		for callerID, bl := range callers {
+			if len(bl) == 0 {
+				continue
+			}
+			if counts[eventID] == nil {
+				counts[eventID] = make(map[CallerID]int)
+			}
+			counts[eventID][callerID] = len(bl)
+		}
+	}
+	return counts
+}
+
 // EnterLoop triggers Enter events at the specified rate until the returned cancel is called.
 func EnterLoop(bus Handler, frameDelay time.Duration) (cancel func()) {
 	ch := make(chan struct{})
@@ -112,3 +136,69 @@ func EnterLoop(bus Handler, frameDelay time.Duration) (cancel func()) {
 		close(ch)
 	}
 }
+
+// maxCatchUpFrames limits how many enter events ClockEnterLoop will trigger for a single tick,
+// so a high time scale or a slow frame cannot leave the loop permanently behind.
+const maxCatchUpFrames = 8
+
+// ClockEnterLoop acts like EnterLoop, but measures frames in the game time of the provided clock.
+// While the clock is paused no Enter events are triggered. When the clock is scaled to run slower
+// than real time, Enter events are triggered less often, and when it runs faster, multiple Enter
+// events may be triggered per tick, so logic counting frames is scaled alongside logic reading
+// SinceLastFrame. If clock is nil, timing.DefaultClock is used.
+func ClockEnterLoop(bus Handler, frameDelay time.Duration, clock *timing.Clock) (cancel func()) {
+	if clock == nil {
+		clock = timing.DefaultClock
+	}
+	ch := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(frameDelay)
+		frameDelayF64 := float64(frameDelay)
+		lastTick := clock.Now()
+		lastFrame := lastTick
+		framesElapsed := 0
+		// owed is game time which has passed, but has not been reported by an enter event
+		var owed time.Duration
+		for {
+			select {
+			case <-ticker.C:
+				now := clock.Now()
+				owed += now - lastTick
+				lastTick = now
+				// Q: Why trigger at half a frame owed instead of a full frame?
+				// A: Ticks arrive with some jitter. At a scale of 1 this keeps one enter per tick
+				//    instead of occasionally skipping a tick and triggering two on the next.
+				frames := 0
+				for owed >= frameDelay/2 && frames < maxCatchUpFrames {
+					owed -= frameDelay
+					frames++
+				}
+				if owed > frameDelay {
+					// we could not catch up; drop the excess time
+					owed = 0
+				}
+				if frames == 0 {
+					continue
+				}
+				deltaTime := (now - lastFrame) / time.Duration(frames)
+				lastFrame = now
+				for i := 0; i < frames; i++ {
+					<-bus.Trigger(Enter.UnsafeEventID, EnterPayload{
+						FramesElapsed:  framesElapsed,
+						SinceLastFrame: deltaTime,
+						TickPercent:    float64(deltaTime) / frameDelayF64,
+					})
+					framesElapsed++
+				}
+			case <-ch:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() {
+		// see EnterLoop
+		ch <- struct{}{}
+		close(ch)
+	}
+}
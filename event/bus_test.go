@@ -2,11 +2,13 @@ package event_test
 
 import (
 	"math/rand"
+	"reflect"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/timing"
 )
 
 func TestNewBus(t *testing.T) {
@@ -97,3 +99,116 @@ func TestBus_EnterLoop(t *testing.T) {
 		}
 	})
 }
+
+func TestClockEnterLoop(t *testing.T) {
+	t.Run("Paused", func(t *testing.T) {
+		b := event.NewBus(event.NewCallerMap())
+		var calls int32
+		b1 := event.GlobalBind(b, event.Enter, func(event.EnterPayload) event.Response {
+			atomic.AddInt32(&calls, 1)
+			return 0
+		})
+		<-b1.Bound
+		clock := timing.NewClock()
+		clock.Pause()
+		cancel := event.ClockEnterLoop(b, 20*time.Millisecond, clock)
+		time.Sleep(200 * time.Millisecond)
+		cancel()
+		if calls != 0 {
+			t.Fatal(expectedError("calls", 0, calls))
+		}
+	})
+	t.Run("Scaled", func(t *testing.T) {
+		b := event.NewBus(event.NewCallerMap())
+		var calls int32
+		b1 := event.GlobalBind(b, event.Enter, func(event.EnterPayload) event.Response {
+			atomic.AddInt32(&calls, 1)
+			return 0
+		})
+		<-b1.Bound
+		clock := timing.NewClock()
+		clock.SetScale(2)
+		cancel := event.ClockEnterLoop(b, 50*time.Millisecond, clock)
+		time.Sleep(1*time.Second + 15*time.Millisecond)
+		cancel()
+		if calls < 38 || calls > 40 {
+			t.Fatal(expectedError("calls", 40, calls))
+		}
+	})
+}
+
+func TestBus_Priority(t *testing.T) {
+	t.Run("Ordered", func(t *testing.T) {
+		b := event.NewBus(event.NewCallerMap())
+		cid := b.GetCallerMap().Register(event.CallerID(0))
+		var order int32
+		results := make([]int32, 6)
+		priorities := []int{-5, 10, 0, 10, 3, -5}
+		for i, p := range priorities {
+			i := i
+			callerID := event.Global
+			if i%2 == 0 {
+				callerID = cid
+			}
+			bnd := b.UnsafeBind(1, callerID, func(ci event.CallerID, h event.Handler, _ interface{}) event.Response {
+				results[i] = atomic.AddInt32(&order, 1)
+				return 0
+			}, event.WithPriority(p))
+			<-bnd.Bound
+		}
+		<-b.Trigger(1, nil)
+		for i, p := range priorities {
+			for j, p2 := range priorities {
+				if p > p2 && results[i] > results[j] {
+					t.Fatalf("binding with priority %v called after binding with priority %v", p, p2)
+				}
+			}
+		}
+	})
+	t.Run("PersistsThroughReset", func(t *testing.T) {
+		b := event.NewBus(event.NewCallerMap())
+		var lowCalled int32
+		var highCalledFirst int32
+		b.PersistentBind(1, 0, func(ci event.CallerID, h event.Handler, _ interface{}) event.Response {
+			atomic.StoreInt32(&lowCalled, 1)
+			return 0
+		}, event.WithPriority(-1))
+		b.PersistentBind(1, 0, func(ci event.CallerID, h event.Handler, _ interface{}) event.Response {
+			if atomic.LoadInt32(&lowCalled) == 0 {
+				atomic.StoreInt32(&highCalledFirst, 1)
+			}
+			return 0
+		}, event.WithPriority(1))
+		// persistent bindings are recorded concurrently
+		time.Sleep(50 * time.Millisecond)
+		b.Reset()
+		<-b.Trigger(1, nil)
+		if highCalledFirst != 1 {
+			t.Fatal("persistent priority was not preserved through reset")
+		}
+	})
+}
+
+func TestBus_BindingCounts(t *testing.T) {
+	t.Run("Basic", func(t *testing.T) {
+		b := event.NewBus(event.NewCallerMap())
+		cid := b.GetCallerMap().Register(event.CallerID(0))
+		bnds := []event.Binding{
+			b.UnsafeBind(1, 0, func(event.CallerID, event.Handler, interface{}) event.Response { return 0 }),
+			b.UnsafeBind(1, cid, func(event.CallerID, event.Handler, interface{}) event.Response { return 0 }),
+			b.UnsafeBind(1, cid, func(event.CallerID, event.Handler, interface{}) event.Response { return 0 }),
+			b.UnsafeBind(2, cid, func(event.CallerID, event.Handler, interface{}) event.Response { return 0 }),
+		}
+		for _, bnd := range bnds {
+			<-bnd.Bound
+		}
+		<-bnds[3].Unbind()
+		counts := b.BindingCounts()
+		expected := map[event.UnsafeEventID]map[event.CallerID]int{
+			1: {0: 1, cid: 2},
+		}
+		if !reflect.DeepEqual(counts, expected) {
+			t.Fatal(expectedError("counts", expected, counts))
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package event
 
 import (
+	"reflect"
+	"sort"
 	"sync"
 )
 
@@ -28,13 +30,24 @@ type CallerMap struct {
 	highestID   CallerID
 	callersLock sync.RWMutex
 	callers     map[CallerID]Caller
+	parents     map[CallerID]CallerID
+	children    map[CallerID]map[CallerID]struct{} // the reverse of parents
+	cleanups    map[CallerID][]func()
+
+	components components
 }
 
 // NewCallerMap creates a caller map. A CallerMap
 // is not valid for use if not created via this function.
 func NewCallerMap() *CallerMap {
 	return &CallerMap{
-		callers: map[CallerID]Caller{},
+		callers:  map[CallerID]Caller{},
+		parents:  map[CallerID]CallerID{},
+		children: map[CallerID]map[CallerID]struct{}{},
+		cleanups: map[CallerID][]func(){},
+		components: components{
+			stores: make(map[reflect.Type]componentStorage),
+		},
 	}
 }
 
@@ -69,6 +82,20 @@ func (cm *CallerMap) GetEntity(id CallerID) Caller {
 	return cm.callers[id]
 }
 
+// IDs returns the IDs of all registered callers, in ascending order.
+func (cm *CallerMap) IDs() []CallerID {
+	cm.callersLock.RLock()
+	ids := make([]CallerID, 0, len(cm.callers))
+	for id := range cm.callers {
+		ids = append(ids, id)
+	}
+	cm.callersLock.RUnlock()
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
 // Has returns whether the given caller id is an initialized entity
 // within the caller map.
 func (cm *CallerMap) HasEntity(id CallerID) bool {
@@ -78,17 +105,64 @@ func (cm *CallerMap) HasEntity(id CallerID) bool {
 	return ok
 }
 
-// Remove removes an entity from the caller map.
+// Remove removes an entity from the caller map, along with its components and its relationships
+// to its parent and its children. Its cleanups are discarded without being run; see Destroy.
 func (cm *CallerMap) RemoveEntity(id CallerID) {
 	cm.callersLock.Lock()
 	delete(cm.callers, id)
+	cm.unparent(id)
+	delete(cm.cleanups, id)
+	for child := range cm.children[id] {
+		delete(cm.parents, child)
+	}
+	delete(cm.children, id)
 	cm.callersLock.Unlock()
+	cm.removeComponents(id)
+}
+
+// SetParent sets the parent of a caller, for the purpose of bubbling events with TriggerBubbling.
+// Setting a caller's parent to Global removes its parent.
+func (cm *CallerMap) SetParent(child, parent CallerID) {
+	cm.callersLock.Lock()
+	cm.unparent(child)
+	if parent != Global {
+		cm.parents[child] = parent
+		if cm.children[parent] == nil {
+			cm.children[parent] = map[CallerID]struct{}{}
+		}
+		cm.children[parent][child] = struct{}{}
+	}
+	cm.callersLock.Unlock()
+}
+
+// unparent removes a caller's parent. It must be called while holding the callers lock.
+func (cm *CallerMap) unparent(child CallerID) {
+	parent, ok := cm.parents[child]
+	if !ok {
+		return
+	}
+	delete(cm.parents, child)
+	delete(cm.children[parent], child)
+	if len(cm.children[parent]) == 0 {
+		delete(cm.children, parent)
+	}
+}
+
+// Parent returns the parent of a caller, or Global if it has no parent.
+func (cm *CallerMap) Parent(child CallerID) CallerID {
+	cm.callersLock.RLock()
+	defer cm.callersLock.RUnlock()
+	return cm.parents[child]
 }
 
-// Clear clears the caller map to forget all registered callers.
+// Clear clears the caller map to forget all registered callers and their components.
 func (cm *CallerMap) Clear() {
 	cm.callersLock.Lock()
 	cm.highestID = 0
 	cm.callers = map[CallerID]Caller{}
+	cm.parents = map[CallerID]CallerID{}
+	cm.children = map[CallerID]map[CallerID]struct{}{}
+	cm.cleanups = map[CallerID][]func(){}
 	cm.callersLock.Unlock()
+	cm.clearComponents()
 }
@@ -0,0 +1,211 @@
+package event
+
+import (
+	"reflect"
+	"sync"
+)
+
+// components stores typed components attached to callers, one store per component type.
+type components struct {
+	sync.RWMutex
+	stores map[reflect.Type]componentStorage
+}
+
+type componentStorage interface {
+	remove(CallerID)
+}
+
+// a componentStore keeps its components densely packed for fast iteration.
+type componentStore[T any] struct {
+	index  map[CallerID]int
+	ids    []CallerID
+	values []*T
+	// scratchPool holds *scratch[T]s, reused by Each and Query to snapshot this store
+	scratchPool sync.Pool
+}
+
+// a scratch is a snapshot of some of a store's components, taken so they can be iterated
+// over without holding the components lock.
+type scratch[T any] struct {
+	ids    []CallerID
+	values []*T
+}
+
+func (cs *componentStore[T]) getScratch() *scratch[T] {
+	if sc, ok := cs.scratchPool.Get().(*scratch[T]); ok {
+		return sc
+	}
+	return new(scratch[T])
+}
+
+// putScratch empties sc, so it does not keep removed components alive, and returns it to the pool.
+func (cs *componentStore[T]) putScratch(sc *scratch[T]) {
+	for i := range sc.values {
+		sc.values[i] = nil
+	}
+	sc.ids = sc.ids[:0]
+	sc.values = sc.values[:0]
+	cs.scratchPool.Put(sc)
+}
+
+func (cs *componentStore[T]) remove(cid CallerID) {
+	i, ok := cs.index[cid]
+	if !ok {
+		return
+	}
+	last := len(cs.ids) - 1
+	cs.ids[i] = cs.ids[last]
+	cs.values[i] = cs.values[last]
+	cs.index[cs.ids[i]] = i
+	cs.values[last] = nil
+	cs.ids = cs.ids[:last]
+	cs.values = cs.values[:last]
+	delete(cs.index, cid)
+}
+
+func (cs *componentStore[T]) get(cid CallerID) (*T, bool) {
+	i, ok := cs.index[cid]
+	if !ok {
+		return nil, false
+	}
+	return cs.values[i], true
+}
+
+// store returns the store for T, creating it if create is true. It must be called while
+// holding the components lock, and create must only be true while holding it for writing.
+func store[T any](cm *CallerMap, create bool) *componentStore[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if s, ok := cm.components.stores[typ]; ok {
+		return s.(*componentStore[T])
+	}
+	if !create {
+		return nil
+	}
+	s := &componentStore[T]{
+		index: make(map[CallerID]int),
+	}
+	cm.components.stores[typ] = s
+	return s
+}
+
+// AddComponent attaches a component to a caller, replacing any existing component of the same
+// type. It returns a pointer to the stored component, which remains valid until the component
+// is removed. Components are removed when their caller is removed or the caller map is cleared.
+func AddComponent[T any](cm *CallerMap, cid CallerID, component T) *T {
+	cm.components.Lock()
+	defer cm.components.Unlock()
+	s := store[T](cm, true)
+	if i, ok := s.index[cid]; ok {
+		*s.values[i] = component
+		return s.values[i]
+	}
+	s.index[cid] = len(s.ids)
+	s.ids = append(s.ids, cid)
+	s.values = append(s.values, &component)
+	return &component
+}
+
+// GetComponent returns a caller's component of type T, if it has one.
+func GetComponent[T any](cm *CallerMap, cid CallerID) (*T, bool) {
+	cm.components.RLock()
+	defer cm.components.RUnlock()
+	s := store[T](cm, false)
+	if s == nil {
+		return nil, false
+	}
+	return s.get(cid)
+}
+
+// RemoveComponent removes a caller's component of type T, returning whether it had one.
+func RemoveComponent[T any](cm *CallerMap, cid CallerID) bool {
+	cm.components.Lock()
+	defer cm.components.Unlock()
+	s := store[T](cm, false)
+	if s == nil {
+		return false
+	}
+	if _, ok := s.index[cid]; !ok {
+		return false
+	}
+	s.remove(cid)
+	return true
+}
+
+// Each calls fn for every caller with a component of type T.
+//
+// Callers are collected before fn is first called, so fn may safely add and remove components.
+// Callers whose components are added during iteration will not be visited, but callers whose
+// components are removed during iteration may still be. The slices callers are collected into
+// are reused across calls, so iterating does not allocate once warmed up.
+func Each[T any](cm *CallerMap, fn func(CallerID, *T)) {
+	cm.components.RLock()
+	s := store[T](cm, false)
+	if s == nil {
+		cm.components.RUnlock()
+		return
+	}
+	sc := s.getScratch()
+	sc.ids = append(sc.ids, s.ids...)
+	sc.values = append(sc.values, s.values...)
+	cm.components.RUnlock()
+
+	for i, cid := range sc.ids {
+		fn(cid, sc.values[i])
+	}
+	s.putScratch(sc)
+}
+
+// Query calls fn for every caller with both a component of type A and a component of type B.
+// See Each for what fn may do during iteration.
+func Query[A, B any](cm *CallerMap, fn func(CallerID, *A, *B)) {
+	cm.components.RLock()
+	sa := store[A](cm, false)
+	sb := store[B](cm, false)
+	if sa == nil || sb == nil {
+		cm.components.RUnlock()
+		return
+	}
+	// matched callers and their As are collected into A's scratch, and their Bs into B's
+	scA, scB := sa.getScratch(), sb.getScratch()
+	// iterate over whichever store is smaller
+	if len(sa.ids) <= len(sb.ids) {
+		for i, cid := range sa.ids {
+			if b, ok := sb.get(cid); ok {
+				scA.ids = append(scA.ids, cid)
+				scA.values = append(scA.values, sa.values[i])
+				scB.values = append(scB.values, b)
+			}
+		}
+	} else {
+		for i, cid := range sb.ids {
+			if a, ok := sa.get(cid); ok {
+				scA.ids = append(scA.ids, cid)
+				scA.values = append(scA.values, a)
+				scB.values = append(scB.values, sb.values[i])
+			}
+		}
+	}
+	cm.components.RUnlock()
+
+	for i, cid := range scA.ids {
+		fn(cid, scA.values[i], scB.values[i])
+	}
+	sa.putScratch(scA)
+	sb.putScratch(scB)
+}
+
+// removeComponents removes all of a caller's components.
+func (cm *CallerMap) removeComponents(cid CallerID) {
+	cm.components.Lock()
+	for _, s := range cm.components.stores {
+		s.remove(cid)
+	}
+	cm.components.Unlock()
+}
+
+// clearComponents removes all components from all callers.
+func (cm *CallerMap) clearComponents() {
+	cm.components.Lock()
+	cm.components.stores = make(map[reflect.Type]componentStorage)
+	cm.components.Unlock()
+}
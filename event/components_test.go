@@ -0,0 +1,111 @@
+package event_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+type health struct {
+	HP int
+}
+
+type velocity struct {
+	X, Y float64
+}
+
+func TestComponents(t *testing.T) {
+	t.Run("AddGetRemove", func(t *testing.T) {
+		cm := event.NewCallerMap()
+		cid := cm.Register(event.CallerID(0))
+		if _, ok := event.GetComponent[health](cm, cid); ok {
+			t.Fatal("expected no component before add")
+		}
+		hp := event.AddComponent(cm, cid, health{HP: 10})
+		hp.HP--
+		got, ok := event.GetComponent[health](cm, cid)
+		if !ok || got.HP != 9 {
+			t.Fatal(expectedError("health", 9, got))
+		}
+		event.AddComponent(cm, cid, health{HP: 20})
+		if got.HP != 20 {
+			t.Fatal(expectedError("replaced health", 20, got.HP))
+		}
+		if !event.RemoveComponent[health](cm, cid) {
+			t.Fatal("expected remove to report an existing component")
+		}
+		if event.RemoveComponent[health](cm, cid) {
+			t.Fatal("expected second remove to report no component")
+		}
+	})
+	t.Run("Query", func(t *testing.T) {
+		cm := event.NewCallerMap()
+		ids := make([]event.CallerID, 7)
+		for i := range ids {
+			ids[i] = cm.Register(event.CallerID(0))
+			if i%2 == 0 {
+				event.AddComponent(cm, ids[i], health{HP: i})
+			}
+			if i%3 == 0 {
+				event.AddComponent(cm, ids[i], velocity{X: float64(i)})
+			}
+		}
+		var healthy []event.CallerID
+		event.Each(cm, func(cid event.CallerID, _ *health) {
+			healthy = append(healthy, cid)
+		})
+		if len(healthy) != 4 {
+			t.Fatal(expectedError("health components", 4, len(healthy)))
+		}
+		var both []event.CallerID
+		event.Query(cm, func(cid event.CallerID, h *health, v *velocity) {
+			if float64(h.HP) != v.X {
+				t.Fatal(expectedError("matched velocity", h.HP, v.X))
+			}
+			// removing during iteration is allowed
+			cm.RemoveEntity(cid)
+			both = append(both, cid)
+		})
+		sort.Slice(both, func(i, j int) bool { return both[i] < both[j] })
+		if len(both) != 2 || both[0] != ids[0] || both[1] != ids[6] {
+			t.Fatal(expectedError("queried callers", []event.CallerID{ids[0], ids[6]}, both))
+		}
+		if _, ok := event.GetComponent[health](cm, ids[0]); ok {
+			t.Fatal("expected components to be removed with their caller")
+		}
+	})
+	t.Run("Clear", func(t *testing.T) {
+		cm := event.NewCallerMap()
+		cid := cm.Register(event.CallerID(0))
+		event.AddComponent(cm, cid, velocity{})
+		cm.Clear()
+		cid = cm.Register(event.CallerID(0))
+		if _, ok := event.GetComponent[velocity](cm, cid); ok {
+			t.Fatal("expected components to be cleared")
+		}
+	})
+	t.Run("Nested", func(t *testing.T) {
+		cm := event.NewCallerMap()
+		for i := 0; i < 3; i++ {
+			cid := cm.Register(event.CallerID(0))
+			event.AddComponent(cm, cid, health{HP: i})
+			event.AddComponent(cm, cid, velocity{X: float64(i)})
+		}
+		// iterations reuse their buffers, so nested iterations must not share them
+		pairs := 0
+		for n := 0; n < 2; n++ {
+			event.Each(cm, func(event.CallerID, *health) {
+				event.Query(cm, func(_ event.CallerID, inner *health, v *velocity) {
+					if float64(inner.HP) != v.X {
+						t.Fatal(expectedError("matched velocity", inner.HP, v.X))
+					}
+					pairs++
+				})
+			})
+		}
+		if pairs != 18 {
+			t.Fatal(expectedError("nested pairs", 18, pairs))
+		}
+	})
+}
@@ -1,6 +1,10 @@
 package event
 
 import (
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -8,22 +12,77 @@ import (
 // An UnsafeEventID is a non-typed eventID. EventIDs are just these, with type information attached.
 type UnsafeEventID int64
 
+// String returns the name this event was registered with, or if it was registered without a name,
+// its numeric ID.
+func (id UnsafeEventID) String() string {
+	if info, ok := LookupEvent(id); ok && info.Name != "" {
+		return info.Name
+	}
+	return strconv.FormatInt(int64(id), 10)
+}
+
 // A EventID represents an event associated with a given payload type.
 type EventID[T any] struct {
 	UnsafeEventID
 }
 
+// EventInfo describes a registered event.
+type EventInfo struct {
+	ID UnsafeEventID
+	// Name is the human readable name an event was registered with. It may be empty.
+	Name        string
+	PayloadType reflect.Type
+}
+
 var (
 	nextEventID int64
+
+	eventInfoLock sync.RWMutex
+	eventInfos    = map[UnsafeEventID]EventInfo{}
 )
 
 // RegisterEvent returns a unique ID to associate an event with. EventIDs not created through RegisterEvent are
 // not valid for use in type-safe bindings.
 func RegisterEvent[T any]() EventID[T] {
-	id := atomic.AddInt64(&nextEventID, 1)
+	return RegisterNamedEvent[T]("")
+}
+
+// RegisterNamedEvent acts like RegisterEvent, additionally associating a human readable name with the event
+// for use in debugging and logging. Names are not required to be unique.
+func RegisterNamedEvent[T any](name string) EventID[T] {
+	id := UnsafeEventID(atomic.AddInt64(&nextEventID, 1))
+	eventInfoLock.Lock()
+	eventInfos[id] = EventInfo{
+		ID:          id,
+		Name:        name,
+		PayloadType: reflect.TypeOf((*T)(nil)).Elem(),
+	}
+	eventInfoLock.Unlock()
 	return EventID[T]{
-		UnsafeEventID: UnsafeEventID(id),
+		UnsafeEventID: id,
+	}
+}
+
+// LookupEvent returns the details of a registered event.
+func LookupEvent(id UnsafeEventID) (EventInfo, bool) {
+	eventInfoLock.RLock()
+	defer eventInfoLock.RUnlock()
+	info, ok := eventInfos[id]
+	return info, ok
+}
+
+// Events returns the details of all registered events, in the order they were registered.
+func Events() []EventInfo {
+	eventInfoLock.RLock()
+	infos := make([]EventInfo, 0, len(eventInfos))
+	for _, info := range eventInfos {
+		infos = append(infos, info)
 	}
+	eventInfoLock.RUnlock()
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
 }
 
 // EnterPayload is the payload sent down to Enter bindings
@@ -35,5 +94,5 @@ type EnterPayload struct {
 
 var (
 	// Enter: the beginning of every logical frame.
-	Enter = RegisterEvent[EnterPayload]()
+	Enter = RegisterNamedEvent[EnterPayload]("Enter")
 )
@@ -0,0 +1,60 @@
+package event_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+func TestRegisterNamedEvent(t *testing.T) {
+	t.Run("Basic", func(t *testing.T) {
+		ev := event.RegisterNamedEvent[*event.EnterPayload]("test.Named")
+		info, ok := event.LookupEvent(ev.UnsafeEventID)
+		if !ok {
+			t.Fatal("registered event was not found")
+		}
+		if info.Name != "test.Named" {
+			t.Fatal(expectedError("name", "test.Named", info.Name))
+		}
+		if info.PayloadType != reflect.TypeOf(&event.EnterPayload{}) {
+			t.Fatal(expectedError("payload type", reflect.TypeOf(&event.EnterPayload{}), info.PayloadType))
+		}
+		if ev.String() != "test.Named" {
+			t.Fatal(expectedError("string", "test.Named", ev.String()))
+		}
+	})
+	t.Run("Unnamed", func(t *testing.T) {
+		ev := event.RegisterEvent[interface{}]()
+		info, ok := event.LookupEvent(ev.UnsafeEventID)
+		if !ok {
+			t.Fatal("registered event was not found")
+		}
+		if info.PayloadType.Kind() != reflect.Interface {
+			t.Fatal(expectedError("payload kind", reflect.Interface, info.PayloadType.Kind()))
+		}
+		if ev.String() == "" {
+			t.Fatal("unnamed event had empty string")
+		}
+	})
+}
+
+func TestEvents(t *testing.T) {
+	t.Run("Ordered", func(t *testing.T) {
+		ev1 := event.RegisterEvent[int]()
+		ev2 := event.RegisterEvent[int]()
+		infos := event.Events()
+		found := 0
+		for i, info := range infos {
+			if i > 0 && infos[i-1].ID >= info.ID {
+				t.Fatal("events were not sorted by ID")
+			}
+			if info.ID == ev1.UnsafeEventID || info.ID == ev2.UnsafeEventID {
+				found++
+			}
+		}
+		if found != 2 {
+			t.Fatal(expectedError("found events", 2, found))
+		}
+	})
+}
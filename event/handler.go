@@ -2,6 +2,10 @@ package event
 
 var (
 	_ Handler = &Bus{}
+	_ Stepper = &SyncBus{}
+
+	_ BindingCounter = &Bus{}
+	_ BindingCounter = &SyncBus{}
 )
 
 // Handler represents the necessary exported functions from an event.Bus
@@ -11,11 +15,19 @@ type Handler interface {
 	Reset()
 	TriggerForCaller(cid CallerID, event UnsafeEventID, data interface{}) <-chan struct{}
 	Trigger(event UnsafeEventID, data interface{}) <-chan struct{}
-	UnsafeBind(UnsafeEventID, CallerID, UnsafeBindable) Binding
+	TriggerBubbling(cid CallerID, event UnsafeEventID, data interface{}) <-chan struct{}
+	UnsafeBind(UnsafeEventID, CallerID, UnsafeBindable, ...BindOption) Binding
 	Unbind(Binding) <-chan struct{}
 	UnbindAllFrom(CallerID) <-chan struct{}
 	SetCallerMap(*CallerMap)
 	GetCallerMap() *CallerMap
-	PersistentBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable) Binding
+	PersistentBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable, opts ...BindOption) Binding
 	ClearPersistentBindings()
 }
+
+// A BindingCounter is a Handler which can report what bindings it holds, for debugging.
+type BindingCounter interface {
+	// BindingCounts returns, for each event with at least one binding, how many
+	// bindings each caller currently has to that event.
+	BindingCounts() map[UnsafeEventID]map[CallerID]int
+}
@@ -1,10 +1,17 @@
 package event
 
 import (
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
-type bindableList map[BindID]UnsafeBindable
+type bindable struct {
+	fn       UnsafeBindable
+	priority int
+}
+
+type bindableList map[BindID]bindable
 
 func (eb *Bus) getBindableList(eventID UnsafeEventID, callerID CallerID) bindableList {
 	if m := eb.bindingMap[eventID]; m == nil {
@@ -21,28 +28,73 @@ func (eb *Bus) getBindableList(eventID UnsafeEventID, callerID CallerID) bindabl
 	return bl
 }
 
-func (bus *Bus) trigger(binds bindableList, eventID UnsafeEventID, callerID CallerID, data interface{}) {
+// a triggerTarget is a single binding to be called by a trigger.
+type triggerTarget struct {
+	callerID CallerID
+	bindID   BindID
+	bindable
+}
+
+// trigger calls all bindings in the given caller map, in descending order of priority. Within each
+// priority, callers are triggered one after another, and the bindings of a single caller are called
+// concurrently. It returns whether any binding asked to stop propagation.
+func (bus *Bus) trigger(callerBinds map[CallerID]bindableList, eventID UnsafeEventID, data interface{}) (stopped bool) {
+	targets := make([]triggerTarget, 0, len(callerBinds))
+	for callerID, binds := range callerBinds {
+		for bindID, bnd := range binds {
+			targets = append(targets, triggerTarget{
+				callerID: callerID,
+				bindID:   bindID,
+				bindable: bnd,
+			})
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].priority != targets[j].priority {
+			return targets[i].priority > targets[j].priority
+		}
+		return targets[i].callerID < targets[j].callerID
+	})
 	wg := &sync.WaitGroup{}
-	wg.Add(len(binds))
-	for bindID, bnd := range binds {
-		bindID := bindID
-		bnd := bnd
-		go func() {
-			if callerID == Global || bus.callerMap.HasEntity(callerID) {
-				response := bnd(callerID, bus, data)
-				switch response {
-				case ResponseUnbindThisBinding:
-					// Q: Why does this call bus.Unbind when it already has the event index to delete?
-					// A: This goroutine does not own a write lock on the bus, and should therefore
-					//    not modify its contents. We do not have a simple way of promoting our read lock
-					//    to a write lock.
-					bus.Unbind(Binding{EventID: eventID, CallerID: callerID, BindID: bindID, busResetCount: bus.resetCount})
-				case ResponseUnbindThisCaller:
-					bus.UnbindAllFrom(callerID)
+	var stop int32
+	for start := 0; start < len(targets); {
+		end := start + 1
+		for end < len(targets) && targets[end].priority == targets[start].priority &&
+			targets[end].callerID == targets[start].callerID {
+			end++
+		}
+		wg.Add(end - start)
+		for _, tgt := range targets[start:end] {
+			tgt := tgt
+			go func() {
+				if bus.triggerTarget(tgt, eventID, data) {
+					atomic.StoreInt32(&stop, 1)
 				}
-			}
-			wg.Done()
-		}()
+				wg.Done()
+			}()
+		}
+		wg.Wait()
+		start = end
+	}
+	return atomic.LoadInt32(&stop) == 1
+}
+
+func (bus *Bus) triggerTarget(tgt triggerTarget, eventID UnsafeEventID, data interface{}) (stopped bool) {
+	if tgt.callerID != Global && !bus.callerMap.HasEntity(tgt.callerID) {
+		return false
+	}
+	response := tgt.fn(tgt.callerID, bus, data)
+	switch response {
+	case ResponseUnbindThisBinding:
+		// Q: Why does this call bus.Unbind when it already has the event index to delete?
+		// A: This goroutine does not own a write lock on the bus, and should therefore
+		//    not modify its contents. We do not have a simple way of promoting our read lock
+		//    to a write lock.
+		bus.Unbind(Binding{EventID: eventID, CallerID: tgt.callerID, BindID: tgt.bindID, busResetCount: bus.resetCount})
+	case ResponseUnbindThisCaller:
+		bus.UnbindAllFrom(tgt.callerID)
+	case ResponseStopPropagation:
+		return true
 	}
-	wg.Wait()
+	return false
 }
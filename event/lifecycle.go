@@ -0,0 +1,73 @@
+package event
+
+var (
+	// Registered is triggered globally when a caller is registered via Register. Its payload is
+	// the new caller's ID.
+	Registered = RegisterNamedEvent[CallerID]("event.Registered")
+	// Destroyed is triggered for a caller when it is destroyed via Destroy, before any of its
+	// cleanups run or its bindings are removed. Its payload is the destroyed caller's ID.
+	Destroyed = RegisterNamedEvent[CallerID]("event.Destroyed")
+)
+
+// Register registers c with h's caller map and triggers Registered, returning c's new ID. It does
+// not wait for Registered's bindings to run, so it is safe to call from within a binding.
+func Register(h Handler, c Caller) CallerID {
+	cid := h.GetCallerMap().Register(c)
+	TriggerOn(h, Registered, cid)
+	return cid
+}
+
+// Destroy removes a caller entirely: it triggers Destroyed for the caller, runs the caller's
+// cleanups in the reverse order they were added, unbinds all of its bindings, and removes it and
+// its components from h's caller map. The returned channel is closed once the caller is removed.
+//
+// Destroy does not block on h, so it is safe to call from within a binding. If h does not trigger
+// synchronously, the cleanups run on another goroutine once Destroyed's bindings have run.
+func Destroy(h Handler, cid CallerID) <-chan struct{} {
+	if cid == Global {
+		return closedCh
+	}
+	destroyed := TriggerForCallerOn(h, cid, Destroyed, cid)
+	select {
+	case <-destroyed:
+		// h triggered synchronously, e.g. a SyncBus
+		destroy(h, cid)
+		return closedCh
+	default:
+	}
+	ch := make(chan struct{})
+	go func() {
+		<-destroyed
+		destroy(h, cid)
+		close(ch)
+	}()
+	return ch
+}
+
+// destroy runs cid's cleanups and removes it. It does not wait for cid's bindings to be unbound,
+// but once cid is removed from h's caller map its bindings will not be called.
+func destroy(h Handler, cid CallerID) {
+	cm := h.GetCallerMap()
+	cleanups := cm.takeCleanups(cid)
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+	h.UnbindAllFrom(cid)
+	cm.RemoveEntity(cid)
+}
+
+// AddCleanup registers fn to be called when cid is destroyed via Destroy, e.g. to undraw its
+// renderables or remove its collision spaces.
+func (cm *CallerMap) AddCleanup(cid CallerID, fn func()) {
+	cm.callersLock.Lock()
+	cm.cleanups[cid] = append(cm.cleanups[cid], fn)
+	cm.callersLock.Unlock()
+}
+
+func (cm *CallerMap) takeCleanups(cid CallerID) []func() {
+	cm.callersLock.Lock()
+	defer cm.callersLock.Unlock()
+	cleanups := cm.cleanups[cid]
+	delete(cm.cleanups, cid)
+	return cleanups
+}
@@ -0,0 +1,83 @@
+package event_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+func TestDestroy(t *testing.T) {
+	t.Run("Order", func(t *testing.T) {
+		b := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+		cm := b.GetCallerMap()
+		var registered []event.CallerID
+		event.GlobalBind(b, event.Registered, func(cid event.CallerID) event.Response {
+			registered = append(registered, cid)
+			return 0
+		})
+		cid := event.Register(b, event.CallerID(0))
+		if !reflect.DeepEqual(registered, []event.CallerID{cid}) {
+			t.Fatal(expectedError("registered", []event.CallerID{cid}, registered))
+		}
+
+		var steps []string
+		event.Bind(b, event.Destroyed, cid, func(_ event.CallerID, _ event.CallerID) event.Response {
+			steps = append(steps, "destroyed")
+			return 0
+		})
+		cm.AddCleanup(cid, func() {
+			steps = append(steps, "first")
+		})
+		cm.AddCleanup(cid, func() {
+			steps = append(steps, "second")
+		})
+		event.AddComponent(cm, cid, 1)
+		event.Destroy(b, cid)
+
+		expected := []string{"destroyed", "second", "first"}
+		if !reflect.DeepEqual(steps, expected) {
+			t.Fatal(expectedError("steps", expected, steps))
+		}
+		if cm.HasEntity(cid) {
+			t.Fatal("expected destroyed caller to be removed")
+		}
+		if _, ok := event.GetComponent[int](cm, cid); ok {
+			t.Fatal("expected destroyed caller's components to be removed")
+		}
+		if counts := b.BindingCounts(); len(counts[event.Destroyed.UnsafeEventID]) != 0 {
+			t.Fatal(expectedError("destroyed bindings", 0, len(counts[event.Destroyed.UnsafeEventID])))
+		}
+		// cleanups should only run once
+		event.Destroy(b, cid)
+		if len(steps) != 3 {
+			t.Fatal(expectedError("steps", 3, len(steps)))
+		}
+	})
+	t.Run("FromBinding", func(t *testing.T) {
+		b := event.NewBus(event.NewCallerMap())
+		cid := event.Register(b, event.CallerID(0))
+		ev := event.RegisterEvent[struct{}]()
+		cleaned := make(chan struct{})
+		b.GetCallerMap().AddCleanup(cid, func() {
+			close(cleaned)
+		})
+		bnd := event.GlobalBind(b, ev, func(struct{}) event.Response {
+			event.Destroy(b, cid)
+			event.Register(b, event.CallerID(0))
+			return 0
+		})
+		<-bnd.Bound
+		select {
+		case <-event.TriggerOn(b, ev, struct{}{}):
+		case <-time.After(time.Second):
+			t.Fatal("expected destroying from within a binding not to block the bus")
+		}
+		select {
+		case <-cleaned:
+		case <-time.After(time.Second):
+			t.Fatal("expected cleanups to run")
+		}
+	})
+}
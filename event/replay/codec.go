@@ -0,0 +1,117 @@
+package replay
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/key"
+	"github.com/oakmound/oak/v4/mouse"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// A Codec converts the payloads of an event to and from bytes.
+type Codec[T any] interface {
+	Encode(T) ([]byte, error)
+	Decode([]byte) (T, error)
+}
+
+// JSONCodec encodes payloads with encoding/json.
+type JSONCodec[T any] struct{}
+
+// Encode marshals t as json.
+func (JSONCodec[T]) Encode(t T) ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// Decode unmarshals data as json into a T.
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var t T
+	err := json.Unmarshal(data, &t)
+	return t, err
+}
+
+// untypedCodec wraps a Codec[T] for storage alongside codecs of other types.
+type untypedCodec struct {
+	encode func(interface{}) ([]byte, error)
+	decode func([]byte) (interface{}, error)
+}
+
+// Codecs is a registry of payload codecs, keyed by event.
+type Codecs struct {
+	lock   sync.RWMutex
+	codecs map[event.UnsafeEventID]untypedCodec
+}
+
+// NewCodecs creates an empty codec registry.
+func NewCodecs() *Codecs {
+	return &Codecs{
+		codecs: make(map[event.UnsafeEventID]untypedCodec),
+	}
+}
+
+// RegisterCodec sets the codec used for payloads of the given event, replacing any existing codec
+// for that event.
+func RegisterCodec[T any](cs *Codecs, ev event.EventID[T], c Codec[T]) {
+	cs.lock.Lock()
+	cs.codecs[ev.UnsafeEventID] = untypedCodec{
+		encode: func(payload interface{}) ([]byte, error) {
+			typed, ok := payload.(T)
+			if !ok {
+				return nil, oakerr.InvalidInput{InputName: "payload"}
+			}
+			return c.Encode(typed)
+		},
+		decode: func(data []byte) (interface{}, error) {
+			return c.Decode(data)
+		},
+	}
+	cs.lock.Unlock()
+}
+
+// Encode encodes a payload for the given event. If no codec is registered for the event, ok will be false.
+func (cs *Codecs) Encode(ev event.UnsafeEventID, payload interface{}) (data []byte, ok bool, err error) {
+	cs.lock.RLock()
+	c, ok := cs.codecs[ev]
+	cs.lock.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	data, err = c.encode(payload)
+	return data, true, err
+}
+
+// Decode decodes a payload for the given event. If no codec is registered for the event, ok will be false.
+func (cs *Codecs) Decode(ev event.UnsafeEventID, data []byte) (payload interface{}, ok bool, err error) {
+	cs.lock.RLock()
+	c, ok := cs.codecs[ev]
+	cs.lock.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	payload, err = c.decode(data)
+	return payload, true, err
+}
+
+// DefaultCodecs has codecs registered for the keyboard and mouse events triggered by oak windows
+// for OS input.
+var DefaultCodecs = NewCodecs()
+
+func init() {
+	for _, ev := range []event.EventID[key.Event]{key.AnyDown, key.AnyUp, key.AnyHeld} {
+		RegisterCodec[key.Event](DefaultCodecs, ev, JSONCodec[key.Event]{})
+	}
+	for _, ev := range inputMouseEvents {
+		RegisterCodec[*mouse.Event](DefaultCodecs, ev, JSONCodec[*mouse.Event]{})
+	}
+}
+
+// inputMouseEvents are those mouse events oak windows trigger directly from OS input; other mouse
+// events are derived from these.
+var inputMouseEvents = []event.EventID[*mouse.Event]{
+	mouse.Press,
+	mouse.Release,
+	mouse.ScrollDown,
+	mouse.ScrollUp,
+	mouse.Drag,
+}
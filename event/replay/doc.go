@@ -0,0 +1,3 @@
+// Package replay records the events triggered on an event handler and replays them later,
+// frame by frame, to reproduce a session of play.
+package replay
@@ -0,0 +1,83 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/fileutil"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// FormatVersion is the version of the recording format written by Recorders.
+const FormatVersion = 1
+
+// A Record is a single recorded trigger.
+type Record struct {
+	// Epoch counts how many times the frame count reported by enter events had restarted,
+	// e.g. due to a scene change, when this record was made.
+	Epoch int `json:"epoch"`
+	// Frame is the FramesElapsed of the last enter event before this record was made.
+	Frame    int                 `json:"frame"`
+	EventID  event.UnsafeEventID `json:"event"`
+	CallerID event.CallerID      `json:"caller"`
+	// Payload is the encoded payload of the trigger. It will be nil if the payload was nil or
+	// if no codec was registered for the event.
+	Payload []byte `json:"payload,omitempty"`
+	// Bubbling is whether the event was triggered via TriggerBubbling.
+	Bubbling bool `json:"bubbling,omitempty"`
+}
+
+// a header is written as the first line of every recording.
+type header struct {
+	Version int `json:"version"`
+}
+
+// ReadRecords reads a recording, as written by a Recorder, from r.
+func ReadRecords(r io.Reader) ([]Record, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var hdr header
+	if err := dec.Decode(&hdr); err != nil {
+		return nil, err
+	}
+	if hdr.Version != FormatVersion {
+		return nil, oakerr.UnsupportedFormat{Format: "recording version " + strconv.Itoa(hdr.Version)}
+	}
+	records := []Record{}
+	for {
+		var rec Record
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// LoadRecords reads a recording from the given file via fileutil.
+func LoadRecords(file string) ([]Record, error) {
+	r, err := fileutil.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ReadRecords(r)
+}
+
+// a frameCounter tracks the frame and epoch that enter events indicate it currently is.
+type frameCounter struct {
+	epoch int
+	frame int
+}
+
+func (fc *frameCounter) observe(ep event.EnterPayload) {
+	if ep.FramesElapsed < fc.frame {
+		fc.epoch++
+	}
+	fc.frame = ep.FramesElapsed
+}
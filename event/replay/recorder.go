@@ -0,0 +1,120 @@
+package replay
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+// A Recorder is an event.Handler which records every trigger made through it before passing that
+// trigger on to the handler it wraps. Enter events are not recorded; instead the recorder binds to
+// enter events on the wrapped handler to note which frame each trigger was made on.
+//
+// Triggers made with the handler passed into a binding's callback go directly to the wrapped handler,
+// and are not recorded.
+type Recorder struct {
+	event.Handler
+
+	codecs *Codecs
+
+	mutex   sync.Mutex
+	enc     *json.Encoder
+	counter frameCounter
+	err     error
+}
+
+// NewRecorder creates a Recorder wrapping h, writing records to w and encoding payloads with the codecs
+// registered in codecs. If codecs is nil, DefaultCodecs will be used.
+func NewRecorder(h event.Handler, w io.Writer, codecs *Codecs) (*Recorder, error) {
+	if codecs == nil {
+		codecs = DefaultCodecs
+	}
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(header{Version: FormatVersion}); err != nil {
+		return nil, err
+	}
+	r := &Recorder{
+		Handler: h,
+		codecs:  codecs,
+		enc:     enc,
+	}
+	// observe frames before any other enter bindings can trigger something to record
+	h.PersistentBind(event.Enter.UnsafeEventID, event.Global, func(_ event.CallerID, _ event.Handler, payload interface{}) event.Response {
+		if ep, ok := payload.(event.EnterPayload); ok {
+			r.mutex.Lock()
+			r.counter.observe(ep)
+			r.mutex.Unlock()
+		}
+		return event.ResponseNone
+	}, event.WithPriority(math.MaxInt))
+	return r, nil
+}
+
+// Trigger records, then triggers, an event.
+func (r *Recorder) Trigger(eventID event.UnsafeEventID, data interface{}) <-chan struct{} {
+	r.record(event.Global, eventID, data)
+	return r.Handler.Trigger(eventID, data)
+}
+
+// TriggerForCaller records, then triggers, an event for a caller.
+func (r *Recorder) TriggerForCaller(callerID event.CallerID, eventID event.UnsafeEventID, data interface{}) <-chan struct{} {
+	r.record(callerID, eventID, data)
+	return r.Handler.TriggerForCaller(callerID, eventID, data)
+}
+
+// TriggerBubbling records, then triggers, an event bubbling up from a caller.
+func (r *Recorder) TriggerBubbling(callerID event.CallerID, eventID event.UnsafeEventID, data interface{}) <-chan struct{} {
+	r.recordBubbling(callerID, eventID, data, true)
+	return r.Handler.TriggerBubbling(callerID, eventID, data)
+}
+
+// Err returns the first error encountered encoding or writing a record. Once an error has been
+// encountered, the recorder will stop recording.
+func (r *Recorder) Err() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(callerID event.CallerID, eventID event.UnsafeEventID, data interface{}) {
+	r.recordBubbling(callerID, eventID, data, false)
+}
+
+func (r *Recorder) recordBubbling(callerID event.CallerID, eventID event.UnsafeEventID, data interface{}, bubbling bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		return
+	}
+	if eventID == event.Enter.UnsafeEventID {
+		return
+	}
+	rec := Record{
+		Epoch:    r.counter.epoch,
+		Frame:    r.counter.frame,
+		EventID:  eventID,
+		CallerID: callerID,
+		Bubbling: bubbling,
+	}
+	if data != nil {
+		payload, _, err := r.codecs.Encode(eventID, data)
+		if err != nil {
+			r.err = err
+			return
+		}
+		rec.Payload = payload
+	}
+	r.err = r.enc.Encode(rec)
+}
+
+// BindingCounts calls BindingCounts on the wrapped handler, if it is an event.BindingCounter.
+// Otherwise it returns nil.
+func (r *Recorder) BindingCounts() map[event.UnsafeEventID]map[event.CallerID]int {
+	if bc, ok := r.Handler.(event.BindingCounter); ok {
+		return bc.BindingCounts()
+	}
+	return nil
+}
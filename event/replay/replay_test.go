@@ -0,0 +1,130 @@
+package replay_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/event/replay"
+	"github.com/oakmound/oak/v4/key"
+	"github.com/oakmound/oak/v4/mouse"
+)
+
+var testEvent = event.RegisterEvent[string]()
+
+type inputs struct {
+	downs []key.Event
+	mice  []mouse.Event
+}
+
+func (in *inputs) TriggerKeyDown(e key.Event)       { in.downs = append(in.downs, e) }
+func (in *inputs) TriggerKeyUp(key.Event)           {}
+func (in *inputs) TriggerKeyHeld(key.Event)         {}
+func (in *inputs) TriggerMouseEvent(me mouse.Event) { in.mice = append(in.mice, me) }
+
+func TestRecordAndReplay(t *testing.T) {
+	codecs := replay.NewCodecs()
+	replay.RegisterCodec[string](codecs, testEvent, replay.JSONCodec[string]{})
+	replay.RegisterCodec[key.Event](codecs, key.AnyDown, replay.JSONCodec[key.Event]{})
+	replay.RegisterCodec[*mouse.Event](codecs, mouse.Press, replay.JSONCodec[*mouse.Event]{})
+
+	buf := &bytes.Buffer{}
+	bus := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+	rec, err := replay.NewRecorder(bus, buf, codecs)
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	bus.Step()
+	event.TriggerOn(rec, testEvent, "a")
+	bus.Step()
+	bus.Step()
+	event.TriggerOn(rec, key.AnyDown, key.Event{Code: key.A})
+	me := mouse.NewEvent(1, 2, mouse.ButtonLeft, mouse.Press)
+	event.TriggerOn(rec, mouse.Press, &me)
+	event.TriggerOn(rec, testEvent, "b")
+	// a scene change restarts frame counts
+	event.TriggerOn(bus, event.Enter, event.EnterPayload{FramesElapsed: 0})
+	event.TriggerOn(rec, testEvent, "c")
+	if err := rec.Err(); err != nil {
+		t.Fatalf("recorder failed: %v", err)
+	}
+
+	records, err := replay.ReadRecords(buf)
+	if err != nil {
+		t.Fatalf("failed to read records: %v", err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, got %v", len(records))
+	}
+	expectedFrames := [][2]int{{0, 0}, {0, 2}, {0, 2}, {0, 2}, {1, 0}}
+	for i, r := range records {
+		if r.Epoch != expectedFrames[i][0] || r.Frame != expectedFrames[i][1] {
+			t.Fatalf("record %d: expected epoch/frame %v, got %v/%v", i, expectedFrames[i], r.Epoch, r.Frame)
+		}
+	}
+
+	replayBus := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+	got := []string{}
+	event.GlobalBind(replayBus, testEvent, func(s string) event.Response {
+		got = append(got, s)
+		return 0
+	})
+	in := &inputs{}
+	rp := replay.NewReplayer(replayBus, records, codecs)
+	rp.Retrigger(testEvent.UnsafeEventID)
+	rp.RouteInputs(in)
+	rp.Start()
+
+	replayBus.Step()
+	if !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("expected only first record replayed, got %v", got)
+	}
+	replayBus.Step()
+	replayBus.Step()
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("expected first frames replayed, got %v", got)
+	}
+	if len(in.downs) != 1 || in.downs[0].Code != key.A {
+		t.Fatalf("expected key down replayed, got %v", in.downs)
+	}
+	if len(in.mice) != 1 || in.mice[0].X() != 1 || in.mice[0].Y() != 2 || in.mice[0].EventType != mouse.Press {
+		t.Fatalf("expected mouse press replayed, got %v", in.mice)
+	}
+	select {
+	case <-rp.Done():
+		t.Fatal("replay finished early")
+	default:
+	}
+	replayBus.Reset()
+	rp.Advance(event.EnterPayload{FramesElapsed: 0})
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		// the binding on testEvent was removed by Reset
+		t.Fatalf("expected reset binding not to be called, got %v", got)
+	}
+	select {
+	case <-rp.Done():
+	default:
+		t.Fatal("replay did not finish")
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	t.Run("BadVersion", func(t *testing.T) {
+		_, err := replay.ReadRecords(strings.NewReader(`{"version":1000}`))
+		if err == nil {
+			t.Fatal("expected error reading unsupported version")
+		}
+	})
+	t.Run("Empty", func(t *testing.T) {
+		records, err := replay.ReadRecords(strings.NewReader(`{"version":1}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(records) != 0 {
+			t.Fatalf("expected no records, got %v", records)
+		}
+	})
+}
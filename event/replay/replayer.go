@@ -0,0 +1,173 @@
+package replay
+
+import (
+	"sync"
+
+	"github.com/oakmound/oak/v4/dlog"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/key"
+	"github.com/oakmound/oak/v4/mouse"
+)
+
+// A Route replays a single record, given its decoded payload.
+type Route func(callerID event.CallerID, payload interface{})
+
+// An InputTriggerer can trigger emulated OS input. oak.Window is an InputTriggerer.
+type InputTriggerer interface {
+	TriggerKeyDown(key.Event)
+	TriggerKeyUp(key.Event)
+	TriggerKeyHeld(key.Event)
+	TriggerMouseEvent(mouse.Event)
+}
+
+// A Replayer replays recorded triggers on the frames they were recorded on. Only records whose events
+// have been given a route, e.g. via Route, Retrigger, or RouteInputs, are replayed.
+//
+// Event IDs are only stable from one run of a program to the next if events are registered in the same
+// order; events registered as package level variables are, but events registered lazily, like key.Down,
+// may not be.
+type Replayer struct {
+	handler event.Handler
+	codecs  *Codecs
+	records []Record
+
+	mutex  sync.Mutex
+	routes map[event.UnsafeEventID]Route
+	// retriggered tracks which routes were set by Retrigger, so bubbling records can bubble again
+	retriggered map[event.UnsafeEventID]bool
+	next        int
+	counter     frameCounter
+	done        chan struct{}
+	doneOnce    sync.Once
+}
+
+// NewReplayer creates a Replayer of records against h. Payloads will be decoded with the codecs registered
+// in codecs; if codecs is nil, DefaultCodecs will be used.
+func NewReplayer(h event.Handler, records []Record, codecs *Codecs) *Replayer {
+	if codecs == nil {
+		codecs = DefaultCodecs
+	}
+	return &Replayer{
+		handler: h,
+		codecs:  codecs,
+		records: records,
+		routes:  make(map[event.UnsafeEventID]Route),
+
+		retriggered: make(map[event.UnsafeEventID]bool),
+		done:        make(chan struct{}),
+	}
+}
+
+// Route sets how records of the given event should be replayed.
+func (rp *Replayer) Route(eventID event.UnsafeEventID, route Route) {
+	rp.mutex.Lock()
+	rp.routes[eventID] = route
+	delete(rp.retriggered, eventID)
+	rp.mutex.Unlock()
+}
+
+// Retrigger causes records of the given events to be triggered again, as they were recorded, on
+// the replayer's handler.
+func (rp *Replayer) Retrigger(eventIDs ...event.UnsafeEventID) {
+	for _, eventID := range eventIDs {
+		eventID := eventID
+		rp.Route(eventID, func(callerID event.CallerID, payload interface{}) {
+			<-rp.handler.TriggerForCaller(callerID, eventID, payload)
+		})
+		rp.mutex.Lock()
+		rp.retriggered[eventID] = true
+		rp.mutex.Unlock()
+	}
+}
+
+// RouteInputs causes recorded keyboard and mouse input to be replayed through in, e.g. through
+// an oak.Window's TriggerKeyDown and TriggerMouseEvent. Only the events a window triggers directly
+// from OS input are routed; events derived from those, like key.Down(code) or mouse.Click, will be
+// triggered again by in.
+func (rp *Replayer) RouteInputs(in InputTriggerer) {
+	rp.Route(key.AnyDown.UnsafeEventID, func(_ event.CallerID, payload interface{}) {
+		in.TriggerKeyDown(payload.(key.Event))
+	})
+	rp.Route(key.AnyUp.UnsafeEventID, func(_ event.CallerID, payload interface{}) {
+		in.TriggerKeyUp(payload.(key.Event))
+	})
+	rp.Route(key.AnyHeld.UnsafeEventID, func(_ event.CallerID, payload interface{}) {
+		in.TriggerKeyHeld(payload.(key.Event))
+	})
+	for _, ev := range inputMouseEvents {
+		rp.Route(ev.UnsafeEventID, func(_ event.CallerID, payload interface{}) {
+			me := *(payload.(*mouse.Event))
+			me.StopPropagation = false
+			in.TriggerMouseEvent(me)
+		})
+	}
+}
+
+// Start binds the replayer to the enter events of its handler. The binding persists through handler
+// resets, so replays may span multiple scenes.
+func (rp *Replayer) Start() event.Binding {
+	return rp.handler.PersistentBind(event.Enter.UnsafeEventID, event.Global, func(_ event.CallerID, _ event.Handler, payload interface{}) event.Response {
+		ep, ok := payload.(event.EnterPayload)
+		if !ok {
+			return event.ResponseNone
+		}
+		if rp.Advance(ep) {
+			return event.ResponseUnbindThisBinding
+		}
+		return event.ResponseNone
+	})
+}
+
+// Advance replays all records up to and including the frame of the given enter event. It returns
+// whether all records have been replayed. Start calls Advance on each enter event; Advance only needs
+// to be called manually if Start is not used.
+func (rp *Replayer) Advance(ep event.EnterPayload) (done bool) {
+	rp.mutex.Lock()
+	rp.counter.observe(ep)
+	for rp.next < len(rp.records) {
+		rec := rp.records[rp.next]
+		if rec.Epoch > rp.counter.epoch || (rec.Epoch == rp.counter.epoch && rec.Frame > rp.counter.frame) {
+			break
+		}
+		rp.next++
+		route, ok := rp.routes[rec.EventID]
+		if !ok {
+			continue
+		}
+		var payload interface{}
+		if rec.Payload != nil {
+			var err error
+			payload, ok, err = rp.codecs.Decode(rec.EventID, rec.Payload)
+			if err != nil {
+				dlog.Error("failed to decode replayed payload:", err)
+				continue
+			}
+			if !ok {
+				dlog.Error("no codec registered for replayed event", rec.EventID)
+				continue
+			}
+		}
+		// routes may trigger events which cause this replayer to advance again
+		bubbling := rec.Bubbling && rp.retriggered[rec.EventID]
+		rp.mutex.Unlock()
+		if bubbling {
+			<-rp.handler.TriggerBubbling(rec.CallerID, rec.EventID, payload)
+		} else {
+			route(rec.CallerID, payload)
+		}
+		rp.mutex.Lock()
+	}
+	done = rp.next >= len(rp.records)
+	rp.mutex.Unlock()
+	if done {
+		rp.doneOnce.Do(func() {
+			close(rp.done)
+		})
+	}
+	return done
+}
+
+// Done returns a channel which is closed once every record has been replayed.
+func (rp *Replayer) Done() <-chan struct{} {
+	return rp.done
+}
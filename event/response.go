@@ -13,4 +13,8 @@ const (
 	ResponseUnbindThisBinding
 	// ResponseUnbindThisCaller unbinds all of a caller's bindings when returned from any binding.
 	ResponseUnbindThisCaller
+	// ResponseStopPropagation stops an event triggered via TriggerBubbling from being
+	// delivered to the parents of the caller whose binding returned it. Other bindings
+	// of that caller will still be called.
+	ResponseStopPropagation
 )
@@ -0,0 +1,301 @@
+package event
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// A SyncBus is a Handler which, unlike Bus, dispatches triggers synchronously on the
+// calling goroutine, calling bindings of the same priority in the order they were bound. Bind and unbind
+// calls also take effect immediately. Enter events are not driven by a ticker, but
+// manually through Step, each step reporting the same fixed frame delay.
+//
+// Together, this makes the order in which bindings run reproducible from one run to
+// the next, for deterministic tests, lockstep simulations, and input replays.
+type SyncBus struct {
+	nextBindID BindID
+
+	// resetCount serves the same purpose as Bus.resetCount
+	resetCount         int64
+	bindings           map[UnsafeEventID][]*syncBinding
+	persistentBindings []persistentBinding
+
+	callerMap *CallerMap
+
+	frameDelay    time.Duration
+	framesElapsed int
+	stepMutex     sync.Mutex
+
+	mutex sync.Mutex
+}
+
+type syncBinding struct {
+	bindID   BindID
+	callerID CallerID
+	fn       UnsafeBindable
+	priority int
+	unbound  bool
+}
+
+// A Stepper is a Handler which is expected to have its Enter events triggered manually,
+// via Step, rather than by EnterLoop.
+type Stepper interface {
+	Handler
+	// Step triggers a single Enter event.
+	Step()
+}
+
+// closedCh is returned by synchronous operations that have already completed.
+var closedCh = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// NewSyncBus returns an empty synchronous event bus with an assigned caller map. If nil
+// is provided, the caller map used will be DefaultCallerMap. Each call to Step will report
+// frameDelay as the time elapsed since the last frame.
+func NewSyncBus(callerMap *CallerMap, frameDelay time.Duration) *SyncBus {
+	if callerMap == nil {
+		callerMap = DefaultCallerMap
+	}
+	return &SyncBus{
+		bindings:   make(map[UnsafeEventID][]*syncBinding),
+		callerMap:  callerMap,
+		frameDelay: frameDelay,
+	}
+}
+
+// SetCallerMap updates a bus to use a specific set of callers.
+func (bus *SyncBus) SetCallerMap(cm *CallerMap) {
+	bus.callerMap = cm
+}
+
+// GetCallerMap returns this bus's caller map.
+func (bus *SyncBus) GetCallerMap() *CallerMap {
+	return bus.callerMap
+}
+
+// UnsafeBind registers a callback function to be called whenever the provided event is triggered
+// against this bus. The binding is available to be triggered as soon as this call returns; if it
+// is bound from within a triggered callback, it will not be called by that same trigger.
+func (bus *SyncBus) UnsafeBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable, opts ...BindOption) Binding {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	return bus.bind(eventID, callerID, fn, NewBindOptions(opts...))
+}
+
+// bind must be called while holding the bus's lock.
+func (bus *SyncBus) bind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable, bo BindOptions) Binding {
+	bus.nextBindID++
+	sb := &syncBinding{
+		bindID:   bus.nextBindID,
+		callerID: callerID,
+		fn:       fn,
+		priority: bo.Priority,
+	}
+	// keep bindings sorted by descending priority, then by bind order
+	bs := bus.bindings[eventID]
+	i := sort.Search(len(bs), func(i int) bool {
+		return bs[i].priority < sb.priority
+	})
+	bs = append(bs, nil)
+	copy(bs[i+1:], bs[i:])
+	bs[i] = sb
+	bus.bindings[eventID] = bs
+	return Binding{
+		Handler:       bus,
+		EventID:       eventID,
+		CallerID:      callerID,
+		BindID:        bus.nextBindID,
+		Bound:         closedCh,
+		busResetCount: bus.resetCount,
+	}
+}
+
+// PersistentBind calls UnsafeBind, and causes UnsafeBind to be called with these inputs when the
+// bus is Reset.
+func (bus *SyncBus) PersistentBind(eventID UnsafeEventID, callerID CallerID, fn UnsafeBindable, opts ...BindOption) Binding {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bo := NewBindOptions(opts...)
+	bus.persistentBindings = append(bus.persistentBindings, persistentBinding{
+		eventID:  eventID,
+		callerID: callerID,
+		fn:       fn,
+		opts:     bo,
+	})
+	return bus.bind(eventID, callerID, fn, bo)
+}
+
+// ClearPersistentBindings removes all persistent bindings. It will not unbind them
+// from the bus, but they will not be bound following the next bus reset.
+func (bus *SyncBus) ClearPersistentBindings() {
+	bus.mutex.Lock()
+	bus.persistentBindings = bus.persistentBindings[:0]
+	bus.mutex.Unlock()
+}
+
+// Unbind unregisters a binding from the bus. If called from within a triggered callback, the
+// unbound binding will not be called by the remainder of that trigger.
+func (bus *SyncBus) Unbind(loc Binding) <-chan struct{} {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.resetCount != loc.busResetCount {
+		// This binding is not valid for this bus (in this state)
+		return closedCh
+	}
+	bus.filter(func(eventID UnsafeEventID, sb *syncBinding) bool {
+		return eventID == loc.EventID && sb.bindID == loc.BindID
+	})
+	return closedCh
+}
+
+// UnbindAllFrom unbinds all bindings currently bound to the provided caller via ID.
+func (bus *SyncBus) UnbindAllFrom(c CallerID) <-chan struct{} {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.filter(func(_ UnsafeEventID, sb *syncBinding) bool {
+		return sb.callerID == c
+	})
+	return closedCh
+}
+
+// filter removes all bindings matching the provided function, preserving the order of those
+// remaining. It must be called while holding the bus's lock.
+func (bus *SyncBus) filter(remove func(UnsafeEventID, *syncBinding) bool) {
+	for eventID, bs := range bus.bindings {
+		kept := bs[:0]
+		for _, sb := range bs {
+			if remove(eventID, sb) {
+				sb.unbound = true
+				continue
+			}
+			kept = append(kept, sb)
+		}
+		// clear the tail so removed bindings can be collected
+		for i := len(kept); i < len(bs); i++ {
+			bs[i] = nil
+		}
+		bus.bindings[eventID] = kept
+	}
+}
+
+// BindingCounts returns, for each event with at least one binding, how many
+// bindings each caller currently has to that event.
+func (bus *SyncBus) BindingCounts() map[UnsafeEventID]map[CallerID]int {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	counts := make(map[UnsafeEventID]map[CallerID]int)
+	for eventID, bs := range bus.bindings {
+		if len(bs) == 0 {
+			continue
+		}
+		counts[eventID] = make(map[CallerID]int)
+		for _, sb := range bs {
+			counts[eventID][sb.callerID]++
+		}
+	}
+	return counts
+}
+
+// Reset unbinds all present, non-persistent bindings on the bus, and rebinds persistent
+// bindings in the order they were originally bound. It does not reset the frame count
+// reported by Step.
+func (bus *SyncBus) Reset() {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.resetCount++
+	for _, bs := range bus.bindings {
+		for _, sb := range bs {
+			sb.unbound = true
+		}
+	}
+	bus.bindings = make(map[UnsafeEventID][]*syncBinding)
+	for _, pb := range bus.persistentBindings {
+		bus.bind(pb.eventID, pb.callerID, pb.fn, pb.opts)
+	}
+}
+
+// Trigger calls all bindables attached to the given event, with the passed in data, in
+// descending order of priority, and then in the order they were bound. It returns once all bindables have been called; the returned channel
+// is always closed.
+func (bus *SyncBus) Trigger(eventID UnsafeEventID, data interface{}) <-chan struct{} {
+	bus.trigger(eventID, Global, false, data)
+	return closedCh
+}
+
+// TriggerForCaller acts like Trigger, but will only trigger for the given caller.
+func (bus *SyncBus) TriggerForCaller(callerID CallerID, eventID UnsafeEventID, data interface{}) <-chan struct{} {
+	if callerID == Global {
+		return bus.Trigger(eventID, data)
+	}
+	bus.trigger(eventID, callerID, true, data)
+	return closedCh
+}
+
+// TriggerBubbling acts like Bus.TriggerBubbling, returning once the event has finished bubbling.
+func (bus *SyncBus) TriggerBubbling(callerID CallerID, eventID UnsafeEventID, data interface{}) <-chan struct{} {
+	bubble(bus.callerMap, callerID, func(cid CallerID) bool {
+		return bus.trigger(eventID, cid, true, data)
+	})
+	return closedCh
+}
+
+func (bus *SyncBus) trigger(eventID UnsafeEventID, callerID CallerID, forCaller bool, data interface{}) (stopped bool) {
+	// Q: Why copy the bindings instead of holding the lock while calling them?
+	// A: Bindings commonly bind and unbind from within their callbacks. Releasing the lock
+	//    lets those calls take effect immediately, which is what keeps this bus deterministic.
+	bus.mutex.Lock()
+	bs := make([]*syncBinding, 0, len(bus.bindings[eventID]))
+	for _, sb := range bus.bindings[eventID] {
+		if !forCaller || sb.callerID == callerID {
+			bs = append(bs, sb)
+		}
+	}
+	resetCount := bus.resetCount
+	bus.mutex.Unlock()
+
+	for _, sb := range bs {
+		bus.mutex.Lock()
+		unbound := sb.unbound
+		bus.mutex.Unlock()
+		if unbound {
+			continue
+		}
+		if sb.callerID != Global && !bus.callerMap.HasEntity(sb.callerID) {
+			continue
+		}
+		switch sb.fn(sb.callerID, bus, data) {
+		case ResponseUnbindThisBinding:
+			bus.Unbind(Binding{EventID: eventID, CallerID: sb.callerID, BindID: sb.bindID, busResetCount: resetCount})
+		case ResponseUnbindThisCaller:
+			bus.UnbindAllFrom(sb.callerID)
+		case ResponseStopPropagation:
+			stopped = true
+		}
+	}
+	return stopped
+}
+
+// Step triggers a single Enter event, reporting this bus's fixed frame delay as the time
+// since the last frame. Steps are numbered in the order Step is called.
+func (bus *SyncBus) Step() {
+	bus.stepMutex.Lock()
+	framesElapsed := bus.framesElapsed
+	bus.framesElapsed++
+	bus.stepMutex.Unlock()
+	bus.Trigger(Enter.UnsafeEventID, EnterPayload{
+		FramesElapsed:  framesElapsed,
+		SinceLastFrame: bus.frameDelay,
+		TickPercent:    1,
+	})
+}
+
+// FramesElapsed returns how many times Step has been called on this bus.
+func (bus *SyncBus) FramesElapsed() int {
+	bus.stepMutex.Lock()
+	defer bus.stepMutex.Unlock()
+	return bus.framesElapsed
+}
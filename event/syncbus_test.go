@@ -0,0 +1,174 @@
+package event_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+func TestSyncBus_Trigger(t *testing.T) {
+	t.Run("BindOrder", func(t *testing.T) {
+		b := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+		cid := b.GetCallerMap().Register(event.CallerID(0))
+		calls := []int{}
+		for i := 0; i < 100; i++ {
+			i := i
+			callerID := event.Global
+			if i%2 == 0 {
+				callerID = cid
+			}
+			b.UnsafeBind(1, callerID, func(event.CallerID, event.Handler, interface{}) event.Response {
+				calls = append(calls, i)
+				return 0
+			})
+		}
+		<-b.Trigger(1, nil)
+		if len(calls) != 100 {
+			t.Fatal(expectedError("calls", 100, len(calls)))
+		}
+		for i, v := range calls {
+			if i != v {
+				t.Fatal(expectedError("call order", i, v))
+			}
+		}
+	})
+	t.Run("BindDuringTrigger", func(t *testing.T) {
+		b := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+		var calls int
+		b.UnsafeBind(1, 0, func(_ event.CallerID, h event.Handler, _ interface{}) event.Response {
+			h.UnsafeBind(1, 0, func(event.CallerID, event.Handler, interface{}) event.Response {
+				calls++
+				return 0
+			})
+			return event.ResponseUnbindThisBinding
+		})
+		b.Trigger(1, nil)
+		if calls != 0 {
+			t.Fatal(expectedError("calls", 0, calls))
+		}
+		b.Trigger(1, nil)
+		if calls != 1 {
+			t.Fatal(expectedError("calls", 1, calls))
+		}
+	})
+	t.Run("UnbindDuringTrigger", func(t *testing.T) {
+		b := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+		var calls int
+		var second event.Binding
+		b.UnsafeBind(1, 0, func(event.CallerID, event.Handler, interface{}) event.Response {
+			second.Unbind()
+			return 0
+		})
+		second = b.UnsafeBind(1, 0, func(event.CallerID, event.Handler, interface{}) event.Response {
+			calls++
+			return 0
+		})
+		b.Trigger(1, nil)
+		if calls != 0 {
+			t.Fatal(expectedError("calls", 0, calls))
+		}
+	})
+	t.Run("ForCaller", func(t *testing.T) {
+		b := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+		cid1 := b.GetCallerMap().Register(event.CallerID(0))
+		cid2 := b.GetCallerMap().Register(event.CallerID(0))
+		called := []event.CallerID{}
+		for _, cid := range []event.CallerID{cid2, cid1, cid2} {
+			b.UnsafeBind(1, cid, func(ci event.CallerID, _ event.Handler, _ interface{}) event.Response {
+				called = append(called, ci)
+				return 0
+			})
+		}
+		b.TriggerForCaller(cid2, 1, nil)
+		if !reflect.DeepEqual(called, []event.CallerID{cid2, cid2}) {
+			t.Fatal(expectedError("called", []event.CallerID{cid2, cid2}, called))
+		}
+		b.UnbindAllFrom(cid2)
+		called = called[:0]
+		b.Trigger(1, nil)
+		if !reflect.DeepEqual(called, []event.CallerID{cid1}) {
+			t.Fatal(expectedError("called", []event.CallerID{cid1}, called))
+		}
+	})
+}
+
+func TestSyncBus_Priority(t *testing.T) {
+	t.Run("Ordered", func(t *testing.T) {
+		b := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+		calls := []int{}
+		priorities := []int{0, 2, -1, 2, 0, 1}
+		for i, p := range priorities {
+			i := i
+			b.UnsafeBind(1, 0, func(event.CallerID, event.Handler, interface{}) event.Response {
+				calls = append(calls, i)
+				return 0
+			}, event.WithPriority(p))
+		}
+		b.Trigger(1, nil)
+		expected := []int{1, 3, 5, 0, 4, 2}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Fatal(expectedError("calls", expected, calls))
+		}
+	})
+}
+
+func TestSyncBus_Reset(t *testing.T) {
+	t.Run("Persistent", func(t *testing.T) {
+		b := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+		calls := []int{}
+		b.PersistentBind(1, 0, func(event.CallerID, event.Handler, interface{}) event.Response {
+			calls = append(calls, 1)
+			return 0
+		})
+		b1 := b.UnsafeBind(1, 0, func(event.CallerID, event.Handler, interface{}) event.Response {
+			calls = append(calls, 2)
+			return 0
+		})
+		b.PersistentBind(1, 0, func(event.CallerID, event.Handler, interface{}) event.Response {
+			calls = append(calls, 3)
+			return 0
+		})
+		b.Reset()
+		// stale bindings should not unbind anything after a reset
+		b.Unbind(b1)
+		b.Trigger(1, nil)
+		if !reflect.DeepEqual(calls, []int{1, 3}) {
+			t.Fatal(expectedError("calls", []int{1, 3}, calls))
+		}
+		b.ClearPersistentBindings()
+		b.Reset()
+		b.Trigger(1, nil)
+		if !reflect.DeepEqual(calls, []int{1, 3}) {
+			t.Fatal(expectedError("calls", []int{1, 3}, calls))
+		}
+	})
+}
+
+func TestSyncBus_Step(t *testing.T) {
+	t.Run("FixedDelay", func(t *testing.T) {
+		b := event.NewSyncBus(event.NewCallerMap(), 16*time.Millisecond)
+		payloads := []event.EnterPayload{}
+		event.GlobalBind(b, event.Enter, func(ep event.EnterPayload) event.Response {
+			payloads = append(payloads, ep)
+			return 0
+		})
+		for i := 0; i < 3; i++ {
+			b.Step()
+		}
+		if b.FramesElapsed() != 3 {
+			t.Fatal(expectedError("frames elapsed", 3, b.FramesElapsed()))
+		}
+		for i, ep := range payloads {
+			expected := event.EnterPayload{
+				FramesElapsed:  i,
+				SinceLastFrame: 16 * time.Millisecond,
+				TickPercent:    1,
+			}
+			if ep != expected {
+				t.Fatal(expectedError("payload", expected, ep))
+			}
+		}
+	})
+}
@@ -10,7 +10,7 @@ func (bus *Bus) TriggerForCaller(callerID CallerID, eventID UnsafeEventID, data
 		bus.mutex.RLock()
 		if idMap, ok := bus.bindingMap[eventID]; ok {
 			if bs, ok := idMap[callerID]; ok {
-				bus.trigger(bs, eventID, callerID, data)
+				bus.trigger(map[CallerID]bindableList{callerID: bs}, eventID, data)
 			}
 		}
 		bus.mutex.RUnlock()
@@ -19,15 +19,50 @@ func (bus *Bus) TriggerForCaller(callerID CallerID, eventID UnsafeEventID, data
 	return ch
 }
 
+// TriggerBubbling triggers an event for the given caller, then for its parent, and so on up through
+// its ancestors as tracked by the bus's caller map, until some caller's binding returns
+// ResponseStopPropagation or a caller without a parent is reached.
+func (bus *Bus) TriggerBubbling(callerID CallerID, eventID UnsafeEventID, data interface{}) <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		bubble(bus.callerMap, callerID, func(cid CallerID) bool {
+			bus.mutex.RLock()
+			defer bus.mutex.RUnlock()
+			if bs, ok := bus.bindingMap[eventID][cid]; ok {
+				return bus.trigger(map[CallerID]bindableList{cid: bs}, eventID, data)
+			}
+			return false
+		})
+		close(ch)
+	}()
+	return ch
+}
+
+// bubble calls trigger for callerID and then each of its ancestors, until trigger reports that
+// propagation should stop.
+func bubble(cm *CallerMap, callerID CallerID, trigger func(CallerID) (stopped bool)) {
+	// guard against parent cycles
+	visited := map[CallerID]struct{}{}
+	for callerID != Global {
+		if _, ok := visited[callerID]; ok {
+			return
+		}
+		visited[callerID] = struct{}{}
+		if trigger(callerID) {
+			return
+		}
+		callerID = cm.Parent(callerID)
+	}
+}
+
 // Trigger will scan through the event bus and call all bindables found attached
-// to the given event, with the passed in data.
+// to the given event, with the passed in data. Bindables are called in descending
+// order of their priority.
 func (bus *Bus) Trigger(eventID UnsafeEventID, data interface{}) <-chan struct{} {
 	ch := make(chan struct{})
 	go func() {
 		bus.mutex.RLock()
-		for callerID, bs := range bus.bindingMap[eventID] {
-			bus.trigger(bs, eventID, callerID, data)
-		}
+		bus.trigger(bus.bindingMap[eventID], eventID, data)
 		bus.mutex.RUnlock()
 		close(ch)
 	}()
@@ -39,6 +74,11 @@ func TriggerOn[T any](b Handler, ev EventID[T], data T) <-chan struct{} {
 	return b.Trigger(ev.UnsafeEventID, data)
 }
 
+// TriggerBubblingOn calls TriggerBubbling with a strongly typed event.
+func TriggerBubblingOn[T any](b Handler, cid CallerID, ev EventID[T], data T) <-chan struct{} {
+	return b.TriggerBubbling(cid, ev.UnsafeEventID, data)
+}
+
 // TriggerForCallerOn calls TriggerForCaller with a strongly typed event.
 func TriggerForCallerOn[T any](b Handler, cid CallerID, ev EventID[T], data T) <-chan struct{} {
 	return b.TriggerForCaller(cid, ev.UnsafeEventID, data)
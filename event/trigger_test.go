@@ -249,6 +249,70 @@ func TestTriggerForCallerOn(t *testing.T) {
 	})
 }
 
+func TestTriggerBubbling(t *testing.T) {
+	handlers := map[string]func(*event.CallerMap) event.Handler{
+		"Bus": func(cm *event.CallerMap) event.Handler {
+			return event.NewBus(cm)
+		},
+		"SyncBus": func(cm *event.CallerMap) event.Handler {
+			return event.NewSyncBus(cm, time.Millisecond)
+		},
+	}
+	for name, newHandler := range handlers {
+		newHandler := newHandler
+		t.Run(name, func(t *testing.T) {
+			cm := event.NewCallerMap()
+			b := newHandler(cm)
+			root := cm.Register(event.CallerID(0))
+			mid := cm.Register(event.CallerID(0))
+			leaf := cm.Register(event.CallerID(0))
+			cm.SetParent(mid, root)
+			cm.SetParent(leaf, mid)
+
+			eventID := event.RegisterEvent[bool]()
+			calls := make(chan event.CallerID, 3)
+			for _, cid := range []event.CallerID{root, mid, leaf} {
+				bnd := b.UnsafeBind(eventID.UnsafeEventID, cid, func(ci event.CallerID, _ event.Handler, stop interface{}) event.Response {
+					calls <- ci
+					if stop.(bool) && ci == mid {
+						return event.ResponseStopPropagation
+					}
+					return 0
+				})
+				<-bnd.Bound
+			}
+			expectCalls := func(expected ...event.CallerID) {
+				t.Helper()
+				for _, cid := range expected {
+					if got := <-calls; got != cid {
+						t.Fatal(expectedError("caller", cid, got))
+					}
+				}
+				if len(calls) != 0 {
+					t.Fatal(expectedError("remaining calls", 0, len(calls)))
+				}
+			}
+			<-event.TriggerBubblingOn(b, leaf, eventID, false)
+			expectCalls(leaf, mid, root)
+			<-event.TriggerBubblingOn(b, leaf, eventID, true)
+			expectCalls(leaf, mid)
+
+			cm.RemoveEntity(mid)
+			<-event.TriggerBubblingOn(b, leaf, eventID, false)
+			expectCalls(leaf)
+
+			// removing a former parent leaves its former children alone
+			cm.SetParent(leaf, root)
+			cm.SetParent(mid, leaf)
+			cm.SetParent(mid, root)
+			cm.RemoveEntity(leaf)
+			if cm.Parent(mid) != root {
+				t.Fatal(expectedError("parent", root, cm.Parent(mid)))
+			}
+		})
+	}
+}
+
 func expectedError(name string, expected, got interface{}) error {
 	return fmt.Errorf("expected %s to be %v, got %v", name, expected, got)
 }
@@ -53,6 +53,22 @@ func ReadDir(file string) ([]fs.DirEntry, error) {
 	return fs.ReadDir(FS, fixedPath)
 }
 
+// WriteFile replaces os.WriteFile. Unlike reads, writes are always made to the OS filesystem,
+// as FS is read only.
+func WriteFile(file string, data []byte) error {
+	return os.WriteFile(file, data, 0644)
+}
+
+// Stat replaces os.Stat, trying to use FS.
+func Stat(file string) (fs.FileInfo, error) {
+	fixedPath := fixWindowsPath(file)
+	info, readErr := fs.Stat(FS, fixedPath)
+	if readErr != nil && OSFallback {
+		return os.Stat(file)
+	}
+	return info, readErr
+}
+
 func fixWindowsPath(file string) string {
 	if !FixWindowsPaths {
 		return file
@@ -58,7 +58,7 @@ func (w *Window) Init(firstScene string, configOptions ...ConfigOption) error {
 	w.DrawTicker = time.NewTicker(timing.FPSToFrameDelay(w.DrawFrameRate))
 
 	if w.config.TrackInputChanges {
-		trackJoystickChanges(w.eventHandler)
+		trackJoystickChanges(w.handler())
 	}
 
 	if !w.config.SkipRNGSeed {
@@ -17,17 +17,17 @@ import (
 // The following block defines events generated by oak during scene execution
 var (
 	// ViewportUpdate is triggered when the position of of the viewport changes
-	ViewportUpdate = event.RegisterEvent[intgeom.Point2]()
+	ViewportUpdate = event.RegisterNamedEvent[intgeom.Point2]("oak.ViewportUpdate")
 	// OnStop is triggered when the engine is stopped, e.g. when a window's close
 	// button is clicked.
-	OnStop = event.RegisterEvent[struct{}]()
+	OnStop = event.RegisterNamedEvent[struct{}]("oak.OnStop")
 	// FocusGain is triggered when a window gains focus
-	FocusGain = event.RegisterEvent[struct{}]()
+	FocusGain = event.RegisterNamedEvent[struct{}]("oak.FocusGain")
 	// FocusLoss is triggered when a window loses focus
-	FocusLoss = event.RegisterEvent[struct{}]()
+	FocusLoss = event.RegisterNamedEvent[struct{}]("oak.FocusLoss")
 	// InputChange is triggered when the most recent input device changes (e.g. keyboard to joystick or vice versa). It
 	// is only sent if Config.TrackInputChanges is true when Init is called.
-	InputChange = event.RegisterEvent[InputType]()
+	InputChange = event.RegisterNamedEvent[InputType]("oak.InputChange")
 )
 
 func (w *Window) inputLoop() {
@@ -38,25 +38,25 @@ func (w *Window) inputLoop() {
 			switch e.To {
 			case lifecycle.StageDead:
 				dlog.Info(dlog.WindowClosed)
-				<-event.TriggerOn(w.eventHandler, OnStop, struct{}{})
+				<-event.TriggerOn(w.handler(), OnStop, struct{}{})
 				close(w.quitCh)
 				return
 			case lifecycle.StageFocused:
 				w.inFocus = true
 				// If you are in focused state, we don't care how you got there
 				w.DrawTicker.Reset(timing.FPSToFrameDelay(w.DrawFrameRate))
-				event.TriggerOn(w.eventHandler, FocusGain, struct{}{})
+				event.TriggerOn(w.handler(), FocusGain, struct{}{})
 			case lifecycle.StageVisible:
 				// If the last state was focused, this means the app is out of focus
 				// otherwise, we're visible for the first time
 				if e.From > e.To {
 					w.inFocus = false
 					w.DrawTicker.Reset(timing.FPSToFrameDelay(w.IdleDrawFrameRate))
-					event.TriggerOn(w.eventHandler, FocusLoss, struct{}{})
+					event.TriggerOn(w.handler(), FocusLoss, struct{}{})
 				} else {
 					w.inFocus = true
 					w.DrawTicker.Reset(timing.FPSToFrameDelay(w.DrawFrameRate))
-					event.TriggerOn(w.eventHandler, FocusGain, struct{}{})
+					event.TriggerOn(w.handler(), FocusGain, struct{}{})
 				}
 			}
 		// Send key events
@@ -118,8 +118,8 @@ func (w *Window) inputLoop() {
 // from a real keypress.
 func (w *Window) TriggerKeyDown(e okey.Event) {
 	w.State.SetDown(e.Code)
-	event.TriggerOn(w.eventHandler, okey.AnyDown, e)
-	event.TriggerOn(w.eventHandler, okey.Down(e.Code), e)
+	event.TriggerOn(w.handler(), okey.AnyDown, e)
+	event.TriggerOn(w.handler(), okey.Down(e.Code), e)
 }
 
 // TriggerKeyUp triggers a software-emulated key release.
@@ -128,8 +128,8 @@ func (w *Window) TriggerKeyDown(e okey.Event) {
 // from a real key release.
 func (w *Window) TriggerKeyUp(e okey.Event) {
 	w.State.SetUp(e.Code)
-	event.TriggerOn(w.eventHandler, okey.AnyUp, e)
-	event.TriggerOn(w.eventHandler, okey.Up(e.Code), e)
+	event.TriggerOn(w.handler(), okey.AnyUp, e)
+	event.TriggerOn(w.handler(), okey.Up(e.Code), e)
 }
 
 // TriggerKeyHeld triggers a software-emulated key hold signal.
@@ -137,8 +137,8 @@ func (w *Window) TriggerKeyUp(e okey.Event) {
 // From the perspective of the event handler this is indistinguishable
 // from a real key hold signal.
 func (w *Window) TriggerKeyHeld(e okey.Event) {
-	event.TriggerOn(w.eventHandler, okey.AnyHeld, e)
-	event.TriggerOn(w.eventHandler, okey.Held(e.Code), e)
+	event.TriggerOn(w.handler(), okey.AnyHeld, e)
+	event.TriggerOn(w.handler(), okey.Held(e.Code), e)
 }
 
 // TriggerMouseEvent triggers a software-emulated mouse event.
@@ -152,14 +152,13 @@ func (w *Window) TriggerMouseEvent(mevent omouse.Event) {
 	if onOk {
 		w.Propagate(on, mevent)
 	}
-	event.TriggerOn(w.eventHandler, mevent.EventType, &mevent)
+	event.TriggerOn(w.handler(), mevent.EventType, &mevent)
 
 	if onOk {
 		rel, ok := omouse.EventRelative(on)
 		if ok {
 			relativeEvent := mevent
-			relativeEvent.Point2[0] += float64(w.viewPos[0])
-			relativeEvent.Point2[1] += float64(w.viewPos[1])
+			relativeEvent.Point2 = w.screenToWorld(mevent.Point2)
 			w.LastRelativeMouseEvent = relativeEvent
 
 			w.Propagate(rel, relativeEvent)
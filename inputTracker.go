@@ -14,7 +14,7 @@ import (
 // InputType expresses some form of input to the engine to represent a player
 type InputType int32
 
-var trackingJoystickChange = event.RegisterEvent[struct{}]()
+var trackingJoystickChange = event.RegisterNamedEvent[struct{}]("oak.trackingJoystickChange")
 
 // The following constants define valid types of input sent via the InputChange event.
 const (
@@ -25,24 +25,24 @@ const (
 )
 
 func (w *Window) trackInputChanges() {
-	event.GlobalBind(w.eventHandler, key.AnyDown, func(key.Event) event.Response {
+	event.GlobalBind(w.handler(), key.AnyDown, func(key.Event) event.Response {
 		old := atomic.SwapInt32(&w.mostRecentInput, int32(InputKeyboard))
 		if InputType(old) != InputKeyboard {
-			event.TriggerOn(w.eventHandler, InputChange, InputKeyboard)
+			event.TriggerOn(w.handler(), InputChange, InputKeyboard)
 		}
 		return 0
 	})
-	event.GlobalBind(w.eventHandler, mouse.Press, func(*mouse.Event) event.Response {
+	event.GlobalBind(w.handler(), mouse.Press, func(*mouse.Event) event.Response {
 		old := atomic.SwapInt32(&w.mostRecentInput, int32(InputMouse))
 		if InputType(old) != InputMouse {
-			event.TriggerOn(w.eventHandler, InputChange, InputMouse)
+			event.TriggerOn(w.handler(), InputChange, InputMouse)
 		}
 		return 0
 	})
-	event.GlobalBind(w.eventHandler, trackingJoystickChange, func(struct{}) event.Response {
+	event.GlobalBind(w.handler(), trackingJoystickChange, func(struct{}) event.Response {
 		old := atomic.SwapInt32(&w.mostRecentInput, int32(InputMouse))
 		if InputType(old) != InputJoystick {
-			event.TriggerOn(w.eventHandler, InputChange, InputJoystick)
+			event.TriggerOn(w.handler(), InputChange, InputJoystick)
 		}
 		return 0
 	})
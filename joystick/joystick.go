@@ -33,15 +33,15 @@ const (
 
 // Events. All events but Disconnected include a *State payload.
 var (
-	Change          = event.RegisterEvent[*State]()
-	ButtonDown      = event.RegisterEvent[*State]()
-	ButtonUp        = event.RegisterEvent[*State]()
-	RtTriggerChange = event.RegisterEvent[*State]()
-	LtTriggerChange = event.RegisterEvent[*State]()
-	RtStickChange   = event.RegisterEvent[*State]()
-	LtStickChange   = event.RegisterEvent[*State]()
+	Change          = event.RegisterNamedEvent[*State]("joystick.Change")
+	ButtonDown      = event.RegisterNamedEvent[*State]("joystick.ButtonDown")
+	ButtonUp        = event.RegisterNamedEvent[*State]("joystick.ButtonUp")
+	RtTriggerChange = event.RegisterNamedEvent[*State]("joystick.RtTriggerChange")
+	LtTriggerChange = event.RegisterNamedEvent[*State]("joystick.LtTriggerChange")
+	RtStickChange   = event.RegisterNamedEvent[*State]("joystick.RtStickChange")
+	LtStickChange   = event.RegisterNamedEvent[*State]("joystick.LtStickChange")
 	// Disconnected includes the ID of the joystick that disconnected.
-	Disconnected = event.RegisterEvent[uint32]()
+	Disconnected = event.RegisterNamedEvent[uint32]("joystick.Disconnected")
 )
 
 // Init calls any os functions necessary to detect joysticks
@@ -267,7 +267,7 @@ func Up(s string) event.EventID[*State] {
 	if ev, ok := upEvents[s]; ok {
 		return ev
 	}
-	ev := event.RegisterEvent[*State]()
+	ev := event.RegisterNamedEvent[*State]("joystick.Up(" + s + ")")
 	upEvents[s] = ev
 	return ev
 }
@@ -281,7 +281,7 @@ func Down(s string) event.EventID[*State] {
 	if ev, ok := downEvents[s]; ok {
 		return ev
 	}
-	ev := event.RegisterEvent[*State]()
+	ev := event.RegisterNamedEvent[*State]("joystick.Down(" + s + ")")
 	downEvents[s] = ev
 	return ev
 }
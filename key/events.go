@@ -10,13 +10,13 @@ import (
 var (
 	// Down is sent when a key is pressed. It is sent both as
 	// Down, and as Down + the key name.
-	AnyDown = event.RegisterEvent[Event]()
+	AnyDown = event.RegisterNamedEvent[Event]("key.AnyDown")
 	// Up is sent when a key is released. It is sent both as
 	// Up, and as Up + the key name.
-	AnyUp = event.RegisterEvent[Event]()
+	AnyUp = event.RegisterNamedEvent[Event]("key.AnyUp")
 	// Held is sent when a key is held down. It is sent both as
 	// Held, and as Held + the key name.
-	AnyHeld = event.RegisterEvent[Event]()
+	AnyHeld = event.RegisterNamedEvent[Event]("key.AnyHeld")
 )
 
 // An Event is sent as the payload for all key bindings.
@@ -49,7 +49,7 @@ func Up(code Code) event.EventID[Event] {
 	if ev, ok := upEvents[code]; ok {
 		return ev
 	}
-	ev := event.RegisterEvent[Event]()
+	ev := event.RegisterNamedEvent[Event]("key.Up(" + code.String() + ")")
 	upEvents[code] = ev
 	return ev
 }
@@ -63,7 +63,7 @@ func Down(code Code) event.EventID[Event] {
 	if ev, ok := downEvents[code]; ok {
 		return ev
 	}
-	ev := event.RegisterEvent[Event]()
+	ev := event.RegisterNamedEvent[Event]("key.Down(" + code.String() + ")")
 	downEvents[code] = ev
 	return ev
 }
@@ -77,7 +77,7 @@ func Held(code Code) event.EventID[Event] {
 	if ev, ok := heldEvents[code]; ok {
 		return ev
 	}
-	ev := event.RegisterEvent[Event]()
+	ev := event.RegisterNamedEvent[Event]("key.Held(" + code.String() + ")")
 	heldEvents[code] = ev
 	return ev
 }
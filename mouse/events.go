@@ -4,38 +4,38 @@ import "github.com/oakmound/oak/v4/event"
 
 var (
 	// Press is triggered when a mouse key is pressed down
-	Press = event.RegisterEvent[*Event]()
+	Press = event.RegisterNamedEvent[*Event]("mouse.Press")
 	// Release is triggered when a mouse key, pressed, is released
-	Release = event.RegisterEvent[*Event]()
+	Release = event.RegisterNamedEvent[*Event]("mouse.Release")
 	// ScrollDown is triggered when a mouse's scroll wheel scrolls downward
-	ScrollDown = event.RegisterEvent[*Event]()
+	ScrollDown = event.RegisterNamedEvent[*Event]("mouse.ScrollDown")
 	// ScrollUp is triggered when a mouse's scroll wheel scrolls upward
-	ScrollUp = event.RegisterEvent[*Event]()
+	ScrollUp = event.RegisterNamedEvent[*Event]("mouse.ScrollUp")
 	// Click is triggered when a Release follows a press for the same mouse key without
 	// other mouse key presses intertwining.
-	Click = event.RegisterEvent[*Event]()
+	Click = event.RegisterNamedEvent[*Event]("mouse.Click")
 	// Drag is triggered when the mouse is moved.
-	Drag = event.RegisterEvent[*Event]()
+	Drag = event.RegisterNamedEvent[*Event]("mouse.Drag")
 
 	// The 'On' Variants of all mouse events are triggered when a mouse event occurs on
 	// a specific entity in a mouse collision tree.
-	PressOn      = event.RegisterEvent[*Event]()
-	ReleaseOn    = event.RegisterEvent[*Event]()
-	ScrollDownOn = event.RegisterEvent[*Event]()
-	ScrollUpOn   = event.RegisterEvent[*Event]()
-	ClickOn      = event.RegisterEvent[*Event]()
-	DragOn       = event.RegisterEvent[*Event]()
+	PressOn      = event.RegisterNamedEvent[*Event]("mouse.PressOn")
+	ReleaseOn    = event.RegisterNamedEvent[*Event]("mouse.ReleaseOn")
+	ScrollDownOn = event.RegisterNamedEvent[*Event]("mouse.ScrollDownOn")
+	ScrollUpOn   = event.RegisterNamedEvent[*Event]("mouse.ScrollUpOn")
+	ClickOn      = event.RegisterNamedEvent[*Event]("mouse.ClickOn")
+	DragOn       = event.RegisterNamedEvent[*Event]("mouse.DragOn")
 
 	// Relative variants are like 'On' variants, but their mouse position data is relative to
 	// the window's current viewport. E.g. if the viewport is at 100,100 and a click happens at
 	// 100,100 on the window-- Relative will report 100,100, and non-relative will report 200,200.
 	// TODO: re-evaluate relative vs non-relative mouse events
-	RelativePressOn      = event.RegisterEvent[*Event]()
-	RelativeReleaseOn    = event.RegisterEvent[*Event]()
-	RelativeScrollDownOn = event.RegisterEvent[*Event]()
-	RelativeScrollUpOn   = event.RegisterEvent[*Event]()
-	RelativeClickOn      = event.RegisterEvent[*Event]()
-	RelativeDragOn       = event.RegisterEvent[*Event]()
+	RelativePressOn      = event.RegisterNamedEvent[*Event]("mouse.RelativePressOn")
+	RelativeReleaseOn    = event.RegisterNamedEvent[*Event]("mouse.RelativeReleaseOn")
+	RelativeScrollDownOn = event.RegisterNamedEvent[*Event]("mouse.RelativeScrollDownOn")
+	RelativeScrollUpOn   = event.RegisterNamedEvent[*Event]("mouse.RelativeScrollUpOn")
+	RelativeClickOn      = event.RegisterNamedEvent[*Event]("mouse.RelativeClickOn")
+	RelativeDragOn       = event.RegisterNamedEvent[*Event]("mouse.RelativeDragOn")
 )
 
 // EventOn converts a generic positioned mouse event into its variant indicating
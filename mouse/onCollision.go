@@ -46,8 +46,8 @@ func PhaseCollision(s *collision.Space, handler event.Handler) error {
 
 // MouseCollisionStart/Stop: see collision Start/Stop, for mouse collision
 var (
-	Start = event.RegisterEvent[*Event]()
-	Stop  = event.RegisterEvent[*Event]()
+	Start = event.RegisterNamedEvent[*Event]("mouse.Start")
+	Stop  = event.RegisterNamedEvent[*Event]("mouse.Stop")
 )
 
 func phaseCollisionEnter(id event.CallerID, handler event.Handler, _ interface{}) event.Response {
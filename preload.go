@@ -0,0 +1,167 @@
+package oak
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/oakmound/oak/v4/audio"
+	"github.com/oakmound/oak/v4/audio/format"
+	"github.com/oakmound/oak/v4/dlog"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/fileutil"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+// LoadProgress reports how much of a scene's assets have been loaded.
+type LoadProgress struct {
+	Scene       string
+	FilesLoaded int
+	TotalFiles  int
+	BytesLoaded int64
+	TotalBytes  int64
+}
+
+// Fraction returns the portion of bytes loaded, from 0 to 1. If sizes could not be
+// determined, it falls back to the portion of files loaded.
+func (lp LoadProgress) Fraction() float64 {
+	if lp.TotalBytes > 0 {
+		return float64(lp.BytesLoaded) / float64(lp.TotalBytes)
+	}
+	if lp.TotalFiles > 0 {
+		return float64(lp.FilesLoaded) / float64(lp.TotalFiles)
+	}
+	return 1
+}
+
+// SceneLoadProgress is triggered on a window's event handler each time a file of a preloading
+// scene's assets finishes loading.
+var SceneLoadProgress = event.RegisterNamedEvent[LoadProgress]("oak.SceneLoadProgress")
+
+// A ProgressRenderable is a renderable which can display loading progress. If a window's
+// LoadingR is a ProgressRenderable, it will be updated as scene assets load.
+type ProgressRenderable interface {
+	render.Renderable
+	SetProgress(LoadProgress)
+}
+
+// A Preload tracks the background loading of a scene's assets.
+type Preload struct {
+	done chan struct{}
+
+	mutex    sync.Mutex
+	progress LoadProgress
+	err      error
+}
+
+// Done returns a channel which is closed once all assets have been loaded, or have failed to load.
+func (p *Preload) Done() <-chan struct{} {
+	return p.done
+}
+
+// Progress returns how much of the scene's assets have been loaded so far.
+func (p *Preload) Progress() LoadProgress {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.progress
+}
+
+// Err returns the first error encountered loading an asset. Loading continues past errors.
+func (p *Preload) Err() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.err
+}
+
+// PreloadScene begins loading the named scene's assets in the background, e.g. while a previous
+// scene or a custom loading scene is running. If the scene is already being preloaded, the existing
+// Preload is returned. When the scene starts, it will wait for its preload to complete, after which
+// the preload is forgotten; preloading the scene again will load its assets again.
+func (w *Window) PreloadScene(name string) (*Preload, error) {
+	scen, ok := w.SceneMap.Get(name)
+	if !ok {
+		return nil, oakerr.NotFound{InputName: name}
+	}
+	w.preloadMutex.Lock()
+	defer w.preloadMutex.Unlock()
+	if p, ok := w.preloads[name]; ok {
+		return p, nil
+	}
+	if w.preloads == nil {
+		w.preloads = make(map[string]*Preload)
+	}
+	p := &Preload{
+		done: make(chan struct{}),
+		progress: LoadProgress{
+			Scene:      name,
+			TotalFiles: len(scen.Assets),
+		},
+	}
+	w.preloads[name] = p
+	go w.preload(p, scen.Assets)
+	return p, nil
+}
+
+func (w *Window) preload(p *Preload, assets []string) {
+	sizes := make([]int64, len(assets))
+	var totalBytes int64
+	for i, file := range assets {
+		if info, err := fileutil.Stat(file); err == nil {
+			sizes[i] = info.Size()
+			totalBytes += sizes[i]
+		}
+	}
+	p.mutex.Lock()
+	p.progress.TotalBytes = totalBytes
+	p.mutex.Unlock()
+
+	for i, file := range assets {
+		err := loadAsset(file)
+		p.mutex.Lock()
+		if err != nil && p.err == nil {
+			p.err = err
+		}
+		p.progress.FilesLoaded++
+		p.progress.BytesLoaded += sizes[i]
+		progress := p.progress
+		p.mutex.Unlock()
+		if err != nil {
+			dlog.Error("failed to load asset", file, err)
+		}
+		if pr, ok := w.LoadingR.(ProgressRenderable); ok {
+			pr.SetProgress(progress)
+		}
+		event.TriggerOn(w.handler(), SceneLoadProgress, progress)
+	}
+	close(p.done)
+}
+
+// loadAsset loads a single file into the audio or image cache, depending on its extension.
+func loadAsset(file string) error {
+	ext := strings.ToLower(filepath.Ext(file))
+	if _, ok := format.LoaderForExtension(ext); ok {
+		_, err := audio.Load(file)
+		return err
+	}
+	return render.LoadFile(file)
+}
+
+// awaitPreload loads the named scene's assets if it has any, waiting for them to finish loading.
+func (w *Window) awaitPreload(name string, scen scene.Scene) {
+	if len(scen.Assets) == 0 {
+		return
+	}
+	p, err := w.PreloadScene(name)
+	if err != nil {
+		dlog.Error(err)
+		return
+	}
+	<-p.Done()
+	w.preloadMutex.Lock()
+	if w.preloads[name] == p {
+		delete(w.preloads, name)
+	}
+	w.preloadMutex.Unlock()
+}
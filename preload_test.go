@@ -0,0 +1,82 @@
+package oak
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/fileutil"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+func TestPreloadScene(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	oldFS := fileutil.FS
+	defer func() {
+		fileutil.FS = oldFS
+	}()
+	fileutil.FS = fstest.MapFS{
+		"preload/a.png": &fstest.MapFile{Data: buf.Bytes()},
+		"preload/b.PNG": &fstest.MapFile{Data: buf.Bytes()},
+	}
+
+	w := NewWindow()
+	bus := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+	w.SetLogicHandler(bus)
+	progress := make(chan LoadProgress, 4)
+	event.GlobalBind(bus, SceneLoadProgress, func(lp LoadProgress) event.Response {
+		progress <- lp
+		return 0
+	})
+	err := w.SceneMap.AddScene("level", scene.Scene{
+		Assets: []string{"preload/a.png", "preload/b.PNG"},
+	})
+	if err != nil {
+		t.Fatalf("Scene Add failed: %v", err)
+	}
+	if _, err := w.PreloadScene("missing"); err == nil {
+		t.Fatal("expected error preloading unknown scene")
+	}
+	p, err := w.PreloadScene("level")
+	if err != nil {
+		t.Fatalf("preload failed: %v", err)
+	}
+	if p2, _ := w.PreloadScene("level"); p2 != p {
+		t.Fatal("expected repeated preloads to share progress")
+	}
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("preload did not complete")
+	}
+	if p.Err() != nil {
+		t.Fatalf("preload errored: %v", p.Err())
+	}
+	lp := p.Progress()
+	if lp.FilesLoaded != 2 || lp.TotalFiles != 2 || lp.BytesLoaded != lp.TotalBytes || lp.Fraction() != 1 {
+		t.Fatalf("unexpected final progress: %+v", lp)
+	}
+	first := <-progress
+	if first.FilesLoaded != 1 || first.Scene != "level" {
+		t.Fatalf("unexpected first progress: %+v", first)
+	}
+	if _, err := render.GetSprite("preload/b.PNG"); err != nil {
+		t.Fatalf("expected preloaded sprite with an upper case extension to be cached: %v", err)
+	}
+
+	scen, _ := w.SceneMap.Get("level")
+	w.awaitPreload("level", scen)
+	p2, _ := w.PreloadScene("level")
+	if p2 == p {
+		t.Fatal("expected preload to be forgotten once the scene started")
+	}
+	<-p2.Done()
+}
@@ -0,0 +1,459 @@
+// Package aseprite loads Aseprite's .aseprite and .ase files as render.Switches of animations.
+//
+// Importing this package also registers .aseprite and .ase files with render's loaders, e.g.
+// render.LoadSprite, which load the first frame of a file as a still image. Use Load, or Open and
+// File.Switch, for a file's tagged animations.
+package aseprite
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/oakmound/oak/v4/fileutil"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+const (
+	fileMagic  = 0xA5E0
+	frameMagic = 0xF1FA
+)
+
+// Chunk types
+const (
+	chunkOldPalette = 0x0004
+	chunkLayer      = 0x2004
+	chunkCel        = 0x2005
+	chunkTags       = 0x2018
+	chunkPalette    = 0x2019
+)
+
+// Cel types
+const (
+	celRaw        = 0
+	celLinked     = 1
+	celCompressed = 2
+)
+
+// maxPaletteSize is the most colors a palette can hold, as indexed pixels are one byte.
+const maxPaletteSize = 256
+
+// frameHeaderSize is the size of a frame's header, which its byte count includes.
+const frameHeaderSize = 16
+
+// Color depths, in bits per pixel
+const (
+	depthRGBA      = 32
+	depthGrayscale = 16
+	depthIndexed   = 8
+)
+
+// A Direction is the order an animation's frames play in.
+type Direction uint8
+
+// Directions
+const (
+	Forward Direction = iota
+	Reverse
+	PingPong
+	PingPongReverse
+)
+
+// A File is a decoded Aseprite file.
+type File struct {
+	Width, Height int
+	Frames        []Frame
+	Layers        []Layer
+	Tags          []Tag
+
+	depth       int
+	transparent uint8
+	palette     color.Palette
+}
+
+// A Frame is one frame of a file's animation.
+type Frame struct {
+	Duration time.Duration
+	cels     []cel
+}
+
+// A Layer is one layer of a file's frames.
+type Layer struct {
+	Name    string
+	Visible bool
+	// Group layers contain the layers after them with a greater ChildLevel, up to the next layer
+	// with the same or a lower ChildLevel.
+	Group      bool
+	ChildLevel int
+	Opacity    uint8
+	// BlendMode is Aseprite's blend mode for the layer. Normal, multiply, screen, addition and
+	// subtract layers are blended as in Aseprite; others are drawn as normal layers.
+	BlendMode uint16
+
+	// tilemap layers are not supported, and are not drawn
+	tilemap bool
+}
+
+// A Tag names a range of frames as an animation.
+type Tag struct {
+	Name      string
+	From, To  int
+	Direction Direction
+	// Repeat is how many times Aseprite plays the animation, or 0 if it repeats forever.
+	Repeat int
+}
+
+type cel struct {
+	layer   int
+	x, y    int
+	opacity uint8
+	zIndex  int
+	img     *image.NRGBA
+}
+
+// Open reads and decodes a file.
+func Open(file string) (*File, error) {
+	r, err := fileutil.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return Decode(r)
+}
+
+// Decode decodes an Aseprite file.
+func Decode(r io.Reader) (*File, error) {
+	var header struct {
+		FileSize    uint32
+		Magic       uint16
+		Frames      uint16
+		Width       uint16
+		Height      uint16
+		Depth       uint16
+		Flags       uint32
+		Speed       uint16
+		_           [2]uint32
+		Transparent uint8
+		_           [3]uint8
+		Colors      uint16
+		_           [2]uint8
+		_           [2]int16
+		_           [2]uint16
+		_           [84]uint8
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Magic != fileMagic {
+		return nil, oakerr.UnsupportedFormat{Format: "file without aseprite magic number"}
+	}
+	f := &File{
+		Width:       int(header.Width),
+		Height:      int(header.Height),
+		depth:       int(header.Depth),
+		transparent: header.Transparent,
+	}
+	switch f.depth {
+	case depthRGBA, depthGrayscale, depthIndexed:
+	default:
+		return nil, oakerr.UnsupportedFormat{Format: strconv.Itoa(f.depth) + " bit color"}
+	}
+	for i := 0; i < int(header.Frames); i++ {
+		if err := f.decodeFrame(r); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (f *File) decodeFrame(r io.Reader) error {
+	var header struct {
+		Size      uint32
+		Magic     uint16
+		OldChunks uint16
+		Duration  uint16
+		_         [2]uint8
+		Chunks    uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if header.Magic != frameMagic {
+		return oakerr.InvalidInput{InputName: "frame " + strconv.Itoa(len(f.Frames))}
+	}
+	if header.Size < frameHeaderSize {
+		return oakerr.InvalidInput{InputName: "frame " + strconv.Itoa(len(f.Frames)) + " size"}
+	}
+	// remaining is how many bytes of the frame's chunks have not been read
+	remaining := header.Size - frameHeaderSize
+	chunks := int(header.Chunks)
+	if chunks == 0 {
+		chunks = int(header.OldChunks)
+	}
+	f.Frames = append(f.Frames, Frame{Duration: time.Duration(header.Duration) * time.Millisecond})
+	hasPalette := false
+	for i := 0; i < chunks; i++ {
+		var chunkHeader struct {
+			Size uint32
+			Type uint16
+		}
+		if err := binary.Read(r, binary.LittleEndian, &chunkHeader); err != nil {
+			return err
+		}
+		if chunkHeader.Size < 6 || chunkHeader.Size > remaining {
+			return oakerr.InvalidInput{InputName: "chunk size"}
+		}
+		remaining -= chunkHeader.Size
+		// Q: Why not allocate the chunk's data up front?
+		// A: The size is only a claim; a truncated file claiming a huge chunk would allocate it all
+		//    before failing to read it.
+		data := new(bytes.Buffer)
+		if _, err := io.CopyN(data, r, int64(chunkHeader.Size-6)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		c := &chunkReader{data: data.Bytes()}
+		switch chunkHeader.Type {
+		case chunkLayer:
+			f.decodeLayer(c)
+		case chunkCel:
+			if err := f.decodeCel(c); err != nil {
+				return err
+			}
+		case chunkTags:
+			f.decodeTags(c)
+		case chunkPalette:
+			f.decodePalette(c)
+			hasPalette = true
+		case chunkOldPalette:
+			// old palettes are only present for backwards compatibility
+			if !hasPalette {
+				f.decodeOldPalette(c)
+			}
+		}
+		if c.err != nil {
+			return c.err
+		}
+	}
+	return nil
+}
+
+func (f *File) decodeLayer(c *chunkReader) {
+	flags := c.u16()
+	layerType := c.u16()
+	l := Layer{
+		Visible:    flags&1 != 0,
+		Group:      layerType == 1,
+		tilemap:    layerType == 2,
+		ChildLevel: int(c.u16()),
+	}
+	c.skip(4) // default width and height
+	l.BlendMode = c.u16()
+	l.Opacity = c.u8()
+	c.skip(3)
+	l.Name = c.str()
+	f.Layers = append(f.Layers, l)
+}
+
+func (f *File) decodeCel(c *chunkReader) error {
+	cl := cel{
+		layer: int(c.u16()),
+		x:     int(int16(c.u16())),
+		y:     int(int16(c.u16())),
+	}
+	cl.opacity = c.u8()
+	celType := c.u16()
+	cl.zIndex = int(int16(c.u16()))
+	c.skip(5)
+	frame := &f.Frames[len(f.Frames)-1]
+	switch celType {
+	case celLinked:
+		linked := int(c.u16())
+		if linked >= len(f.Frames) {
+			return oakerr.InvalidInput{InputName: "linked cel frame"}
+		}
+		for _, other := range f.Frames[linked].cels {
+			if other.layer == cl.layer {
+				other.zIndex = cl.zIndex
+				frame.cels = append(frame.cels, other)
+				break
+			}
+		}
+		return nil
+	case celRaw, celCompressed:
+		w, h := int(c.u16()), int(c.u16())
+		pixels := c.rest()
+		if celType == celCompressed {
+			zr, err := zlib.NewReader(bytes.NewReader(pixels))
+			if err != nil {
+				return err
+			}
+			// a cel never needs more than w*h*4 bytes; more would only be read to be discarded
+			pixels, err = io.ReadAll(io.LimitReader(zr, int64(w*h*4)))
+			if err != nil {
+				return err
+			}
+		}
+		img, err := f.decodePixels(w, h, pixels)
+		if err != nil {
+			return err
+		}
+		cl.img = img
+		frame.cels = append(frame.cels, cl)
+	}
+	// tilemap cels are not supported
+	return nil
+}
+
+// decodePixels converts a cel's pixels from the file's color depth.
+func (f *File) decodePixels(w, h int, pixels []byte) (*image.NRGBA, error) {
+	bpp := f.depth / 8
+	if len(pixels) < w*h*bpp {
+		return nil, oakerr.InvalidInput{InputName: "cel pixels"}
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < w*h; i++ {
+		px := img.Pix[i*4 : i*4+4 : i*4+4]
+		switch f.depth {
+		case depthRGBA:
+			copy(px, pixels[i*4:i*4+4])
+		case depthGrayscale:
+			v, a := pixels[i*2], pixels[i*2+1]
+			px[0], px[1], px[2], px[3] = v, v, v, a
+		case depthIndexed:
+			idx := pixels[i]
+			if idx == f.transparent || int(idx) >= len(f.palette) {
+				continue
+			}
+			c := f.palette[idx].(color.NRGBA)
+			px[0], px[1], px[2], px[3] = c.R, c.G, c.B, c.A
+		}
+	}
+	return img, nil
+}
+
+func (f *File) decodeTags(c *chunkReader) {
+	count := int(c.u16())
+	c.skip(8)
+	for i := 0; i < count && c.err == nil; i++ {
+		t := Tag{
+			From: int(c.u16()),
+			To:   int(c.u16()),
+		}
+		t.Direction = Direction(c.u8())
+		t.Repeat = int(c.u16())
+		c.skip(6 + 3 + 1) // reserved, deprecated color, and an extra byte
+		t.Name = c.str()
+		f.Tags = append(f.Tags, t)
+	}
+}
+
+func (f *File) decodePalette(c *chunkReader) {
+	size := int(c.u32())
+	first, last := int(c.u32()), int(c.u32())
+	c.skip(8)
+	if c.err != nil {
+		return
+	}
+	if size > maxPaletteSize || last < first || last >= maxPaletteSize {
+		c.err = oakerr.InvalidInput{InputName: "palette size"}
+		return
+	}
+	if size > len(f.palette) {
+		f.palette = append(f.palette, make(color.Palette, size-len(f.palette))...)
+	}
+	for i := first; i <= last && c.err == nil; i++ {
+		flags := c.u16()
+		col := color.NRGBA{c.u8(), c.u8(), c.u8(), c.u8()}
+		if flags&1 != 0 {
+			c.str()
+		}
+		if i < len(f.palette) {
+			f.palette[i] = col
+		}
+	}
+	for i, col := range f.palette {
+		if col == nil {
+			f.palette[i] = color.NRGBA{}
+		}
+	}
+}
+
+func (f *File) decodeOldPalette(c *chunkReader) {
+	packets := int(c.u16())
+	idx := 0
+	for i := 0; i < packets && c.err == nil; i++ {
+		idx += int(c.u8())
+		count := int(c.u8())
+		if count == 0 {
+			count = 256
+		}
+		for j := 0; j < count && c.err == nil; j, idx = j+1, idx+1 {
+			if idx >= maxPaletteSize {
+				c.err = oakerr.InvalidInput{InputName: "old palette size"}
+				return
+			}
+			col := color.NRGBA{c.u8(), c.u8(), c.u8(), 255}
+			for len(f.palette) <= idx {
+				f.palette = append(f.palette, color.NRGBA{})
+			}
+			f.palette[idx] = col
+		}
+	}
+}
+
+// A chunkReader reads the little endian values of a chunk, recording the first error encountered
+// rather than returning it from each read.
+type chunkReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (c *chunkReader) next(n int) []byte {
+	if c.err != nil || c.pos+n > len(c.data) {
+		if c.err == nil {
+			c.err = io.ErrUnexpectedEOF
+		}
+		return make([]byte, n)
+	}
+	b := c.data[c.pos : c.pos+n]
+	c.pos += n
+	return b
+}
+
+func (c *chunkReader) u8() uint8 {
+	return c.next(1)[0]
+}
+
+func (c *chunkReader) u16() uint16 {
+	return binary.LittleEndian.Uint16(c.next(2))
+}
+
+func (c *chunkReader) u32() uint32 {
+	return binary.LittleEndian.Uint32(c.next(4))
+}
+
+func (c *chunkReader) skip(n int) {
+	c.next(n)
+}
+
+func (c *chunkReader) str() string {
+	return string(c.next(int(c.u16())))
+}
+
+func (c *chunkReader) rest() []byte {
+	if c.err != nil {
+		return nil
+	}
+	b := c.data[c.pos:]
+	c.pos = len(c.data)
+	return b
+}
@@ -0,0 +1,223 @@
+package aseprite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/render"
+)
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	green = color.RGBA{0, 255, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+)
+
+func TestOpen(t *testing.T) {
+	f, err := Open("testdata/layers.aseprite")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	if f.Width != 4 || f.Height != 4 {
+		t.Fatalf("expected 4x4 file, got %vx%v", f.Width, f.Height)
+	}
+	if len(f.Frames) != 3 {
+		t.Fatalf("expected 3 frames, got %v", len(f.Frames))
+	}
+	for i, d := range []time.Duration{100, 200, 300} {
+		if f.Frames[i].Duration != d*time.Millisecond {
+			t.Fatalf("expected frame %v to last %vms, got %v", i, d, f.Frames[i].Duration)
+		}
+	}
+	names := []string{"background", "hidden", "top", "group", "inGroup"}
+	if len(f.Layers) != len(names) {
+		t.Fatalf("expected %v layers, got %v", len(names), len(f.Layers))
+	}
+	for i, name := range names {
+		if f.Layers[i].Name != name {
+			t.Fatalf("expected layer %v to be %v, got %v", i, name, f.Layers[i].Name)
+		}
+	}
+	if f.Layers[1].Visible || !f.Layers[3].Group || f.Layers[4].ChildLevel != 1 {
+		t.Fatalf("expected layer properties to be decoded, got %v", f.Layers)
+	}
+	walk, ok := f.Tag("walk")
+	if !ok || walk != (Tag{Name: "walk", From: 0, To: 2, Direction: PingPong}) {
+		t.Fatalf("expected walk tag, got %v", walk)
+	}
+
+	if _, err := Decode(bytes.NewReader(make([]byte, 128))); err == nil {
+		t.Fatal("expected file without magic number to fail to decode")
+	}
+	if _, err := Decode(bytes.NewReader(make([]byte, 10))); err == nil {
+		t.Fatal("expected truncated file to fail to decode")
+	}
+	// a cel claiming to be huge, without the pixels to back it, fails before allocating its image
+	if _, err := (&File{depth: 32}).decodePixels(65535, 65535, make([]byte, 16)); err == nil {
+		t.Fatal("expected cel without enough pixels to fail to decode")
+	}
+}
+
+// malformedFile encodes a 1x1 indexed file of one frame holding one chunk. The frame and chunk
+// claim the given sizes, regardless of the data actually following them.
+func malformedFile(frameSize, chunkSize uint32, chunkType uint16, data []byte) []byte {
+	buf := new(bytes.Buffer)
+	header := make([]byte, 128)
+	binary.LittleEndian.PutUint16(header[4:], fileMagic)
+	binary.LittleEndian.PutUint16(header[6:], 1)  // frames
+	binary.LittleEndian.PutUint16(header[8:], 1)  // width
+	binary.LittleEndian.PutUint16(header[10:], 1) // height
+	binary.LittleEndian.PutUint16(header[12:], depthIndexed)
+	buf.Write(header)
+	frame := make([]byte, frameHeaderSize)
+	binary.LittleEndian.PutUint32(frame[0:], frameSize)
+	binary.LittleEndian.PutUint16(frame[4:], frameMagic)
+	binary.LittleEndian.PutUint32(frame[12:], 1) // chunks
+	buf.Write(frame)
+	binary.Write(buf, binary.LittleEndian, chunkSize)
+	binary.Write(buf, binary.LittleEndian, chunkType)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// paletteChunk encodes the start of a palette chunk, without any of its entries.
+func paletteChunk(size, first, last uint32) []byte {
+	data := make([]byte, 20)
+	binary.LittleEndian.PutUint32(data[0:], size)
+	binary.LittleEndian.PutUint32(data[4:], first)
+	binary.LittleEndian.PutUint32(data[8:], last)
+	return data
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	const chunkSize = 6 + 20
+	files := map[string][]byte{
+		"huge palette":             malformedFile(100, chunkSize, chunkPalette, paletteChunk(0xFFFFFFFF, 0, 0)),
+		"palette past 256 entries": malformedFile(100, chunkSize, chunkPalette, paletteChunk(256, 0, 300)),
+		"palette ending first":     malformedFile(100, chunkSize, chunkPalette, paletteChunk(4, 3, 1)),
+		"huge truncated chunk":     malformedFile(0xFFFFFFFF, 0xFFFFFFF0, chunkPalette, paletteChunk(4, 0, 0)),
+		"chunk larger than frame":  malformedFile(frameHeaderSize+10, chunkSize, chunkPalette, paletteChunk(4, 0, 0)),
+	}
+	for name, data := range files {
+		if _, err := Decode(bytes.NewReader(data)); err == nil {
+			t.Fatalf("expected %v to fail to decode", name)
+		}
+	}
+}
+
+func TestFile_Image(t *testing.T) {
+	f, err := Open("testdata/layers.aseprite")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	img, err := f.Image(0)
+	if err != nil {
+		t.Fatalf("failed to composite frame: %v", err)
+	}
+	// hidden layers, and layers in hidden groups, are not drawn
+	if c := img.RGBAAt(0, 0); c != red {
+		t.Fatalf("expected background layer, got %v", c)
+	}
+	if c := img.RGBAAt(1, 1); c != blue {
+		t.Fatalf("expected top layer over background, got %v", c)
+	}
+
+	// the background cel is linked to the first frame's, and the top cel is half transparent
+	img, err = f.Image(1)
+	if err != nil {
+		t.Fatalf("failed to composite frame: %v", err)
+	}
+	if c := img.RGBAAt(0, 0); c != red {
+		t.Fatalf("expected linked background layer, got %v", c)
+	}
+	if c := img.RGBAAt(3, 3); c.R < 126 || c.R > 128 || c.B < 127 || c.B > 129 {
+		t.Fatalf("expected half transparent top layer, got %v", c)
+	}
+
+	img, err = f.Image(0, "hidden")
+	if err != nil {
+		t.Fatalf("failed to composite frame: %v", err)
+	}
+	if c := img.RGBAAt(1, 1); c != green {
+		t.Fatalf("expected only the named layer, got %v", c)
+	}
+	if _, err := f.Image(0, "missing"); err == nil {
+		t.Fatal("expected missing layer to fail")
+	}
+	if _, err := f.Image(3); err == nil {
+		t.Fatal("expected frame out of range to fail")
+	}
+}
+
+func TestFile_Switch(t *testing.T) {
+	sw, err := Load("testdata/layers.aseprite")
+	if err != nil {
+		t.Fatalf("failed to load file: %v", err)
+	}
+	if sw.Get() != "idle" {
+		t.Fatalf("expected switch to start on the first tag, got %v", sw.Get())
+	}
+	frameColors := map[string][]color.RGBA{
+		"idle": {red},
+		"walk": {red, red, green, red},
+		"back": {green, red},
+	}
+	for tag, colors := range frameColors {
+		sq, ok := sw.GetSub(tag).(*render.Sequence)
+		if !ok {
+			t.Fatalf("expected sequence for tag %v", tag)
+		}
+		for i, want := range colors {
+			if c := sq.Get(i).GetRGBA().RGBAAt(0, 0); c != want {
+				t.Fatalf("expected %v frame %v to be %v, got %v", tag, i, want, c)
+			}
+		}
+	}
+	// the second frame of walk is the first with the top layer at 2, 2
+	walk := sw.GetSub("walk").(*render.Sequence)
+	if c := walk.Get(1).GetRGBA().RGBAAt(1, 1); c != red {
+		t.Fatalf("expected walk to advance to the second frame, got %v", c)
+	}
+}
+
+func TestIndexed(t *testing.T) {
+	f, err := Open("testdata/indexed.aseprite")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	img, err := f.Image(0)
+	if err != nil {
+		t.Fatalf("failed to composite frame: %v", err)
+	}
+	if img.RGBAAt(0, 0) != red || img.RGBAAt(1, 0) != blue {
+		t.Fatal("expected palette colors")
+	}
+	img, err = f.Image(1)
+	if err != nil {
+		t.Fatalf("failed to composite frame: %v", err)
+	}
+	if c := img.RGBAAt(0, 0); c != (color.RGBA{}) {
+		t.Fatalf("expected transparent index to be transparent, got %v", c)
+	}
+
+	sw, err := f.Switch()
+	if err != nil {
+		t.Fatalf("failed to build switch: %v", err)
+	}
+	if sw.Get() != DefaultKey {
+		t.Fatalf("expected untagged file to use the default key, got %v", sw.Get())
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	s, err := render.NewCache().LoadSprite("testdata/layers.aseprite")
+	if err != nil {
+		t.Fatalf("failed to load sprite: %v", err)
+	}
+	if c := s.GetRGBA().RGBAAt(1, 1); c != blue {
+		t.Fatalf("expected sprite of the first frame, got %v", c)
+	}
+}
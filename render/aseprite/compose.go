@@ -0,0 +1,250 @@
+package aseprite
+
+import (
+	"image"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+)
+
+// DefaultKey is the key of the sequence of all of a file's frames in switches built from files
+// without tags.
+const DefaultKey = "default"
+
+// Aseprite's blend modes which have equivalents in render
+var blendModes = map[uint16]render.BlendMode{
+	1:  render.BlendMultiply,
+	2:  render.BlendScreen,
+	16: render.BlendAdd,
+	17: render.BlendSubtract,
+}
+
+func init() {
+	for _, ext := range []string{".aseprite", ".ase"} {
+		render.RegisterDecoder(ext, decodeImage)
+		render.RegisterCfgDecoder(ext, decodeConfig)
+	}
+}
+
+// decodeImage decodes the first frame of a file, so that loading an Aseprite file as a sprite
+// behaves like loading a gif. Loading a file through render's loaders, e.g. render.LoadSprite,
+// only ever produces its first frame; Load is the only way to get a file's tagged animations.
+func decodeImage(r io.Reader) (image.Image, error) {
+	f, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return f.Image(0)
+}
+
+func decodeConfig(r io.Reader) (image.Config, error) {
+	f, err := Decode(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{Width: f.Width, Height: f.Height}, nil
+}
+
+// Load reads a file as a switch of its animations. See File.Switch.
+func Load(file string) (*render.Switch, error) {
+	f, err := Open(file)
+	if err != nil {
+		return nil, err
+	}
+	return f.Switch()
+}
+
+// Image composites a frame of this file. If layers are named, only those layers are drawn, hidden
+// or not; otherwise every visible layer is drawn.
+func (f *File) Image(frame int, layers ...string) (*image.RGBA, error) {
+	if frame < 0 || frame >= len(f.Frames) {
+		return nil, oakerr.InvalidInput{InputName: "frame"}
+	}
+	draws := f.visibleLayers()
+	if len(layers) > 0 {
+		draws = make([]bool, len(f.Layers))
+		for _, name := range layers {
+			found := false
+			for i, l := range f.Layers {
+				if l.Name == name {
+					draws[i] = true
+					found = true
+				}
+			}
+			if !found {
+				return nil, oakerr.NotFound{InputName: "layer:" + name}
+			}
+		}
+	}
+	cels := make([]cel, 0, len(f.Frames[frame].cels))
+	for _, c := range f.Frames[frame].cels {
+		if c.layer < len(f.Layers) && draws[c.layer] && !f.Layers[c.layer].Group && !f.Layers[c.layer].tilemap {
+			cels = append(cels, c)
+		}
+	}
+	// Cels are drawn in layer order, offset by their z index. Ties are broken by z index, so a cel
+	// moved to the same position as another layer's cel is drawn after it if moved forward.
+	sort.SliceStable(cels, func(i, j int) bool {
+		oi, oj := cels[i].layer+cels[i].zIndex, cels[j].layer+cels[j].zIndex
+		if oi == oj {
+			return cels[i].zIndex < cels[j].zIndex
+		}
+		return oi < oj
+	})
+
+	img := image.NewRGBA(image.Rect(0, 0, f.Width, f.Height))
+	for _, c := range cels {
+		l := f.Layers[c.layer]
+		src := premultiply(c.img, uint8(mul255(uint32(c.opacity), uint32(l.Opacity))))
+		if mode, ok := blendModes[l.BlendMode]; ok {
+			render.DrawImageBlend(img, src, c.x, c.y, mode)
+		} else {
+			render.DrawImage(img, src, c.x, c.y)
+		}
+	}
+	return img, nil
+}
+
+// visibleLayers reports whether each layer is visible, accounting for the visibility of the groups
+// containing it.
+func (f *File) visibleLayers() []bool {
+	visible := make([]bool, len(f.Layers))
+	// groups holds the visibility of the most recent layer at each child level
+	groups := []bool{}
+	for i, l := range f.Layers {
+		parentVisible := true
+		if l.ChildLevel > 0 && l.ChildLevel <= len(groups) {
+			parentVisible = groups[l.ChildLevel-1]
+		}
+		visible[i] = l.Visible && parentVisible
+		if l.ChildLevel < len(groups) {
+			groups = groups[:l.ChildLevel]
+		}
+		groups = append(groups, visible[i])
+	}
+	return visible
+}
+
+// premultiply converts img to an RGBA image with its alpha scaled by opacity.
+func premultiply(img *image.NRGBA, opacity uint8) *image.RGBA {
+	rgba := image.NewRGBA(img.Bounds())
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		a := mul255(uint32(img.Pix[i+3]), uint32(opacity))
+		rgba.Pix[i] = uint8(mul255(uint32(img.Pix[i]), a))
+		rgba.Pix[i+1] = uint8(mul255(uint32(img.Pix[i+1]), a))
+		rgba.Pix[i+2] = uint8(mul255(uint32(img.Pix[i+2]), a))
+		rgba.Pix[i+3] = uint8(a)
+	}
+	return rgba
+}
+
+// mul255 returns a*b/255, rounded.
+func mul255(a, b uint32) uint32 {
+	v := a*b + 128
+	return (v + v>>8) >> 8
+}
+
+// Tag returns the tag with the given name.
+func (f *File) Tag(name string) (Tag, bool) {
+	for _, t := range f.Tags {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Tag{}, false
+}
+
+// Sequence builds the animation of the tag with the given name, or of every frame if name is empty.
+// Frames are shown for their durations in the file, in the order of the tag's direction; the
+// sequence loops regardless of how many times the tag repeats.
+func (f *File) Sequence(name string) (*render.Sequence, error) {
+	t := Tag{To: len(f.Frames) - 1}
+	if name != "" {
+		var ok bool
+		t, ok = f.Tag(name)
+		if !ok {
+			return nil, oakerr.NotFound{InputName: "tag:" + name}
+		}
+	}
+	if t.From < 0 || t.To >= len(f.Frames) || t.From > t.To {
+		return nil, oakerr.InvalidInput{InputName: "tag:" + t.Name + " frames " +
+			strconv.Itoa(t.From) + "-" + strconv.Itoa(t.To)}
+	}
+	frames := t.frames()
+	images := make(map[int]*image.RGBA, t.To-t.From+1)
+	mods := make([]render.Modifiable, len(frames))
+	durations := make([]time.Duration, len(frames))
+	for i, frame := range frames {
+		img, ok := images[frame]
+		if !ok {
+			var err error
+			img, err = f.Image(frame)
+			if err != nil {
+				return nil, err
+			}
+			images[frame] = img
+		}
+		// frames played twice by ping-pong tags share their image
+		mods[i] = render.NewSprite(0, 0, img)
+		durations[i] = f.Frames[frame].Duration
+	}
+	sq := render.NewSequence(0, mods...)
+	if err := sq.SetFrameDurations(durations...); err != nil {
+		return nil, err
+	}
+	return sq, nil
+}
+
+// frames returns the indices of the frames of one loop of this tag.
+func (t Tag) frames() []int {
+	forward := make([]int, 0, t.To-t.From+1)
+	for i := t.From; i <= t.To; i++ {
+		forward = append(forward, i)
+	}
+	backward := make([]int, len(forward))
+	for i, frame := range forward {
+		backward[len(forward)-1-i] = frame
+	}
+	// the end frames are not repeated when a ping-pong animation turns around
+	turn := len(forward) - 1
+	if turn < 1 {
+		turn = 1
+	}
+	switch t.Direction {
+	case Reverse:
+		return backward
+	case PingPong:
+		return append(forward, backward[1:turn]...)
+	case PingPongReverse:
+		return append(backward, forward[1:turn]...)
+	}
+	return forward
+}
+
+// Switch builds a switch of this file's animations, keyed by tag name and starting on its first tag.
+// If this file has no tags, the switch holds a single animation of every frame under DefaultKey.
+func (f *File) Switch() (*render.Switch, error) {
+	if len(f.Frames) == 0 {
+		return nil, oakerr.InvalidInput{InputName: "file without frames"}
+	}
+	if len(f.Tags) == 0 {
+		sq, err := f.Sequence("")
+		if err != nil {
+			return nil, err
+		}
+		return render.NewSwitch(DefaultKey, map[string]render.Modifiable{DefaultKey: sq}), nil
+	}
+	m := make(map[string]render.Modifiable, len(f.Tags))
+	for _, t := range f.Tags {
+		sq, err := f.Sequence(t.Name)
+		if err != nil {
+			return nil, err
+		}
+		m[t.Name] = sq
+	}
+	return render.NewSwitch(f.Tags[0].Name, m), nil
+}
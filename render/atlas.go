@@ -0,0 +1,503 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/draw"
+	"image/png"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/fileutil"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// DefaultAtlasPageSize is the largest size of an atlas page, unless told otherwise.
+var DefaultAtlasPageSize = intgeom.Point2{2048, 2048}
+
+// An Atlas is a set of images packed together into one or more larger pages.
+type Atlas struct {
+	Pages   []*image.RGBA
+	Regions map[string]AtlasRegion
+}
+
+// An AtlasRegion is the area of an atlas page holding one image.
+type AtlasRegion struct {
+	Page   int
+	Bounds intgeom.Rect2
+	// Offset and SourceSize describe images trimmed of their transparent edges when packed. Offset
+	// is where the trimmed image was within the original image, and SourceSize is the size of the
+	// original image. Untrimmed images have no offset, and a source size matching their bounds.
+	Offset     intgeom.Point2
+	SourceSize intgeom.Point2
+	// Duration is how long this image is shown for, if it is a frame of an animation.
+	Duration time.Duration
+}
+
+// Image returns the image of a region of this atlas. The image shares its pixels with the atlas;
+// modifying one modifies the other. Images of trimmed regions are returned trimmed; their region's
+// Offset is where they belong within an image of its SourceSize.
+func (a *Atlas) Image(name string) (*image.RGBA, bool) {
+	rg, ok := a.Regions[name]
+	if !ok || rg.Page < 0 || rg.Page >= len(a.Pages) {
+		return nil, false
+	}
+	page := a.Pages[rg.Page]
+	if !inAtlasPage(page, rg.Bounds) {
+		return nil, false
+	}
+	return atlasImage(page, rg.Bounds), true
+}
+
+// SourceImage returns the image of a region of this atlas at its original size, with trimmed edges
+// restored. Like Image, it shares its pixels with the atlas, unless the region was trimmed.
+func (a *Atlas) SourceImage(name string) (*image.RGBA, bool) {
+	rgba, ok := a.Image(name)
+	if !ok {
+		return nil, false
+	}
+	rg := a.Regions[name]
+	if rg.Offset == (intgeom.Point2{}) && rg.SourceSize == (intgeom.Point2{rg.Bounds.W(), rg.Bounds.H()}) {
+		return rgba, true
+	}
+	src := image.NewRGBA(image.Rect(0, 0, rg.SourceSize.X(), rg.SourceSize.Y()))
+	dst := rgba.Bounds().Add(image.Point{rg.Offset.X(), rg.Offset.Y()})
+	draw.Draw(src, dst, rgba, image.Point{}, draw.Src)
+	return src, true
+}
+
+// Sprite returns a sprite of a region of this atlas, at its original size as SourceImage returns.
+func (a *Atlas) Sprite(name string) (*Sprite, error) {
+	rgba, ok := a.SourceImage(name)
+	if !ok {
+		return nil, oakerr.NotFound{InputName: name}
+	}
+	return NewSprite(0, 0, rgba), nil
+}
+
+// atlasImage returns an image of an area of page, sharing its pixels but with bounds starting at
+// the origin, as renderables expect. The area must be within the page.
+func atlasImage(page *image.RGBA, bds intgeom.Rect2) *image.RGBA {
+	w, h := bds.W(), bds.H()
+	if w <= 0 || h <= 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+	start := page.PixOffset(bds.Min.X(), bds.Min.Y())
+	end := start + (h-1)*page.Stride + w*4
+	return &image.RGBA{
+		Pix:    page.Pix[start:end:end],
+		Stride: page.Stride,
+		Rect:   image.Rect(0, 0, w, h),
+	}
+}
+
+// inAtlasPage reports whether an area is within page.
+func inAtlasPage(page *image.RGBA, bds intgeom.Rect2) bool {
+	return image.Rect(bds.Min.X(), bds.Min.Y(), bds.Max.X(), bds.Max.Y()).In(page.Bounds())
+}
+
+// AtlasOptions control how images are packed into an atlas.
+type AtlasOptions struct {
+	// PageSize is the largest size of a page. If zero, DefaultAtlasPageSize is used.
+	PageSize intgeom.Point2
+	// Padding is the number of transparent pixels left between images.
+	Padding int
+}
+
+// NewAtlas packs images, by name, into the pages of a new atlas. Pages are no larger than needed
+// to hold their images. Images larger than a page cannot be packed.
+func NewAtlas(images map[string]*image.RGBA, opts AtlasOptions) (*Atlas, error) {
+	if opts.PageSize.X() <= 0 || opts.PageSize.Y() <= 0 {
+		opts.PageSize = DefaultAtlasPageSize
+	}
+	if opts.Padding < 0 {
+		return nil, oakerr.InvalidInput{InputName: "opts.Padding"}
+	}
+	names := make([]string, 0, len(images))
+	for name, img := range images {
+		if img == nil {
+			return nil, oakerr.NilInput{InputName: "images[" + name + "]"}
+		}
+		bds := img.Bounds()
+		if bds.Dx() > opts.PageSize.X() || bds.Dy() > opts.PageSize.Y() {
+			return nil, oakerr.InvalidInput{InputName: "images[" + name + "]"}
+		}
+		names = append(names, name)
+	}
+	// Q: Why this order?
+	// A: Shelf packing wastes the least space when the tallest images are placed first. Names break
+	//    ties so packing the same images always produces the same atlas.
+	sort.Slice(names, func(i, j int) bool {
+		bi, bj := images[names[i]].Bounds(), images[names[j]].Bounds()
+		if bi.Dy() != bj.Dy() {
+			return bi.Dy() > bj.Dy()
+		}
+		if bi.Dx() != bj.Dx() {
+			return bi.Dx() > bj.Dx()
+		}
+		return names[i] < names[j]
+	})
+
+	type shelf struct {
+		y, h, x int
+	}
+	type page struct {
+		shelves []shelf
+		// used is the extent of the page's images
+		used intgeom.Point2
+	}
+	var pages []*page
+	a := &Atlas{
+		Regions: make(map[string]AtlasRegion, len(images)),
+	}
+	pad := opts.Padding
+	for _, name := range names {
+		bds := images[name].Bounds()
+		w, h := bds.Dx(), bds.Dy()
+		placed := false
+		for pi := 0; pi < len(pages) && !placed; pi++ {
+			p := pages[pi]
+			pt, ok := intgeom.Point2{}, false
+			for si := range p.shelves {
+				s := &p.shelves[si]
+				if h <= s.h && s.x+w <= opts.PageSize.X() {
+					pt, ok = intgeom.Point2{s.x, s.y}, true
+					s.x += w + pad
+					break
+				}
+			}
+			if !ok {
+				y := 0
+				if len(p.shelves) > 0 {
+					last := p.shelves[len(p.shelves)-1]
+					y = last.y + last.h + pad
+				}
+				if y+h > opts.PageSize.Y() {
+					continue
+				}
+				p.shelves = append(p.shelves, shelf{y: y, h: h, x: w + pad})
+				pt = intgeom.Point2{0, y}
+			}
+			a.Regions[name] = AtlasRegion{
+				Page:       pi,
+				Bounds:     intgeom.NewRect2WH(pt.X(), pt.Y(), w, h),
+				SourceSize: intgeom.Point2{w, h},
+			}
+			p.used = intgeom.Point2{maxInt(p.used.X(), pt.X()+w), maxInt(p.used.Y(), pt.Y()+h)}
+			placed = true
+		}
+		if !placed {
+			pages = append(pages, &page{
+				shelves: []shelf{{y: 0, h: h, x: w + pad}},
+				used:    intgeom.Point2{w, h},
+			})
+			a.Regions[name] = AtlasRegion{
+				Page:       len(pages) - 1,
+				Bounds:     intgeom.NewRect2WH(0, 0, w, h),
+				SourceSize: intgeom.Point2{w, h},
+			}
+		}
+	}
+	a.Pages = make([]*image.RGBA, len(pages))
+	for i, p := range pages {
+		a.Pages[i] = image.NewRGBA(image.Rect(0, 0, p.used.X(), p.used.Y()))
+	}
+	for name, rg := range a.Regions {
+		img := images[name]
+		r := image.Rect(rg.Bounds.Min.X(), rg.Bounds.Min.Y(), rg.Bounds.Max.X(), rg.Bounds.Max.Y())
+		draw.Draw(a.Pages[rg.Page], r, img, img.Bounds().Min, draw.Src)
+	}
+	return a, nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// PackAtlas calls PackAtlas on the Default Cache.
+func PackAtlas(opts AtlasOptions, files ...string) (*Atlas, error) {
+	return DefaultCache.PackAtlas(opts, files...)
+}
+
+// LoadAtlas calls LoadAtlas on the Default Cache.
+func LoadAtlas(file string) (*Atlas, error) {
+	return DefaultCache.LoadAtlas(file)
+}
+
+// PackAtlas packs images already loaded into this cache into a new atlas. If no files are given,
+// every loaded image is packed. Afterwards, GetSprite on any of the files returns a sprite sharing
+// its pixels with the atlas, and the separately loaded images can be freed.
+func (c *Cache) PackAtlas(opts AtlasOptions, files ...string) (*Atlas, error) {
+	c.imageLock.Lock()
+	defer c.imageLock.Unlock()
+	if len(files) == 0 {
+		for file := range c.loadedImages {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+	}
+	// images are cached under more than one name; each is only packed once
+	names := make(map[*image.RGBA]string)
+	images := make(map[string]*image.RGBA)
+	for _, file := range files {
+		rgba, ok := c.loadedImages[file]
+		if !ok {
+			return nil, oakerr.NotFound{InputName: file}
+		}
+		if _, ok := names[rgba]; ok {
+			continue
+		}
+		names[rgba] = file
+		images[file] = rgba
+	}
+	a, err := NewAtlas(images, opts)
+	if err != nil {
+		return nil, err
+	}
+	for file, rgba := range c.loadedImages {
+		if name, ok := names[rgba]; ok {
+			c.loadedImages[file], _ = a.Image(name)
+		}
+	}
+	return a, nil
+}
+
+// AddAtlas caches every region of an atlas by name, so GetSprite returns sprites of their original
+// size, as Atlas.Sprite does.
+func (c *Cache) AddAtlas(a *Atlas) {
+	c.imageLock.Lock()
+	for name := range a.Regions {
+		if rgba, ok := a.SourceImage(name); ok {
+			c.loadedImages[name] = rgba
+		}
+	}
+	c.imageLock.Unlock()
+}
+
+// LoadAtlas loads an atlas from a descriptor file and the page images it references, and adds it
+// to this cache as AddAtlas does. Descriptors may be written by SaveAtlas, or be JSON exported by
+// TexturePacker or Aseprite, in hash or array form. Page images are found relative to the
+// descriptor.
+//
+// Images TexturePacker rotated to pack them are rotated back into pages of their own, as they
+// cannot share pixels with their atlas.
+func (c *Cache) LoadAtlas(file string) (*Atlas, error) {
+	data, err := fileutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var desc atlasDescriptor
+	if err := json.Unmarshal(data, &desc); err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(file)
+	var a *Atlas
+	switch {
+	case len(desc.Pages) != 0:
+		a, err = desc.load(dir)
+	case desc.Frames != nil:
+		a = &Atlas{Regions: make(map[string]AtlasRegion)}
+		err = a.loadPackerTexture(dir, desc.Meta.Image, desc.Frames)
+	case len(desc.Textures) != 0:
+		// multiple pages, as exported by TexturePacker for Phaser 3
+		a = &Atlas{Regions: make(map[string]AtlasRegion)}
+		for _, tex := range desc.Textures {
+			if err = a.loadPackerTexture(dir, tex.Image, tex.Frames); err != nil {
+				break
+			}
+		}
+	default:
+		err = oakerr.UnsupportedFormat{Format: "atlas without pages or frames"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.AddAtlas(a)
+	return a, nil
+}
+
+// SaveAtlas writes an atlas descriptor to file, and each of its pages as a png next to it, which
+// LoadAtlas can load again.
+func SaveAtlas(a *Atlas, file string) error {
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	desc := atlasDescriptor{
+		Regions: make(map[string]atlasRegion, len(a.Regions)),
+	}
+	for i, page := range a.Pages {
+		name := base + "-" + strconv.Itoa(i) + ".png"
+		buf := new(bytes.Buffer)
+		if err := png.Encode(buf, page); err != nil {
+			return err
+		}
+		if err := fileutil.WriteFile(filepath.Join(filepath.Dir(file), name), buf.Bytes()); err != nil {
+			return err
+		}
+		desc.Pages = append(desc.Pages, name)
+	}
+	for name, rg := range a.Regions {
+		desc.Regions[name] = atlasRegion{
+			Page:       rg.Page,
+			X:          rg.Bounds.Min.X(),
+			Y:          rg.Bounds.Min.Y(),
+			W:          rg.Bounds.W(),
+			H:          rg.Bounds.H(),
+			OffsetX:    rg.Offset.X(),
+			OffsetY:    rg.Offset.Y(),
+			SourceW:    rg.SourceSize.X(),
+			SourceH:    rg.SourceSize.Y(),
+			DurationMS: int(rg.Duration / time.Millisecond),
+		}
+	}
+	data, err := json.MarshalIndent(desc, "", "\t")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(file, data)
+}
+
+// atlasDescriptor holds the fields of both this package's atlas descriptors and TexturePacker and
+// Aseprite's JSON.
+type atlasDescriptor struct {
+	Pages   []string               `json:"pages,omitempty"`
+	Regions map[string]atlasRegion `json:"regions,omitempty"`
+
+	Frames   json.RawMessage `json:"frames,omitempty"`
+	Textures []struct {
+		Image  string          `json:"image"`
+		Frames json.RawMessage `json:"frames"`
+	} `json:"textures,omitempty"`
+	Meta struct {
+		Image string `json:"image"`
+	} `json:"meta,omitempty"`
+}
+
+type atlasRegion struct {
+	Page       int `json:"page"`
+	X          int `json:"x"`
+	Y          int `json:"y"`
+	W          int `json:"w"`
+	H          int `json:"h"`
+	OffsetX    int `json:"offsetX,omitempty"`
+	OffsetY    int `json:"offsetY,omitempty"`
+	SourceW    int `json:"sourceW,omitempty"`
+	SourceH    int `json:"sourceH,omitempty"`
+	DurationMS int `json:"duration,omitempty"`
+}
+
+func (desc atlasDescriptor) load(dir string) (*Atlas, error) {
+	a := &Atlas{Regions: make(map[string]AtlasRegion, len(desc.Regions))}
+	for _, page := range desc.Pages {
+		rgba, err := loadSpriteNoCache(filepath.Join(dir, page), 0)
+		if err != nil {
+			return nil, err
+		}
+		a.Pages = append(a.Pages, rgba)
+	}
+	for name, rg := range desc.Regions {
+		if rg.Page < 0 || rg.Page >= len(a.Pages) {
+			return nil, oakerr.InvalidInput{InputName: "regions[" + name + "].page"}
+		}
+		if rg.W < 0 || rg.H < 0 || !inAtlasPage(a.Pages[rg.Page], intgeom.NewRect2WH(rg.X, rg.Y, rg.W, rg.H)) {
+			return nil, oakerr.InvalidInput{InputName: "regions[" + name + "]"}
+		}
+		src := intgeom.Point2{rg.SourceW, rg.SourceH}
+		if src == (intgeom.Point2{}) {
+			src = intgeom.Point2{rg.W, rg.H}
+		}
+		a.Regions[name] = AtlasRegion{
+			Page:       rg.Page,
+			Bounds:     intgeom.NewRect2WH(rg.X, rg.Y, rg.W, rg.H),
+			Offset:     intgeom.Point2{rg.OffsetX, rg.OffsetY},
+			SourceSize: src,
+			Duration:   time.Duration(rg.DurationMS) * time.Millisecond,
+		}
+	}
+	return a, nil
+}
+
+type packerRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type packerFrame struct {
+	Filename         string     `json:"filename"`
+	Frame            packerRect `json:"frame"`
+	Rotated          bool       `json:"rotated"`
+	SpriteSourceSize packerRect `json:"spriteSourceSize"`
+	SourceSize       struct {
+		W int `json:"w"`
+		H int `json:"h"`
+	} `json:"sourceSize"`
+	// Duration is in milliseconds, and only exported by Aseprite
+	Duration int `json:"duration"`
+}
+
+// loadPackerTexture adds a page and its frames in TexturePacker's JSON format, where frames are
+// either a map of names to frames or an array of frames with filenames.
+func (a *Atlas) loadPackerTexture(dir, pageFile string, rawFrames json.RawMessage) error {
+	var frames []packerFrame
+	if err := json.Unmarshal(rawFrames, &frames); err != nil {
+		byName := make(map[string]packerFrame)
+		if err := json.Unmarshal(rawFrames, &byName); err != nil {
+			return err
+		}
+		for name, f := range byName {
+			f.Filename = name
+			frames = append(frames, f)
+		}
+	}
+	if pageFile == "" {
+		return oakerr.InvalidInput{InputName: "meta.image"}
+	}
+	page, err := loadSpriteNoCache(filepath.Join(dir, pageFile), 0)
+	if err != nil {
+		return err
+	}
+	pageIndex := len(a.Pages)
+	a.Pages = append(a.Pages, page)
+	for _, f := range frames {
+		stored := intgeom.NewRect2WH(f.Frame.X, f.Frame.Y, f.Frame.W, f.Frame.H)
+		if f.Rotated {
+			stored = intgeom.NewRect2WH(f.Frame.X, f.Frame.Y, f.Frame.H, f.Frame.W)
+		}
+		if f.Frame.W < 0 || f.Frame.H < 0 || !inAtlasPage(page, stored) {
+			return oakerr.InvalidInput{InputName: "frames[" + f.Filename + "]"}
+		}
+		rg := AtlasRegion{
+			Page:       pageIndex,
+			Bounds:     intgeom.NewRect2WH(f.Frame.X, f.Frame.Y, f.Frame.W, f.Frame.H),
+			Offset:     intgeom.Point2{f.SpriteSourceSize.X, f.SpriteSourceSize.Y},
+			SourceSize: intgeom.Point2{f.SourceSize.W, f.SourceSize.H},
+			Duration:   time.Duration(f.Duration) * time.Millisecond,
+		}
+		if rg.SourceSize == (intgeom.Point2{}) {
+			rg.SourceSize = intgeom.Point2{f.Frame.W, f.Frame.H}
+		}
+		if f.Rotated {
+			// the frame is stored rotated 90 degrees clockwise, occupying h by w pixels
+			w, h := f.Frame.W, f.Frame.H
+			unrotated := image.NewRGBA(image.Rect(0, 0, w, h))
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					unrotated.SetRGBA(x, y, page.RGBAAt(f.Frame.X+h-1-y, f.Frame.Y+x))
+				}
+			}
+			rg.Page = len(a.Pages)
+			rg.Bounds = intgeom.NewRect2WH(0, 0, w, h)
+			a.Pages = append(a.Pages, unrotated)
+		}
+		a.Regions[f.Filename] = rg
+	}
+	return nil
+}
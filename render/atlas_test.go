@@ -0,0 +1,300 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+func solidImage(w, h int, c color.RGBA) *image.RGBA {
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(rgba, rgba.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return rgba
+}
+
+// sharesPixels reports whether img's pixels are within page's.
+func sharesPixels(img, page *image.RGBA) bool {
+	if len(img.Pix) == 0 || len(page.Pix) == 0 {
+		return false
+	}
+	for i := range page.Pix {
+		if &page.Pix[i] == &img.Pix[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNewAtlas(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	images := map[string]*image.RGBA{
+		"red":   solidImage(4, 6, red),
+		"green": solidImage(3, 2, green),
+		"blue":  solidImage(5, 2, blue),
+	}
+	a, err := NewAtlas(images, AtlasOptions{PageSize: intgeom.Point2{10, 10}, Padding: 1})
+	if err != nil {
+		t.Fatalf("failed to pack atlas: %v", err)
+	}
+	if len(a.Pages) != 1 {
+		t.Fatalf("expected one page, got %v", len(a.Pages))
+	}
+	for name, want := range map[string]color.RGBA{"red": red, "green": green, "blue": blue} {
+		img, ok := a.Image(name)
+		if !ok {
+			t.Fatalf("expected %v in atlas", name)
+		}
+		if img.Bounds() != images[name].Bounds() {
+			t.Fatalf("expected %v bounds %v, got %v", name, images[name].Bounds(), img.Bounds())
+		}
+		b := img.Bounds()
+		if img.RGBAAt(0, 0) != want || img.RGBAAt(b.Dx()-1, b.Dy()-1) != want {
+			t.Fatalf("expected %v image to be %v", name, want)
+		}
+		if !sharesPixels(img, a.Pages[0]) {
+			t.Fatalf("expected %v image to share pixels with its page", name)
+		}
+	}
+	// regions do not overlap, and are padded
+	for n1, r1 := range a.Regions {
+		for n2, r2 := range a.Regions {
+			padded := intgeom.NewRect2(r1.Bounds.Min.X(), r1.Bounds.Min.Y(), r1.Bounds.Max.X()+1, r1.Bounds.Max.Y()+1)
+			if n1 != n2 && padded.Intersects(r2.Bounds) {
+				t.Fatalf("expected %v and %v not to overlap", n1, n2)
+			}
+		}
+	}
+
+	t.Run("MultiplePages", func(t *testing.T) {
+		a, err := NewAtlas(map[string]*image.RGBA{
+			"a": solidImage(6, 6, red),
+			"b": solidImage(6, 6, green),
+		}, AtlasOptions{PageSize: intgeom.Point2{8, 8}})
+		if err != nil {
+			t.Fatalf("failed to pack atlas: %v", err)
+		}
+		if len(a.Pages) != 2 || a.Regions["a"].Page == a.Regions["b"].Page {
+			t.Fatal("expected images packed onto separate pages")
+		}
+	})
+	t.Run("TooLarge", func(t *testing.T) {
+		_, err := NewAtlas(map[string]*image.RGBA{"a": solidImage(9, 1, red)}, AtlasOptions{PageSize: intgeom.Point2{8, 8}})
+		if err == nil {
+			t.Fatal("expected image larger than a page to fail to pack")
+		}
+	})
+}
+
+func TestCache_PackAtlas(t *testing.T) {
+	c := NewCache()
+	red := solidImage(2, 2, color.RGBA{255, 0, 0, 255})
+	c.loadedImages["assets/red.png"] = red
+	c.loadedImages["red.png"] = red
+	c.loadedImages["blue.png"] = solidImage(3, 3, color.RGBA{0, 0, 255, 255})
+	if _, err := c.PackAtlas(AtlasOptions{}, "missing.png"); err == nil {
+		t.Fatal("expected packing an unloaded image to fail")
+	}
+	a, err := c.PackAtlas(AtlasOptions{})
+	if err != nil {
+		t.Fatalf("failed to pack atlas: %v", err)
+	}
+	if len(a.Regions) != 2 {
+		t.Fatalf("expected an image cached under two names to be packed once, got %v regions", len(a.Regions))
+	}
+	for _, file := range []string{"assets/red.png", "red.png"} {
+		s, err := c.GetSprite(file)
+		if err != nil {
+			t.Fatalf("failed to get sprite: %v", err)
+		}
+		if !sharesPixels(s.GetRGBA(), a.Pages[0]) {
+			t.Fatalf("expected %v to share pixels with the atlas", file)
+		}
+		if s.GetRGBA().RGBAAt(1, 1) != (color.RGBA{255, 0, 0, 255}) {
+			t.Fatalf("expected %v to be red", file)
+		}
+	}
+}
+
+func TestAtlas_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	a, err := NewAtlas(map[string]*image.RGBA{
+		"red":  solidImage(2, 2, color.RGBA{255, 0, 0, 255}),
+		"blue": solidImage(3, 1, color.RGBA{0, 0, 255, 255}),
+	}, AtlasOptions{})
+	if err != nil {
+		t.Fatalf("failed to pack atlas: %v", err)
+	}
+	file := filepath.Join(dir, "atlas.json")
+	if err := SaveAtlas(a, file); err != nil {
+		t.Fatalf("failed to save atlas: %v", err)
+	}
+	c := NewCache()
+	a2, err := c.LoadAtlas(file)
+	if err != nil {
+		t.Fatalf("failed to load atlas: %v", err)
+	}
+	if len(a2.Pages) != len(a.Pages) {
+		t.Fatalf("expected %v pages, got %v", len(a.Pages), len(a2.Pages))
+	}
+	for name, rg := range a.Regions {
+		if a2.Regions[name] != rg {
+			t.Fatalf("expected region %v to be %v, got %v", name, rg, a2.Regions[name])
+		}
+	}
+	s, err := c.GetSprite("blue")
+	if err != nil {
+		t.Fatalf("failed to get sprite: %v", err)
+	}
+	if w, h := s.GetDims(); w != 3 || h != 1 || s.GetRGBA().RGBAAt(2, 0) != (color.RGBA{0, 0, 255, 255}) {
+		t.Fatal("expected loaded sprite to match saved image")
+	}
+}
+
+func TestCache_LoadAtlas_TexturePacker(t *testing.T) {
+	dir := t.TempDir()
+	red := color.RGBA{255, 0, 0, 255}
+	// a page holding a 2x2 red frame, and a 3x1 frame rotated clockwise into a 1x3 column, red at
+	// its left end
+	page := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	draw.Draw(page, image.Rect(0, 0, 2, 2), image.NewUniform(red), image.Point{}, draw.Src)
+	page.SetRGBA(3, 0, red)
+	page.SetRGBA(3, 1, color.RGBA{0, 255, 0, 255})
+	page.SetRGBA(3, 2, color.RGBA{0, 0, 255, 255})
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, page); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sheet.png"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Hash", func(t *testing.T) {
+		file := filepath.Join(dir, "hash.json")
+		err := os.WriteFile(file, []byte(`{
+			"frames": {
+				"square.png": {
+					"frame": {"x": 0, "y": 0, "w": 2, "h": 2},
+					"rotated": false,
+					"trimmed": true,
+					"spriteSourceSize": {"x": 1, "y": 1, "w": 2, "h": 2},
+					"sourceSize": {"w": 4, "h": 4}
+				},
+				"bar.png": {
+					"frame": {"x": 3, "y": 0, "w": 3, "h": 1},
+					"rotated": true,
+					"trimmed": false,
+					"spriteSourceSize": {"x": 0, "y": 0, "w": 3, "h": 1},
+					"sourceSize": {"w": 3, "h": 1}
+				}
+			},
+			"meta": {"image": "sheet.png", "size": {"w": 4, "h": 3}}
+		}`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := NewCache()
+		a, err := c.LoadAtlas(file)
+		if err != nil {
+			t.Fatalf("failed to load atlas: %v", err)
+		}
+		sq := a.Regions["square.png"]
+		if sq.Offset != (intgeom.Point2{1, 1}) || sq.SourceSize != (intgeom.Point2{4, 4}) {
+			t.Fatalf("expected trimmed region, got %v", sq)
+		}
+		if img, _ := a.Image("square.png"); !sharesPixels(img, a.Pages[0]) {
+			t.Fatal("expected trimmed image to share pixels with the atlas")
+		}
+		s, err := c.GetSprite("square.png")
+		if err != nil {
+			t.Fatalf("failed to get sprite: %v", err)
+		}
+		rgba := s.GetRGBA()
+		if rgba.Bounds().Dx() != 4 || rgba.Bounds().Dy() != 4 {
+			t.Fatalf("expected sprite of the source size, got bounds %v", rgba.Bounds())
+		}
+		if rgba.RGBAAt(0, 0) != (color.RGBA{}) || rgba.RGBAAt(1, 1) != red || rgba.RGBAAt(3, 3) != (color.RGBA{}) {
+			t.Fatal("expected trimmed frame to be drawn at its offset")
+		}
+		bar, err := c.GetSprite("bar.png")
+		if err != nil {
+			t.Fatalf("failed to get sprite: %v", err)
+		}
+		rgba = bar.GetRGBA()
+		if rgba.Bounds().Dx() != 3 || rgba.Bounds().Dy() != 1 {
+			t.Fatalf("expected rotated frame to be unrotated, got bounds %v", rgba.Bounds())
+		}
+		if rgba.RGBAAt(0, 0) != red || rgba.RGBAAt(2, 0) != (color.RGBA{0, 0, 255, 255}) {
+			t.Fatal("expected rotated frame's pixels to be unrotated")
+		}
+	})
+	t.Run("Array", func(t *testing.T) {
+		// as exported by Aseprite
+		file := filepath.Join(dir, "array.json")
+		err := os.WriteFile(file, []byte(`{
+			"frames": [
+				{
+					"filename": "walk 0.aseprite",
+					"frame": {"x": 0, "y": 0, "w": 2, "h": 2},
+					"rotated": false,
+					"trimmed": false,
+					"spriteSourceSize": {"x": 0, "y": 0, "w": 2, "h": 2},
+					"sourceSize": {"w": 2, "h": 2},
+					"duration": 150
+				}
+			],
+			"meta": {"app": "https://www.aseprite.org/", "image": "sheet.png", "frameTags": []}
+		}`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := NewCache().LoadAtlas(file)
+		if err != nil {
+			t.Fatalf("failed to load atlas: %v", err)
+		}
+		if a.Regions["walk 0.aseprite"].Duration != 150*time.Millisecond {
+			t.Fatalf("expected frame duration, got %v", a.Regions["walk 0.aseprite"])
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		file := filepath.Join(dir, "invalid.json")
+		if err := os.WriteFile(file, []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewCache().LoadAtlas(file); err == nil {
+			t.Fatal("expected atlas without pages or frames to fail to load")
+		}
+		outOfBounds := map[string]string{
+			"frames.json": `{
+				"frames": {"big.png": {"frame": {"x": 0, "y": 0, "w": 10, "h": 10}}},
+				"meta": {"image": "sheet.png"}
+			}`,
+			"rotated.json": `{
+				"frames": {"big.png": {"frame": {"x": 3, "y": 0, "w": 1, "h": 2}, "rotated": true}},
+				"meta": {"image": "sheet.png"}
+			}`,
+			"regions.json": `{
+				"pages": ["sheet.png"],
+				"regions": {"big": {"page": 0, "x": 2, "y": 2, "w": 3, "h": 1}}
+			}`,
+		}
+		for name, desc := range outOfBounds {
+			file := filepath.Join(dir, name)
+			if err := os.WriteFile(file, []byte(desc), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewCache().LoadAtlas(file); err == nil {
+				t.Fatalf("expected %v with regions outside their page to fail to load", name)
+			}
+		}
+	})
+}
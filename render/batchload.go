@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/oakmound/oak/v4/alg/intgeom"
@@ -41,17 +42,8 @@ func BlankBatchLoad(baseFolder string, maxFileSize int64) error {
 				// Ignore files we know we can't parse
 				return
 			}
-			_, err := DefaultCache.loadSprite(file, maxFileSize)
-			if err != nil {
+			if err := loadFile(file, maxFileSize); err != nil {
 				dlog.Error(err)
-				return
-			}
-			if cell, ok := shouldLoadSheet(file); ok {
-				_, err = DefaultCache.LoadSheet(file, cell)
-				if err != nil {
-					dlog.Error(err)
-					return
-				}
 			}
 		}(file)
 		return nil
@@ -60,6 +52,27 @@ func BlankBatchLoad(baseFolder string, maxFileSize int64) error {
 	return err
 }
 
+// LoadFile loads a single image file into DefaultCache, following the same rules as BatchLoad
+// to determine whether it should also be loaded as a sheet.
+func LoadFile(file string) error {
+	ext := strings.ToLower(filepath.Ext(file))
+	if _, ok := fileDecoders[ext]; !ok {
+		return oakerr.UnsupportedFormat{Format: ext}
+	}
+	return loadFile(file, 0)
+}
+
+func loadFile(file string, maxFileSize int64) error {
+	_, err := DefaultCache.loadSprite(file, maxFileSize)
+	if err != nil {
+		return err
+	}
+	if cell, ok := shouldLoadSheet(file); ok {
+		_, err = DefaultCache.LoadSheet(file, cell)
+	}
+	return err
+}
+
 var (
 	sheetFileRegex      = regexp.MustCompile(`^[^\d]*(\d+)x(\d+)\..*$`)
 	sheetDirectoryRegex = regexp.MustCompile(`^[^\d]*(\d+)x(\d+)$`)
@@ -0,0 +1,210 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// A BlendMode is how the colors of a renderable combine with the colors already drawn beneath it.
+type BlendMode uint8
+
+// Blend modes
+const (
+	// BlendNormal draws colors over those beneath them, as DrawImage does.
+	BlendNormal BlendMode = iota
+	// BlendAdd adds colors to those beneath them, brightening them. Useful for glows and fire.
+	BlendAdd
+	// BlendMultiply multiplies colors with those beneath them, darkening them. Useful for shadows.
+	BlendMultiply
+	// BlendScreen inverts, multiplies, and inverts again, brightening colors more gently than
+	// BlendAdd.
+	BlendScreen
+	// BlendSubtract subtracts colors from those beneath them.
+	BlendSubtract
+)
+
+// SetBlendMode sets how this is blended with what is drawn beneath it. Blend modes are honored by
+// Sprites, Sequences, Switches and CompositeRs.
+func (ldp *LayeredPoint) SetBlendMode(mode BlendMode) {
+	ldp.blend = mode
+}
+
+// GetBlendMode returns how this is blended with what is drawn beneath it.
+func (ldp *LayeredPoint) GetBlendMode() BlendMode {
+	return ldp.blend
+}
+
+type blendable interface {
+	GetBlendMode() BlendMode
+}
+
+// hasDrawOptions reports whether this has a transform or blend mode to draw with.
+func (ldp *LayeredPoint) hasDrawOptions() bool {
+	return ldp.transform != nil || ldp.blend != BlendNormal
+}
+
+// drawRGBA draws img at x, y with this point's transform and blend mode.
+func (ldp *LayeredPoint) drawRGBA(buff draw.Image, img *image.RGBA, x, y float64) {
+	switch {
+	case ldp.transform != nil:
+		drawTransformed(buff, img, x, y, *ldp.transform, ldp.blend)
+	case ldp.blend != BlendNormal:
+		DrawImageBlend(buff, img, int(x), int(y), ldp.blend)
+	default:
+		DrawImage(buff, img, int(x), int(y))
+	}
+}
+
+// DrawImageBlend is equivalent to DrawImage, but combines img with buff using the given blend mode.
+func DrawImageBlend(buff draw.Image, img image.Image, x, y int, mode BlendMode) {
+	if mode == BlendNormal {
+		DrawImage(buff, img, x, y)
+		return
+	}
+	offset := image.Point{x, y}
+	area := img.Bounds().Add(offset).Intersect(buff.Bounds())
+	if area.Empty() {
+		return
+	}
+	src, srcRGBA := img.(*image.RGBA)
+	blendArea(buff, area, func(dst *image.RGBA) {
+		rowLen := area.Dx() * 4
+		for py := area.Min.Y; py < area.Max.Y; py++ {
+			if srcRGBA {
+				di := dst.PixOffset(area.Min.X, py)
+				si := src.PixOffset(area.Min.X-x, py-y)
+				blendRow(dst.Pix[di:di+rowLen], src.Pix[si:si+rowLen], mode)
+				continue
+			}
+			for px := area.Min.X; px < area.Max.X; px++ {
+				rgba := color.RGBAModel.Convert(img.At(px-x, py-y)).(color.RGBA)
+				c := [4]uint8{rgba.R, rgba.G, rgba.B, rgba.A}
+				if c == ([4]uint8{}) {
+					continue
+				}
+				blendPixel(dst.Pix[dst.PixOffset(px, py):], c, mode)
+			}
+		}
+	})
+}
+
+// blendRow combines the premultiplied pixels of src with those of dst, which are the same length.
+// It is equivalent to calling blendPixel on each pixel, but chooses how to blend once per row.
+func blendRow(dst, src []uint8, mode BlendMode) {
+	switch mode {
+	case BlendAdd:
+		for i := 0; i+3 < len(src); i += 4 {
+			if src[i+3] == 0 && src[i] == 0 && src[i+1] == 0 && src[i+2] == 0 {
+				continue
+			}
+			d, s := dst[i:i+4:i+4], src[i:i+4:i+4]
+			a := uint32(s[3]) + uint32(d[3]) - mul255(uint32(s[3]), uint32(d[3]))
+			d[0] = uint8(min32(uint32(s[0])+uint32(d[0]), a))
+			d[1] = uint8(min32(uint32(s[1])+uint32(d[1]), a))
+			d[2] = uint8(min32(uint32(s[2])+uint32(d[2]), a))
+			d[3] = uint8(a)
+		}
+	case BlendMultiply:
+		for i := 0; i+3 < len(src); i += 4 {
+			d, s := dst[i:i+4:i+4], src[i:i+4:i+4]
+			sa, da := uint32(s[3]), uint32(d[3])
+			if sa == 255 && da == 255 {
+				// the common case of opaque colors
+				d[0] = uint8(mul255(uint32(s[0]), uint32(d[0])))
+				d[1] = uint8(mul255(uint32(s[1]), uint32(d[1])))
+				d[2] = uint8(mul255(uint32(s[2]), uint32(d[2])))
+				continue
+			}
+			blendPixel(d, [4]uint8{s[0], s[1], s[2], s[3]}, mode)
+		}
+	default:
+		for i := 0; i+3 < len(src); i += 4 {
+			c := [4]uint8{src[i], src[i+1], src[i+2], src[i+3]}
+			if c == ([4]uint8{}) {
+				continue
+			}
+			blendPixel(dst[i:i+4:i+4], c, mode)
+		}
+	}
+}
+
+func min32(a, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// BlendColor combines a single color with the pixel of buff at x, y using the given blend mode.
+func BlendColor(buff draw.Image, x, y int, c color.Color, mode BlendMode) {
+	if !(image.Point{x, y}).In(buff.Bounds()) {
+		return
+	}
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	if dst, ok := buff.(*image.RGBA); ok {
+		blendPixel(dst.Pix[dst.PixOffset(x, y):], [4]uint8{rgba.R, rgba.G, rgba.B, rgba.A}, mode)
+		return
+	}
+	d := color.RGBAModel.Convert(buff.At(x, y)).(color.RGBA)
+	px := [4]uint8{d.R, d.G, d.B, d.A}
+	blendPixel(px[:], [4]uint8{rgba.R, rgba.G, rgba.B, rgba.A}, mode)
+	buff.Set(x, y, color.RGBA{px[0], px[1], px[2], px[3]})
+}
+
+// blendArea calls fn with an RGBA image holding the contents of buff within area, writing any
+// changes fn makes back to buff.
+func blendArea(buff draw.Image, area image.Rectangle, fn func(dst *image.RGBA)) {
+	if dst, ok := buff.(*image.RGBA); ok {
+		fn(dst)
+		return
+	}
+	tmp := image.NewRGBA(area)
+	draw.Draw(tmp, area, buff, area.Min, draw.Src)
+	fn(tmp)
+	draw.Draw(buff, area, tmp, area.Min, draw.Src)
+}
+
+// blendPixel combines the premultiplied color src with the pixel at the start of dst.
+func blendPixel(dst []uint8, src [4]uint8, mode BlendMode) {
+	sa, da := uint32(src[3]), uint32(dst[3])
+	switch mode {
+	case BlendAdd, BlendMultiply, BlendScreen:
+		a := sa + da - mul255(sa, da)
+		for c := 0; c < 3; c++ {
+			s, d := uint32(src[c]), uint32(dst[c])
+			var v uint32
+			switch mode {
+			case BlendAdd:
+				v = s + d
+			case BlendMultiply:
+				v = mul255(s, 255-da) + mul255(d, 255-sa) + mul255(s, d)
+			case BlendScreen:
+				v = s + d - mul255(s, d)
+			}
+			// premultiplied colors may not exceed their alpha
+			if v > a {
+				v = a
+			}
+			dst[c] = uint8(v)
+		}
+		dst[3] = uint8(a)
+	case BlendSubtract:
+		for c := 0; c < 3; c++ {
+			if src[c] >= dst[c] {
+				dst[c] = 0
+			} else {
+				dst[c] -= src[c]
+			}
+		}
+	default:
+		blendOver(dst, src)
+	}
+}
+
+// mul255 multiplies two color channels, scaled to 0-255.
+func mul255(a, b uint32) uint32 {
+	// an exact, rounded division by 255
+	v := a*b + 128
+	return (v + v>>8) >> 8
+}
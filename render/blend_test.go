@@ -0,0 +1,116 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestBlendPixel(t *testing.T) {
+	type testCase struct {
+		mode     BlendMode
+		dst, src [4]uint8
+		expected [4]uint8
+	}
+	tcs := map[string]testCase{
+		"Normal":         {BlendNormal, [4]uint8{100, 100, 100, 255}, [4]uint8{50, 0, 0, 128}, [4]uint8{100, 50, 50, 255}},
+		"Add":            {BlendAdd, [4]uint8{100, 200, 0, 255}, [4]uint8{100, 100, 0, 255}, [4]uint8{200, 255, 0, 255}},
+		"Multiply":       {BlendMultiply, [4]uint8{200, 100, 255, 255}, [4]uint8{128, 255, 0, 255}, [4]uint8{100, 100, 0, 255}},
+		"Screen":         {BlendScreen, [4]uint8{0, 255, 128, 255}, [4]uint8{128, 0, 128, 255}, [4]uint8{128, 255, 192, 255}},
+		"Subtract":       {BlendSubtract, [4]uint8{100, 50, 0, 255}, [4]uint8{50, 100, 10, 255}, [4]uint8{50, 0, 0, 255}},
+		"AddTransparent": {BlendAdd, [4]uint8{}, [4]uint8{100, 0, 0, 100}, [4]uint8{100, 0, 0, 100}},
+	}
+	for name, tc := range tcs {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			dst := tc.dst
+			blendPixel(dst[:], tc.src, tc.mode)
+			if dst != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, dst)
+			}
+		})
+	}
+}
+
+func TestBlendMode_Draw(t *testing.T) {
+	gray := color.RGBA{100, 100, 100, 255}
+	glow := color.RGBA{100, 50, 0, 255}
+	expected := color.RGBA{200, 150, 100, 255}
+	grayBuff := func() *image.RGBA {
+		buff := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		for x := 0; x < 4; x++ {
+			for y := 0; y < 4; y++ {
+				buff.SetRGBA(x, y, gray)
+			}
+		}
+		return buff
+	}
+	glowBox := func() *Sprite {
+		s := NewColorBox(2, 2, glow)
+		s.SetBlendMode(BlendAdd)
+		return s
+	}
+	t.Run("Sprite", func(t *testing.T) {
+		buff := grayBuff()
+		glowBox().Draw(buff, 1, 1)
+		if buff.RGBAAt(1, 1) != expected || buff.RGBAAt(2, 2) != expected || buff.RGBAAt(0, 0) != gray {
+			t.Fatal("expected sprite added to buffer")
+		}
+	})
+	t.Run("Sequence", func(t *testing.T) {
+		buff := grayBuff()
+		sq := NewSequence(1, NewColorBox(2, 2, glow))
+		sq.SetBlendMode(BlendAdd)
+		sq.Draw(buff, 0, 0)
+		if buff.RGBAAt(1, 1) != expected {
+			t.Fatal("expected sequence added to buffer")
+		}
+	})
+	t.Run("Switch", func(t *testing.T) {
+		buff := grayBuff()
+		sw := NewSwitch("a", map[string]Modifiable{"a": NewColorBox(2, 2, glow)})
+		sw.SetBlendMode(BlendAdd)
+		sw.Draw(buff, 0, 0)
+		if buff.RGBAAt(1, 1) != expected {
+			t.Fatal("expected switch added to buffer")
+		}
+	})
+	t.Run("CompositeR", func(t *testing.T) {
+		buff := grayBuff()
+		cs := NewCompositeR(NewColorBox(2, 2, glow))
+		cs.SetBlendMode(BlendAdd)
+		cs.Draw(buff, 1, 1)
+		if buff.RGBAAt(1, 1) != expected || buff.RGBAAt(0, 0) != gray {
+			t.Fatal("expected composite's renderables added to buffer")
+		}
+	})
+	t.Run("NRGBA", func(t *testing.T) {
+		buff := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+		for x := 0; x < 4; x++ {
+			for y := 0; y < 4; y++ {
+				buff.Set(x, y, gray)
+			}
+		}
+		glowBox().Draw(buff, 0, 0)
+		if c := color.RGBAModel.Convert(buff.At(1, 1)); c != expected {
+			t.Fatalf("expected sprite added to non-RGBA buffer, got %v", c)
+		}
+	})
+	t.Run("Copy", func(t *testing.T) {
+		cp := glowBox().Copy().(*Sprite)
+		if cp.GetBlendMode() != BlendAdd {
+			t.Fatal("expected blend mode to be copied")
+		}
+	})
+}
+
+func TestBlendColor(t *testing.T) {
+	buff := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	buff.SetRGBA(0, 0, color.RGBA{100, 100, 100, 255})
+	BlendColor(buff, 0, 0, color.RGBA{255, 255, 255, 255}, BlendSubtract)
+	if buff.RGBAAt(0, 0) != (color.RGBA{0, 0, 0, 255}) {
+		t.Fatalf("expected subtracted color, got %v", buff.RGBAAt(0, 0))
+	}
+	// out of bounds colors are ignored
+	BlendColor(buff, 5, 5, color.RGBA{255, 255, 255, 255}, BlendAdd)
+}
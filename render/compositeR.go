@@ -74,10 +74,39 @@ func (cs *CompositeR) SetOffsets(ps ...floatgeom.Point2) {
 // Draw Draws the CompositeR with an offset from its logical location.
 func (cs *CompositeR) Draw(buff draw.Image, xOff, yOff float64) {
 	for _, c := range cs.rs {
-		c.Draw(buff, cs.X()+xOff, cs.Y()+yOff)
+		cs.drawChild(buff, c, cs.X()+xOff, cs.Y()+yOff)
 	}
 }
 
+// drawChild draws one of this composite's renderables. If the composite has a blend mode, it is
+// applied to renderables which do not have their own.
+func (cs *CompositeR) drawChild(buff draw.Image, r Renderable, xOff, yOff float64) {
+	if cs.blend == BlendNormal {
+		r.Draw(buff, xOff, yOff)
+		return
+	}
+	if b, ok := r.(blendable); ok && b.GetBlendMode() != BlendNormal {
+		r.Draw(buff, xOff, yOff)
+		return
+	}
+	var rgba *image.RGBA
+	if m, ok := r.(interface{ GetRGBA() *image.RGBA }); ok {
+		rgba = m.GetRGBA()
+	}
+	if rgba == nil {
+		r.Draw(buff, xOff, yOff)
+		return
+	}
+	x, y := r.X()+xOff, r.Y()+yOff
+	if tr, ok := r.(transformable); ok {
+		if t, ok := tr.GetTransform(); ok {
+			drawTransformed(buff, rgba, x, y, t, cs.blend)
+			return
+		}
+	}
+	DrawImageBlend(buff, rgba, int(x), int(y), cs.blend)
+}
+
 // Undraw undraws the CompositeR and its consituent renderables
 func (cs *CompositeR) Undraw() {
 	cs.layer = Undraw
@@ -149,16 +178,8 @@ func (cs *CompositeR) DrawToScreen(world draw.Image, viewPos *intgeom.Point2, sc
 		if realLength == i {
 			break
 		}
-		x := int(r.X())
-		y := int(r.Y())
-		x2 := x
-		y2 := y
-		w, h := r.GetDims()
-		x += w
-		y += h
-		if x > viewPos[0] && y > viewPos[1] &&
-			x2 < viewPos[0]+screenW && y2 < viewPos[1]+screenH {
-			r.Draw(world, float64(-viewPos[0]), float64(-viewPos[1]))
+		if inView(r, *viewPos, screenW, screenH) {
+			cs.drawChild(world, r, float64(-viewPos[0]), float64(-viewPos[1]))
 		}
 	}
 	cs.rs = cs.rs[0:realLength]
@@ -126,13 +126,7 @@ func (rh *RenderableHeap) DrawToScreen(world draw.Image, viewPos *intgeom.Point2
 		for len(rh.rs) > 0 {
 			r := rh.heapPop()
 			if r.GetLayer() != Undraw {
-				x2 := int(r.X())
-				y2 := int(r.Y())
-				w, h := r.GetDims()
-				x := w + x2
-				y := h + y2
-				if x > viewPos[0] && y > viewPos[1] &&
-					x2 < viewPos[0]+screenW && y2 < viewPos[1]+screenH {
+				if inView(r, *viewPos, screenW, screenH) {
 					r.Draw(world, vx, vy)
 				}
 				rh.swap.heapPush(r)
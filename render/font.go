@@ -169,19 +169,9 @@ func (f *Font) MeasureString(s string) fixed.Int26_6 {
 		if prevC >= 0 {
 			f.Drawer.Dot.X += f.Drawer.Face.Kern(prevC, c)
 		}
-		_, _, _, advance, ok := f.Drawer.Face.Glyph(f.Drawer.Dot, c)
-		if idx := f.ttfnt.Index(c); !ok || idx == 0 {
-			found := false
-			for _, fallback := range f.Fallbacks {
-				_, _, _, advance, ok = fallback.Drawer.Face.Glyph(f.Drawer.Dot, c)
-				if idx := fallback.ttfnt.Index(c); ok && idx != 0 {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
+		_, _, _, advance, ok := f.glyph(f.Drawer.Dot, c)
+		if !ok {
+			continue
 		}
 		width += advance
 		prevC = c
@@ -197,19 +187,9 @@ func (f *Font) drawString(s string) {
 		if prevC >= 0 {
 			f.Drawer.Dot.X += f.Drawer.Face.Kern(prevC, c)
 		}
-		dr, mask, maskp, advance, ok := f.Drawer.Face.Glyph(f.Drawer.Dot, c)
-		if idx := f.ttfnt.Index(c); !ok || idx == 0 {
-			found := false
-			for _, fallback := range f.Fallbacks {
-				dr, mask, maskp, advance, ok = fallback.Drawer.Face.Glyph(f.Drawer.Dot, c)
-				if idx := fallback.ttfnt.Index(c); ok && idx != 0 {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
+		dr, mask, maskp, advance, ok := f.glyph(f.Drawer.Dot, c)
+		if !ok {
+			continue
 		}
 		draw.DrawMask(f.Drawer.Dst, dr, f.Drawer.Src, image.Point{}, mask, maskp, draw.Over)
 		f.Drawer.Dot.X += advance
@@ -217,6 +197,33 @@ func (f *Font) drawString(s string) {
 	}
 }
 
+// drawRune draws c to dst with its baseline at dot, colored by src.
+func (f *Font) drawRune(dst draw.Image, dot fixed.Point26_6, c rune, src image.Image) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	dr, mask, maskp, _, ok := f.glyph(dot, c)
+	if !ok {
+		return
+	}
+	draw.DrawMask(dst, dr, src, image.Point{}, mask, maskp, draw.Over)
+}
+
+// glyph returns c's glyph at dot from this font, or from the first of its fallbacks with a glyph
+// for c. The returned mask is only valid until the next call to glyph.
+func (f *Font) glyph(dot fixed.Point26_6, c rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	dr, mask, maskp, advance, ok = f.Drawer.Face.Glyph(dot, c)
+	if idx := f.ttfnt.Index(c); ok && idx != 0 {
+		return dr, mask, maskp, advance, true
+	}
+	for _, fallback := range f.Fallbacks {
+		dr, mask, maskp, advance, ok = fallback.Drawer.Face.Glyph(dot, c)
+		if idx := fallback.ttfnt.Index(c); ok && idx != 0 {
+			return dr, mask, maskp, advance, true
+		}
+	}
+	return dr, mask, maskp, advance, false
+}
+
 // Height returns the height or size of the font
 func (f *Font) Height() float64 {
 	if f.gen.Size == 0 {
@@ -1,6 +1,9 @@
 package render
 
-import "github.com/oakmound/oak/v4/event"
+import (
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/timing"
+)
 
 // NonStatic types are not always static. If something is not NonStatic,
 // it is equivalent to having IsStatic always return true.
@@ -14,6 +17,37 @@ type Triggerable interface {
 	SetTriggerID(event.CallerID)
 }
 
+// Clocked types measure time by a clock, which can be set so they pause and scale alongside a
+// scene. Drawing a Clocked renderable via a scene's context sets it to use the scene's clock.
+type Clocked interface {
+	SetClock(*timing.Clock)
+}
+
+// TriggerHandled types trigger their events on a handler, which can be set so they trigger on a
+// scene's handler. Drawing a TriggerHandled renderable via a scene's context sets it to trigger on
+// the scene's handler.
+type TriggerHandled interface {
+	SetTriggerHandler(event.Handler)
+}
+
+// triggerHandler holds the handler a TriggerHandled type triggers its events on.
+type triggerHandler struct {
+	handler event.Handler
+}
+
+// SetTriggerHandler sets the handler events are triggered on. If never set, or set to nil,
+// event.DefaultBus is used.
+func (th *triggerHandler) SetTriggerHandler(h event.Handler) {
+	th.handler = h
+}
+
+func (th *triggerHandler) triggerOn() event.Handler {
+	if th.handler == nil {
+		return event.DefaultBus
+	}
+	return th.handler
+}
+
 type updates interface {
 	update()
 }
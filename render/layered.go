@@ -50,6 +50,8 @@ func (ld *Layer) Undraw() {
 type LayeredPoint struct {
 	physics.Vector
 	Layer
+	transform *Transform
+	blend     BlendMode
 }
 
 // NewLayeredPoint creates a new LayeredPoint at a given location and layer
@@ -74,6 +76,11 @@ func (ldp *LayeredPoint) Copy() LayeredPoint {
 	ldp2 := LayeredPoint{}
 	ldp2.Vector = ldp.Vector.Copy()
 	ldp2.Layer = ldp.Layer
+	ldp2.blend = ldp.blend
+	if ldp.transform != nil {
+		t := *ldp.transform
+		ldp2.transform = &t
+	}
 	return ldp2
 }
 
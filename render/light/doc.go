@@ -0,0 +1,2 @@
+// Package light provides lights and shadows, drawn as a layer of a render.DrawStack.
+package light
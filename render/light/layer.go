@@ -0,0 +1,135 @@
+package light
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/render"
+)
+
+// A Layer is a render.Stackable which lights everything drawn before it on a draw stack. Each frame,
+// the renderables added to a layer, usually Lights, are added together into a light map, starting
+// from black, which is then multiplied over what has already been drawn. Stackables drawn after a
+// layer, such as a UI, are unaffected by it.
+//
+// Renderables other than Lights can be added to a layer to shape its light map, e.g. a sprite with
+// render.BlendAdd for a light with a custom shape, or render.BlendMultiply for a darker area.
+type Layer struct {
+	rs       []render.Renderable
+	toPush   []render.Renderable
+	toUndraw []render.Renderable
+	addLock  sync.Mutex
+
+	// lightMap is kept between frames to avoid reallocating it
+	lightMap *image.RGBA
+}
+
+var _ render.Stackable = &Layer{}
+
+var dark = image.NewUniform(color.RGBA{0, 0, 0, 255})
+
+// NewLayer creates a light layer with no lights.
+func NewLayer() *Layer {
+	return &Layer{}
+}
+
+// Add stages a light, or other renderable, to be added to this layer at the next PreDraw.
+func (ly *Layer) Add(r render.Renderable, layers ...int) render.Renderable {
+	if len(layers) > 0 {
+		r.SetLayer(layers[0])
+	}
+	ly.addLock.Lock()
+	ly.toPush = append(ly.toPush, r)
+	ly.addLock.Unlock()
+	return r
+}
+
+// Replace adds a renderable and removes an old one at the next PreDraw.
+func (ly *Layer) Replace(old, new render.Renderable, layer int) {
+	new.SetLayer(layer)
+	ly.addLock.Lock()
+	ly.toPush = append(ly.toPush, new)
+	ly.toUndraw = append(ly.toUndraw, old)
+	ly.addLock.Unlock()
+}
+
+// PreDraw adds and removes the renderables staged by Add and Replace.
+func (ly *Layer) PreDraw() {
+	ly.addLock.Lock()
+	for _, r := range ly.toUndraw {
+		r.Undraw()
+	}
+	ly.rs = append(ly.rs, ly.toPush...)
+	ly.toPush = ly.toPush[:0]
+	ly.toUndraw = ly.toUndraw[:0]
+	ly.addLock.Unlock()
+	// undrawn renderables are removed, otherwise renderables are drawn in the order they were added
+	kept := ly.rs[:0]
+	for _, r := range ly.rs {
+		if r.GetLayer() != render.Undraw {
+			kept = append(kept, r)
+		}
+	}
+	for i := len(kept); i < len(ly.rs); i++ {
+		ly.rs[i] = nil
+	}
+	ly.rs = kept
+}
+
+// Copy returns a new layer without any of this layer's renderables, as renderables cannot be copied.
+func (ly *Layer) Copy() render.Stackable {
+	return NewLayer()
+}
+
+// Clear removes every renderable from this layer.
+func (ly *Layer) Clear() {
+	ly.addLock.Lock()
+	ly.rs = nil
+	ly.toPush = nil
+	ly.toUndraw = nil
+	ly.addLock.Unlock()
+}
+
+// DrawToScreen builds this layer's light map for the w by h viewport at view, then multiplies it
+// over world.
+func (ly *Layer) DrawToScreen(world draw.Image, view *intgeom.Point2, w, h int) {
+	area := image.Rect(0, 0, w, h).Intersect(world.Bounds())
+	if area.Empty() {
+		return
+	}
+	if ly.lightMap == nil || ly.lightMap.Bounds() != area {
+		ly.lightMap = image.NewRGBA(area)
+	}
+	draw.Draw(ly.lightMap, area, dark, image.Point{}, draw.Src)
+
+	vx, vy := float64(-view.X()), float64(-view.Y())
+	for _, r := range ly.rs {
+		if r.GetLayer() == render.Undraw || !ly.inView(r, view, area) {
+			continue
+		}
+		r.Draw(ly.lightMap, vx, vy)
+	}
+	render.DrawImageBlend(world, ly.lightMap, 0, 0, render.BlendMultiply)
+}
+
+// inView reports whether r could light any of area when drawn from view.
+func (ly *Layer) inView(r render.Renderable, view *intgeom.Point2, area image.Rectangle) bool {
+	var x1, y1, x2, y2 float64
+	if l, ok := r.(*Light); ok {
+		bds, everywhere := l.bounds()
+		if everywhere {
+			return true
+		}
+		x1, y1, x2, y2 = bds.Min.X(), bds.Min.Y(), bds.Max.X(), bds.Max.Y()
+	} else {
+		w, h := r.GetDims()
+		x1, y1 = r.X(), r.Y()
+		x2, y2 = x1+float64(w), y1+float64(h)
+	}
+	vx, vy := float64(view.X()), float64(view.Y())
+	return x2 > vx+float64(area.Min.X) && y2 > vy+float64(area.Min.Y) &&
+		x1 < vx+float64(area.Max.X) && y1 < vy+float64(area.Max.Y)
+}
@@ -0,0 +1,319 @@
+package light
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sync"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/collision/ray"
+	"github.com/oakmound/oak/v4/render"
+)
+
+// A Kind is a type of light.
+type Kind uint8
+
+// Light kinds
+const (
+	// Point lights shine equally in every direction, fading out towards their radius.
+	Point Kind = iota
+	// Spot lights shine like point lights, but only within a cone.
+	Spot
+	// Ambient lights light everything they are drawn to evenly.
+	Ambient
+)
+
+// DefaultShadowRays is how many rays a light casts to find its shadows, unless told otherwise.
+const DefaultShadowRays = 120
+
+// A Light adds light to a light map. Its position is its center, and like other renderables it can
+// be attached to a physics.Vector to follow it.
+type Light struct {
+	render.LayeredPoint
+
+	mu     sync.Mutex
+	kind   Kind
+	color  color.RGBA
+	radius float64
+	// direction and spread are in degrees; like collision/ray angles, 0 is right and 90 is down
+	direction float64
+	spread    float64
+
+	caster     *ray.Caster
+	shadowRays int
+	// hits are the distances to the nearest occluder along each shadow ray
+	hits []float64
+
+	// texture is this light's unshadowed contribution, rendered when first drawn after a change
+	texture *image.RGBA
+}
+
+// NewPoint creates a point light centered at x, y.
+func NewPoint(x, y, radius float64, c color.Color) *Light {
+	return newLight(Point, x, y, radius, c)
+}
+
+// NewSpot creates a spot light centered at x, y, shining towards direction in a cone spread degrees
+// wide. Like collision/ray angles, a direction of 0 degrees is right and 90 degrees is down.
+func NewSpot(x, y, radius float64, c color.Color, direction, spread float64) *Light {
+	l := newLight(Spot, x, y, radius, c)
+	l.direction = direction
+	l.spread = spread
+	return l
+}
+
+// NewAmbient creates an ambient light.
+func NewAmbient(c color.Color) *Light {
+	return newLight(Ambient, 0, 0, 0, c)
+}
+
+func newLight(kind Kind, x, y, radius float64, c color.Color) *Light {
+	return &Light{
+		LayeredPoint: render.NewLayeredPoint(x, y, 0),
+		kind:         kind,
+		color:        opaque(c),
+		radius:       radius,
+		shadowRays:   DefaultShadowRays,
+	}
+}
+
+// opaque returns a color's red, green and blue, ignoring its alpha.
+func opaque(c color.Color) color.RGBA {
+	r, g, b, _ := c.RGBA()
+	return color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 255}
+}
+
+// Kind returns what kind of light this is.
+func (l *Light) Kind() Kind {
+	return l.kind
+}
+
+// SetColor sets the color of this light. Brighter colors make brighter lights; alpha is ignored.
+func (l *Light) SetColor(c color.Color) {
+	l.mu.Lock()
+	l.color = opaque(c)
+	l.texture = nil
+	l.mu.Unlock()
+}
+
+// SetRadius sets how far this light reaches. Ambient lights have no radius.
+func (l *Light) SetRadius(radius float64) {
+	l.mu.Lock()
+	l.radius = radius
+	l.texture = nil
+	l.mu.Unlock()
+}
+
+// Radius returns how far this light reaches.
+func (l *Light) Radius() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.radius
+}
+
+// SetDirection sets the direction, in degrees, a spot light shines towards.
+func (l *Light) SetDirection(direction float64) {
+	l.mu.Lock()
+	l.direction = direction
+	l.texture = nil
+	l.mu.Unlock()
+}
+
+// SetSpread sets how wide, in degrees, a spot light's cone is.
+func (l *Light) SetSpread(spread float64) {
+	l.mu.Lock()
+	l.spread = spread
+	l.texture = nil
+	l.mu.Unlock()
+}
+
+// SetShadows makes this light cast shadows from the spaces found by caster, e.g. a caster built with
+// ray.AcceptLabels for the labels of walls. Each frame, rays are cast out to the light's radius; a
+// larger number of rays gives more precise shadows at a higher cost. Rays <= 0 uses
+// DefaultShadowRays. A nil caster turns shadows off. Ambient lights cast no shadows.
+func (l *Light) SetShadows(caster *ray.Caster, rays int) {
+	if rays <= 0 {
+		rays = DefaultShadowRays
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if caster == nil {
+		l.caster = nil
+		return
+	}
+	// Q: Why copy the caster?
+	// A: Only the first space each ray hits matters, and the caster may be shared with other lights.
+	l.caster = caster.Copy()
+	l.caster.Limits = append(l.caster.Limits[:len(l.caster.Limits):len(l.caster.Limits)], firstHit)
+	l.shadowRays = rays
+}
+
+func firstHit(points []collision.Point) bool {
+	return len(points) == 0
+}
+
+// GetDims returns the width and height of the area this light can reach.
+func (l *Light) GetDims() (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.kind == Ambient {
+		return 1, 1
+	}
+	d := int(math.Ceil(l.radius))*2 + 1
+	return d, d
+}
+
+// bounds returns the area this light can reach in world coordinates, and whether it reaches
+// everywhere.
+func (l *Light) bounds() (floatgeom.Rect2, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.kind == Ambient {
+		return floatgeom.Rect2{}, true
+	}
+	return floatgeom.NewRect2(l.X()-l.radius, l.Y()-l.radius, l.X()+l.radius, l.Y()+l.radius), false
+}
+
+// Draw adds this light to buff, centered at its position offset by xOff, yOff.
+func (l *Light) Draw(buff draw.Image, xOff, yOff float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.kind == Ambient {
+		l.drawAmbient(buff)
+		return
+	}
+	if l.radius <= 0 {
+		return
+	}
+	tex := l.renderTexture()
+	r := int(math.Ceil(l.radius))
+	cx, cy := int(math.Round(l.X()+xOff)), int(math.Round(l.Y()+yOff))
+	if l.caster == nil {
+		render.DrawImageBlend(buff, tex, cx-r, cy-r, render.BlendAdd)
+		return
+	}
+	l.castShadows()
+	step := 2 * math.Pi / float64(len(l.hits))
+	area := tex.Bounds().Add(image.Point{cx - r, cy - r}).Intersect(buff.Bounds())
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		dy := float64(y - cy)
+		for x := area.Min.X; x < area.Max.X; x++ {
+			i := tex.PixOffset(x-cx+r, y-cy+r)
+			if tex.Pix[i+3] == 0 {
+				continue
+			}
+			dx := float64(x - cx)
+			angle := math.Atan2(dy, dx)
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+			hit := l.hits[int(math.Round(angle/step))%len(l.hits)]
+			if dx*dx+dy*dy > hit*hit {
+				continue
+			}
+			addPixel(buff, x, y, [4]uint8{tex.Pix[i], tex.Pix[i+1], tex.Pix[i+2], tex.Pix[i+3]})
+		}
+	}
+}
+
+// drawAmbient adds this light's color to every pixel of buff. It must be called while holding the
+// lock.
+func (l *Light) drawAmbient(buff draw.Image) {
+	rgba, ok := buff.(*image.RGBA)
+	if !ok {
+		render.DrawImageBlend(buff, image.NewUniform(l.color), 0, 0, render.BlendAdd)
+		return
+	}
+	b := rgba.Bounds()
+	c := [4]uint8{l.color.R, l.color.G, l.color.B, 255}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := rgba.Pix[rgba.PixOffset(b.Min.X, y):rgba.PixOffset(b.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			addTo(row[i:i+4:i+4], c)
+		}
+	}
+}
+
+// addPixel adds the premultiplied color c to the pixel of buff at x, y.
+func addPixel(buff draw.Image, x, y int, c [4]uint8) {
+	rgba, ok := buff.(*image.RGBA)
+	if !ok {
+		render.BlendColor(buff, x, y, color.RGBA{c[0], c[1], c[2], c[3]}, render.BlendAdd)
+		return
+	}
+	i := rgba.PixOffset(x, y)
+	addTo(rgba.Pix[i:i+4:i+4], c)
+}
+
+// addTo adds c to px, saturating each channel.
+func addTo(px []uint8, c [4]uint8) {
+	for ch := 0; ch < 4; ch++ {
+		v := uint16(px[ch]) + uint16(c[ch])
+		if v > 255 {
+			v = 255
+		}
+		px[ch] = uint8(v)
+	}
+}
+
+// renderTexture returns this light's unshadowed contribution, centered in the image. It must be
+// called while holding the lock.
+func (l *Light) renderTexture() *image.RGBA {
+	if l.texture != nil {
+		return l.texture
+	}
+	r := int(math.Ceil(l.radius))
+	tex := image.NewRGBA(image.Rect(0, 0, 2*r+1, 2*r+1))
+	halfSpread := l.spread / 2 * math.Pi / 180
+	// spot lights fade out over the outer fifth of their cone
+	edge := halfSpread / 5
+	dir := l.direction * math.Pi / 180
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			dist := math.Hypot(float64(x), float64(y))
+			if dist >= l.radius {
+				continue
+			}
+			// quadratic falloff, strongest at the center
+			intensity := (1 - dist/l.radius) * (1 - dist/l.radius)
+			if l.kind == Spot && dist > 0 {
+				off := math.Abs(math.Remainder(math.Atan2(float64(y), float64(x))-dir, 2*math.Pi))
+				if off > halfSpread {
+					continue
+				}
+				if edge > 0 && off > halfSpread-edge {
+					intensity *= (halfSpread - off) / edge
+				}
+			}
+			tex.SetRGBA(x+r, y+r, color.RGBA{
+				uint8(float64(l.color.R) * intensity),
+				uint8(float64(l.color.G) * intensity),
+				uint8(float64(l.color.B) * intensity),
+				uint8(255 * intensity),
+			})
+		}
+	}
+	l.texture = tex
+	return tex
+}
+
+// castShadows finds the distance to the nearest occluder along each of this light's shadow rays. It
+// must be called while holding the lock.
+func (l *Light) castShadows() {
+	if len(l.hits) != l.shadowRays {
+		l.hits = make([]float64, l.shadowRays)
+	}
+	l.caster.CastDistance = l.radius
+	origin := floatgeom.Point2{l.X(), l.Y()}
+	step := 360 / float64(len(l.hits))
+	for i := range l.hits {
+		l.hits[i] = l.radius
+		points := l.caster.Cast(origin, floatgeom.AnglePoint(float64(i)*step))
+		if len(points) != 0 {
+			l.hits[i] = origin.Distance(floatgeom.Point2{points[0].X(), points[0].Y()})
+		}
+	}
+}
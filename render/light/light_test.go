@@ -0,0 +1,173 @@
+package light
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/collision/ray"
+	"github.com/oakmound/oak/v4/physics"
+	"github.com/oakmound/oak/v4/render"
+)
+
+var white = color.RGBA{255, 255, 255, 255}
+
+func whiteScreen(w, h int) *image.RGBA {
+	buff := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(buff, buff.Bounds(), image.NewUniform(white), image.Point{}, draw.Src)
+	return buff
+}
+
+func drawLayer(ly *Layer, buff *image.RGBA, view intgeom.Point2) {
+	ly.PreDraw()
+	ly.DrawToScreen(buff, &view, buff.Bounds().Dx(), buff.Bounds().Dy())
+}
+
+func TestLayer_Point(t *testing.T) {
+	ly := NewLayer()
+	ly.Add(NewPoint(20, 20, 10, white))
+	buff := whiteScreen(40, 40)
+	drawLayer(ly, buff, intgeom.Point2{10, 10})
+
+	// the light is at the center of the view
+	if c := buff.RGBAAt(10, 10); c != white {
+		t.Fatalf("expected full light at the light's center, got %v", c)
+	}
+	if c := buff.RGBAAt(15, 10); c.R == 0 || c.R == 255 {
+		t.Fatalf("expected partial light within the light's radius, got %v", c)
+	}
+	if c := buff.RGBAAt(25, 10); c != (color.RGBA{0, 0, 0, 255}) {
+		t.Fatalf("expected darkness outside of the light's radius, got %v", c)
+	}
+}
+
+func TestLayer_Ambient(t *testing.T) {
+	ly := NewLayer()
+	ly.Add(NewAmbient(color.RGBA{128, 128, 128, 255}))
+	ly.Add(NewAmbient(color.RGBA{0, 0, 64, 255}))
+	buff := whiteScreen(8, 8)
+	drawLayer(ly, buff, intgeom.Point2{})
+	if c := buff.RGBAAt(7, 7); c != (color.RGBA{128, 128, 192, 255}) {
+		t.Fatalf("expected ambient lights added together, got %v", c)
+	}
+}
+
+func TestLayer_Spot(t *testing.T) {
+	ly := NewLayer()
+	spot := NewSpot(10, 10, 10, white, 90, 60)
+	ly.Add(spot)
+	buff := whiteScreen(20, 20)
+	drawLayer(ly, buff, intgeom.Point2{})
+	if c := buff.RGBAAt(10, 14); c.R == 0 {
+		t.Fatal("expected light below a spot light shining down")
+	}
+	if c := buff.RGBAAt(10, 6); c.R != 0 {
+		t.Fatal("expected no light above a spot light shining down")
+	}
+	if c := buff.RGBAAt(14, 10); c.R != 0 {
+		t.Fatal("expected no light to the side of a spot light shining down")
+	}
+
+	spot.SetDirection(270)
+	buff = whiteScreen(20, 20)
+	drawLayer(ly, buff, intgeom.Point2{})
+	if c := buff.RGBAAt(10, 6); c.R == 0 {
+		t.Fatal("expected light above a spot light shining up")
+	}
+}
+
+func TestLayer_Shadows(t *testing.T) {
+	const wall = collision.Label(1)
+	tree := collision.NewTree()
+	tree.Add(collision.NewLabeledSpace(15, 0, 2, 10, wall))
+
+	l := NewPoint(10, 5, 20, white)
+	l.SetShadows(ray.NewCaster(ray.Tree(tree), ray.AcceptLabels(wall)), 0)
+	ly := NewLayer()
+	ly.Add(l)
+	buff := whiteScreen(40, 40)
+	drawLayer(ly, buff, intgeom.Point2{})
+	if c := buff.RGBAAt(13, 5); c.R == 0 {
+		t.Fatal("expected light in front of the wall")
+	}
+	if c := buff.RGBAAt(20, 5); c.R != 0 {
+		t.Fatalf("expected shadow behind the wall, got %v", c)
+	}
+	if c := buff.RGBAAt(5, 5); c.R == 0 {
+		t.Fatal("expected light away from the wall")
+	}
+	// below the wall, the light is unobstructed
+	if c := buff.RGBAAt(20, 16); c.R == 0 {
+		t.Fatal("expected light past the end of the wall")
+	}
+
+	l.SetShadows(nil, 0)
+	buff = whiteScreen(40, 40)
+	drawLayer(ly, buff, intgeom.Point2{})
+	if c := buff.RGBAAt(20, 5); c.R == 0 {
+		t.Fatal("expected no shadows once turned off")
+	}
+}
+
+func TestLayer_Attach(t *testing.T) {
+	v := physics.NewVector(0, 0)
+	l := NewPoint(0, 0, 4, white)
+	l.Attach(&v, 2, 2)
+	ly := NewLayer()
+	ly.Add(l)
+	v.SetPos(10, 10)
+	buff := whiteScreen(20, 20)
+	drawLayer(ly, buff, intgeom.Point2{})
+	if c := buff.RGBAAt(12, 12); c != white {
+		t.Fatalf("expected light to follow the vector it is attached to, got %v", c)
+	}
+}
+
+func TestLayer_DrawStack(t *testing.T) {
+	scene := render.NewDynamicHeap()
+	ui := render.NewStaticHeap()
+	ly := NewLayer()
+	stack := render.NewDrawStack(scene, ly, ui)
+	stack.Draw(render.NewColorBox(10, 10, white), 0)
+	stack.Draw(render.NewColorBox(2, 2, white), 2)
+	l, _ := stack.Draw(NewPoint(8, 8, 2, white), 1)
+	stack.PreDraw()
+
+	buff := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	stack.DrawToScreen(buff, &intgeom.Point2{}, 10, 10)
+	if buff.RGBAAt(0, 0) != white {
+		t.Fatal("expected stackables after the light layer to be unlit")
+	}
+	if buff.RGBAAt(5, 5) != (color.RGBA{0, 0, 0, 255}) {
+		t.Fatal("expected stackables before the light layer to be dark away from lights")
+	}
+	if buff.RGBAAt(8, 8) != white {
+		t.Fatal("expected stackables before the light layer to be lit near lights")
+	}
+
+	l.Undraw()
+	stack.PreDraw()
+	stack.DrawToScreen(buff, &intgeom.Point2{}, 10, 10)
+	if buff.RGBAAt(8, 8) != (color.RGBA{0, 0, 0, 255}) {
+		t.Fatal("expected undrawn light to be removed")
+	}
+}
+
+func BenchmarkLayer(b *testing.B) {
+	ly := NewLayer()
+	ly.Add(NewAmbient(color.RGBA{30, 30, 40, 255}))
+	for i := 0; i < 36; i++ {
+		ly.Add(NewPoint(rand.Float64()*640, rand.Float64()*480, 60, color.RGBA{200, 160, 120, 255}))
+	}
+	buff := whiteScreen(640, 480)
+	ly.PreDraw()
+	view := intgeom.Point2{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ly.DrawToScreen(buff, &view, 640, 480)
+	}
+}
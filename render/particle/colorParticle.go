@@ -62,12 +62,22 @@ func (cp *ColorParticle) DrawOffsetGen(generator Generator, buff draw.Image, xOf
 	for i := 0; i < size; i++ {
 		for j := 0; j < size; j++ {
 			if gen.Shape.In(i, j, size) {
-				buff.Set(xOffi+i, yOffi+j, c)
+				setPixel(buff, xOffi+i, yOffi+j, c, gen.BlendMode)
 			}
 		}
 	}
 }
 
+// setPixel sets a pixel of a particle, combining it with buff using mode unless mode is
+// render.BlendNormal.
+func setPixel(buff draw.Image, x, y int, c color.Color, mode render.BlendMode) {
+	if mode == render.BlendNormal {
+		buff.Set(x, y, c)
+		return
+	}
+	render.BlendColor(buff, x, y, c, mode)
+}
+
 // GetLayer returns baseParticle GetLayer. This is a safety check against auto-generated
 // code which would not contain the nil check here
 func (cp *ColorParticle) GetLayer() int {
@@ -45,3 +45,23 @@ func TestColorParticle(t *testing.T) {
 		t.Fatalf("get particle size not particle-specified")
 	}
 }
+
+func TestColorParticle_Blend(t *testing.T) {
+	g := NewColorGenerator(
+		Color(color.RGBA{100, 0, 0, 255}, color.RGBA{}, color.RGBA{100, 0, 0, 255}, color.RGBA{}),
+		Size(span.NewConstant(4)),
+		EndSize(span.NewConstant(4)),
+		Blend(render.BlendAdd),
+	)
+	src := g.Generate(0)
+	src.addParticles()
+	p := src.particles[0].(*ColorParticle)
+	p.SetPos(2, 2)
+
+	buff := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	buff.SetRGBA(2, 2, color.RGBA{100, 100, 100, 255})
+	p.Draw(buff, 0, 0)
+	if buff.RGBAAt(2, 2) != (color.RGBA{200, 100, 100, 255}) {
+		t.Fatalf("expected particle added to buffer, got %v", buff.RGBAAt(2, 2))
+	}
+}
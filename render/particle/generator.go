@@ -5,6 +5,7 @@ import (
 
 	"github.com/oakmound/oak/v4/alg/span"
 	"github.com/oakmound/oak/v4/physics"
+	"github.com/oakmound/oak/v4/render"
 )
 
 var (
@@ -58,6 +59,8 @@ type BaseGenerator struct {
 	EndFunc       func(Particle)
 	LayerFunc     func(physics.Vector) int
 	ParticleLimit int
+	// BlendMode is how particles combine with what is drawn beneath them.
+	BlendMode render.BlendMode
 }
 
 // GetBaseGenerator returns this
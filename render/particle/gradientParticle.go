@@ -39,7 +39,7 @@ func (gp *GradientParticle) DrawOffsetGen(generator Generator, buff draw.Image,
 			if gen.Shape.In(i, j, size) {
 				progress := gen.ProgressFunction(i, j, size, size)
 				c := render.GradientColorAt(c1, c2, progress)
-				buff.Set(xOffi+i, yOffi+j, c)
+				setPixel(buff, xOffi+i, yOffi+j, c, gen.BlendMode)
 			}
 		}
 	}
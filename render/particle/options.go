@@ -6,6 +6,7 @@ import (
 	"github.com/oakmound/oak/v4/alg"
 	"github.com/oakmound/oak/v4/alg/span"
 	"github.com/oakmound/oak/v4/physics"
+	"github.com/oakmound/oak/v4/render"
 )
 
 // And chains together particle options into a single option
@@ -109,6 +110,14 @@ func Layer(l func(physics.Vector) int) func(Generator) {
 	}
 }
 
+// Blend sets how particles should combine with what is drawn beneath them, e.g.
+// render.BlendAdd for fire and glows.
+func Blend(mode render.BlendMode) func(Generator) {
+	return func(g Generator) {
+		g.GetBaseGenerator().BlendMode = mode
+	}
+}
+
 // Limit limits the total number of particles a particle generator can have
 // active at once.
 func Limit(limit int) func(Generator) {
@@ -7,6 +7,7 @@ import (
 	"github.com/oakmound/oak/v4/event"
 	"github.com/oakmound/oak/v4/physics"
 	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/timing"
 )
 
 const (
@@ -27,7 +28,8 @@ type Source struct {
 	pIDBlock     int
 	stackLevel   int
 	EndFunc      func()
-	stopRotateAt time.Time
+	stopRotateAt time.Duration
+	clock        *timing.Clock
 	paused       bool
 	started      bool
 	stopped      bool
@@ -38,15 +40,25 @@ func NewDefaultSource(g Generator, stackLevel int) *Source {
 	return NewSource(event.DefaultBus, g, stackLevel)
 }
 
+// A clockHolder is a handler with its own clock, such as a scene's context.
+type clockHolder interface {
+	GetClock() *timing.Clock
+}
+
 // NewSource for particles constructed from a generator with specifications on how the particles should be handled.
+// If handler has a clock of its own, as a scene's context does, the source measures its duration by it.
 func NewSource(handler event.Handler, g Generator, stackLevel int) *Source {
 	ps := new(Source)
 	ps.Generator = g
 	ps.stackLevel = stackLevel
 	ps.Allocator = DefaultAllocator
 	cid := handler.GetCallerMap().Register(ps)
-	ps.stopRotateAt = time.Now().Add(
-		time.Duration(ps.Generator.GetBaseGenerator().Duration.Poll()) * time.Millisecond)
+	ps.clock = timing.DefaultClock
+	if ch, ok := handler.(clockHolder); ok {
+		ps.clock = ch.GetClock()
+	}
+	ps.stopRotateAt = ps.clock.Now() +
+		time.Duration(ps.Generator.GetBaseGenerator().Duration.Poll())*time.Millisecond
 
 	ps.CallerID = cid // cid must be set before the following bind call
 	ps.rotateBinding = event.Bind(handler, event.Enter, ps, rotateParticles)
@@ -195,7 +207,7 @@ func rotateParticles(ps *Source, _ event.EnterPayload) event.Response {
 		ps.cycleParticles()
 		ps.addParticles()
 	}
-	if time.Now().After(ps.stopRotateAt) {
+	if ps.clock.Now() > ps.stopRotateAt {
 		go ps.Stop()
 		return 0
 	}
@@ -231,6 +243,13 @@ func (ps *Source) Stop() {
 	event.Bind(event.DefaultBus, event.Enter, ps, clearParticles)
 }
 
+// SetClock sets the clock this source measures its duration by. Sources use their handler's clock,
+// or timing.DefaultClock, unless told otherwise.
+func (ps *Source) SetClock(c *timing.Clock) {
+	ps.stopRotateAt = c.Now() + (ps.stopRotateAt - ps.clock.Now())
+	ps.clock = c
+}
+
 // Pause on a Source just stops the repetition
 // of its rotation function, which moves, destroys,
 // ages and generates particles. Existing particles will
@@ -0,0 +1,577 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// An Alignment is how lines of text are placed horizontally.
+type Alignment uint8
+
+// Alignments
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+	// AlignJustify stretches the spaces of wrapped lines so they fill the text's width. The last line
+	// of each paragraph is aligned left.
+	AlignJustify
+)
+
+// RichTextOptions are the optional settings of a RichText.
+type RichTextOptions struct {
+	// Width, if set, is the width in pixels lines wrap at. Otherwise, lines only break at newlines.
+	Width int
+	Align Alignment
+	// LineSpacing scales the height of each line. It defaults to 1.
+	LineSpacing float64
+	// Fonts are the fonts markup can switch to by name.
+	Fonts map[string]*Font
+	// Icons are the sprites markup can place inline by name.
+	Icons map[string]*Sprite
+}
+
+// A RichText is a renderable that draws styled text over multiple lines. Its text is written with
+// markup tags in square brackets:
+//
+//	[color=red]...[/color]     draws text in an SVG 1.1 color name, or a #rrggbb or #rrggbbaa color
+//	[size=16]...[/size]        draws text at a font size
+//	[font=title]...[/font]     draws text in a font named in RichTextOptions.Fonts
+//	[icon=coin]                draws a sprite named in RichTextOptions.Icons in line with text
+//	[[                         draws a literal [
+//
+// Style tags nest, and are closed in the reverse order they are opened. Tags still open at the end
+// of the markup are closed automatically.
+type RichText struct {
+	LayeredPoint
+	font   *Font
+	opts   RichTextOptions
+	markup string
+	// fonts caches the fonts derived from font and opts.Fonts for each style in markup
+	fonts map[richStyle]*Font
+	// tags, if set, handles tags which are not styles or icons
+	tags richTagHandler
+
+	mu sync.Mutex
+	// parsed holds the glyphs of markup, including newlines, before they are laid out into glyphs
+	parsed []richGlyph
+	glyphs []richGlyph
+	lines  int
+	w, h   int
+}
+
+// A richStyle is the style text is drawn in at some point of a RichText's markup.
+type richStyle struct {
+	font  string
+	size  float64
+	color string
+}
+
+// A richTagHandler handles a markup tag other than a style or icon, as either an opening or closing
+// tag, at the index of the glyph after it. It reports whether an opening tag must be closed.
+type richTagHandler func(name, value string, glyph int, closing bool) (paired bool, err error)
+
+// A richGlyph is a rune or icon in a RichText, positioned relative to the text's position.
+type richGlyph struct {
+	r    rune
+	font *Font
+	icon *image.RGBA
+	// index is this glyph's index in the text's markup, counting newlines and wrapped spaces
+	index int
+	// pos is the left end of a rune's baseline, or the top left of an icon
+	pos     fixed.Point26_6
+	advance fixed.Int26_6
+	height  fixed.Int26_6
+}
+
+// NewRichText creates a renderable of styled text from markup. If the markup is invalid, an error
+// is returned.
+func (f *Font) NewRichText(markup string, x, y float64, opts RichTextOptions) (*RichText, error) {
+	rt := f.newRichText(x, y, opts)
+	if err := rt.SetMarkup(markup); err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+// newRichText creates a RichText without any content.
+func (f *Font) newRichText(x, y float64, opts RichTextOptions) *RichText {
+	return &RichText{
+		LayeredPoint: NewLayeredPoint(x, y, 0),
+		font:         f.Copy(),
+		opts:         opts,
+		fonts:        make(map[richStyle]*Font),
+	}
+}
+
+// SetMarkup replaces this text's content. If the markup is invalid, an error is returned and the
+// content is unchanged.
+func (rt *RichText) SetMarkup(markup string) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	glyphs, err := rt.parse(markup)
+	if err != nil {
+		return err
+	}
+	rt.markup = markup
+	rt.parsed = glyphs
+	rt.layout()
+	return nil
+}
+
+// Markup returns the markup this text is drawing.
+func (rt *RichText) Markup() string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.markup
+}
+
+// String returns this text's content without markup. Icons are omitted.
+func (rt *RichText) String() string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	var sb strings.Builder
+	for _, g := range rt.parsed {
+		if g.icon == nil {
+			sb.WriteRune(g.r)
+		}
+	}
+	return sb.String()
+}
+
+// SetWidth sets the width lines wrap at, or stops wrapping if width is 0.
+func (rt *RichText) SetWidth(width int) {
+	rt.mu.Lock()
+	rt.opts.Width = width
+	rt.layout()
+	rt.mu.Unlock()
+}
+
+// SetAlignment sets how lines are placed horizontally.
+func (rt *RichText) SetAlignment(align Alignment) {
+	rt.mu.Lock()
+	rt.opts.Align = align
+	rt.layout()
+	rt.mu.Unlock()
+}
+
+// SetLineSpacing sets the scale of each line's height.
+func (rt *RichText) SetLineSpacing(spacing float64) {
+	rt.mu.Lock()
+	rt.opts.LineSpacing = spacing
+	rt.layout()
+	rt.mu.Unlock()
+}
+
+// GetDims returns the width and height of this text's lines. If this text wraps, its width is
+// the width it wraps at.
+func (rt *RichText) GetDims() (int, int) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.w, rt.h
+}
+
+// LineCount returns how many lines this text is drawn over, after wrapping.
+func (rt *RichText) LineCount() int {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.lines
+}
+
+// Draw draws this text at +xOff, +yOff
+func (rt *RichText) Draw(buff draw.Image, xOff, yOff float64) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	origin := fixed.Point26_6{
+		X: fixed.Int26_6((rt.X() + xOff) * 64),
+		Y: fixed.Int26_6((rt.Y() + yOff) * 64),
+	}
+	for _, g := range rt.glyphs {
+		g.draw(buff, origin, nil)
+	}
+}
+
+// draw draws g relative to origin. If src is not nil, it overrides the color of runes.
+func (g richGlyph) draw(buff draw.Image, origin fixed.Point26_6, src image.Image) {
+	pos := origin.Add(g.pos)
+	if g.icon != nil {
+		DrawImage(buff, g.icon, pos.X.Round(), pos.Y.Round())
+		return
+	}
+	if g.r == ' ' {
+		return
+	}
+	if src == nil {
+		src = g.font.Drawer.Src
+	}
+	g.font.drawRune(buff, pos, g.r, src)
+}
+
+// parse converts markup into unpositioned glyphs.
+func (rt *RichText) parse(markup string) ([]richGlyph, error) {
+	type openTag struct {
+		name  string
+		style richStyle
+	}
+	var (
+		glyphs []richGlyph
+		style  richStyle
+		open   []openTag
+	)
+	fnt, err := rt.styleFont(style)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(markup); {
+		if strings.HasPrefix(markup[i:], "[[") {
+			glyphs = append(glyphs, rt.runeGlyph('[', fnt, len(glyphs)))
+			i += 2
+			continue
+		}
+		if markup[i] != '[' {
+			r, size := utf8.DecodeRuneInString(markup[i:])
+			glyphs = append(glyphs, rt.runeGlyph(r, fnt, len(glyphs)))
+			i += size
+			continue
+		}
+		end := strings.IndexByte(markup[i:], ']')
+		if end == -1 {
+			return nil, oakerr.InvalidInput{InputName: "markup: unterminated tag at " + strconv.Itoa(i)}
+		}
+		tag := markup[i+1 : i+end]
+		i += end + 1
+
+		if strings.HasPrefix(tag, "/") {
+			name := tag[1:]
+			if len(open) == 0 || open[len(open)-1].name != name {
+				return nil, oakerr.InvalidInput{InputName: "markup: unexpected [" + tag + "]"}
+			}
+			style = open[len(open)-1].style
+			open = open[:len(open)-1]
+			switch name {
+			case "color", "size", "font":
+			default:
+				if _, err := rt.tags(name, "", len(glyphs), true); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			name, value, _ := strings.Cut(tag, "=")
+			prev := style
+			switch name {
+			case "color":
+				if _, err := parseColor(value); err != nil {
+					return nil, oakerr.InvalidInput{InputName: "markup: [" + tag + "]"}
+				}
+				style.color = value
+			case "size":
+				size, err := strconv.ParseFloat(value, 64)
+				if err != nil || size <= 0 {
+					return nil, oakerr.InvalidInput{InputName: "markup: [" + tag + "]"}
+				}
+				style.size = size
+			case "font":
+				if _, ok := rt.opts.Fonts[value]; !ok {
+					return nil, oakerr.NotFound{InputName: "markup: font " + value}
+				}
+				style.font = value
+			case "icon":
+				icon, ok := rt.opts.Icons[value]
+				if !ok {
+					return nil, oakerr.NotFound{InputName: "markup: icon " + value}
+				}
+				rgba := icon.GetRGBA()
+				glyphs = append(glyphs, richGlyph{
+					icon:    rgba,
+					index:   len(glyphs),
+					advance: fixed.I(rgba.Bounds().Dx()),
+					height:  fixed.I(rgba.Bounds().Dy()),
+				})
+				continue
+			default:
+				if rt.tags == nil {
+					return nil, oakerr.InvalidInput{InputName: "markup: unknown tag [" + tag + "]"}
+				}
+				paired, err := rt.tags(name, value, len(glyphs), false)
+				if err != nil {
+					return nil, err
+				}
+				if !paired {
+					continue
+				}
+			}
+			open = append(open, openTag{name: name, style: prev})
+		}
+		if fnt, err = rt.styleFont(style); err != nil {
+			return nil, err
+		}
+	}
+	return glyphs, nil
+}
+
+func (rt *RichText) runeGlyph(r rune, fnt *Font, index int) richGlyph {
+	g := richGlyph{
+		r:      r,
+		font:   fnt,
+		index:  index,
+		height: fixed.Int26_6(fnt.Height() * 64),
+	}
+	if r != '\n' {
+		g.advance = fnt.MeasureString(string(r))
+	}
+	return g
+}
+
+// styleFont returns the font text in style is drawn with.
+func (rt *RichText) styleFont(style richStyle) (*Font, error) {
+	if fnt, ok := rt.fonts[style]; ok {
+		return fnt, nil
+	}
+	base := rt.font
+	if style.font != "" {
+		base = rt.opts.Fonts[style.font]
+	}
+	fnt := base
+	if style.size != 0 || style.color != "" {
+		var err error
+		fnt, err = base.RegenerateWith(func(fg FontGenerator) FontGenerator {
+			if style.size != 0 {
+				fg.Size = style.size
+			}
+			if style.color != "" {
+				c, _ := parseColor(style.color)
+				fg.Color = image.NewUniform(c)
+			}
+			return fg
+		})
+		if err != nil {
+			return nil, err
+		}
+		fnt.Fallbacks = base.Fallbacks
+		if style.size != 0 {
+			fnt.Fallbacks = make([]*Font, len(base.Fallbacks))
+			for i, fallback := range base.Fallbacks {
+				fnt.Fallbacks[i], err = fallback.RegenerateWith(func(fg FontGenerator) FontGenerator {
+					fg.Size = style.size
+					return fg
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	rt.fonts[style] = fnt
+	return fnt, nil
+}
+
+// parseColor parses an SVG 1.1 color name, or a #rrggbb or #rrggbbaa color.
+func parseColor(s string) (color.Color, error) {
+	if strings.HasPrefix(s, "#") && (len(s) == 7 || len(s) == 9) {
+		v, err := strconv.ParseUint(s[1:], 16, 32)
+		if err != nil {
+			return nil, oakerr.InvalidInput{InputName: "s"}
+		}
+		if len(s) == 7 {
+			v = v<<8 | 0xff
+		}
+		return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+	}
+	img, err := FontColor(s)
+	if err != nil {
+		return nil, err
+	}
+	return img.At(0, 0), nil
+}
+
+// A richLine is a range of a RichText's glyphs drawn on one line.
+type richLine struct {
+	start, end int
+	width      fixed.Int26_6
+	height     fixed.Int26_6
+	// hardBreak is whether this line ends a paragraph, rather than being wrapped
+	hardBreak bool
+}
+
+// layout positions this text's parsed glyphs into lines.
+func (rt *RichText) layout() {
+	glyphs := append([]richGlyph(nil), rt.parsed...)
+	lines := rt.wrap(glyphs)
+	maxWidth := fixed.I(rt.opts.Width)
+	if rt.opts.Width == 0 {
+		maxWidth = 0
+		for _, l := range lines {
+			if l.width > maxWidth {
+				maxWidth = l.width
+			}
+		}
+	}
+	spacing := rt.opts.LineSpacing
+	if spacing == 0 {
+		spacing = 1
+	}
+	var y fixed.Int26_6
+	for i, l := range lines {
+		var x, spaceExtra fixed.Int26_6
+		switch rt.opts.Align {
+		case AlignCenter:
+			x = (maxWidth - l.width) / 2
+		case AlignRight:
+			x = maxWidth - l.width
+		case AlignJustify:
+			if !l.hardBreak && i != len(lines)-1 {
+				spaces := 0
+				for _, g := range glyphs[l.start:l.end] {
+					if g.r == ' ' && g.icon == nil {
+						spaces++
+					}
+				}
+				if spaces > 0 {
+					spaceExtra = (maxWidth - l.width) / fixed.Int26_6(spaces)
+				}
+			}
+		}
+		for j := l.start; j < l.end; j++ {
+			g := &glyphs[j]
+			if g.icon != nil {
+				g.pos = fixed.Point26_6{X: x, Y: y + l.height - g.height}
+			} else {
+				g.pos = fixed.Point26_6{X: x, Y: y + l.height}
+			}
+			x += g.advance
+			if g.r == ' ' && g.icon == nil {
+				x += spaceExtra
+			}
+		}
+		if i != len(lines)-1 {
+			y += fixed.Int26_6(float64(l.height) * spacing)
+		} else {
+			y += l.height
+		}
+	}
+	// glyphs which were not placed on a line, i.e. newlines and spaces at wrapped line ends, are dropped
+	placed := make([]richGlyph, 0, len(glyphs))
+	for _, l := range lines {
+		placed = append(placed, glyphs[l.start:l.end]...)
+	}
+	rt.glyphs = placed
+	rt.lines = len(lines)
+	rt.w, rt.h = maxWidth.Ceil(), y.Ceil()
+}
+
+// wrap breaks glyphs into lines at newlines and, if this text has a width, between words.
+func (rt *RichText) wrap(glyphs []richGlyph) []richLine {
+	var lines []richLine
+	maxWidth := fixed.I(rt.opts.Width)
+	line := richLine{}
+	finish := func(end int, hardBreak bool) {
+		line.end = end
+		// trailing spaces are not drawn, and do not count towards the line's width
+		for line.end > line.start && glyphs[line.end-1].r == ' ' && glyphs[line.end-1].icon == nil {
+			line.end--
+			line.width -= glyphs[line.end].advance
+		}
+		line.hardBreak = hardBreak
+		if line.height == 0 {
+			line.height = fixed.Int26_6(rt.font.Height() * 64)
+		}
+		lines = append(lines, line)
+	}
+	for i := 0; i < len(glyphs); {
+		g := glyphs[i]
+		if g.r == '\n' && g.icon == nil {
+			finish(i, true)
+			i++
+			line = richLine{start: i}
+			continue
+		}
+		if g.r == ' ' && g.icon == nil {
+			if line.start == i && len(lines) > 0 && !lines[len(lines)-1].hardBreak {
+				// spaces at the start of wrapped lines are skipped
+				i++
+				line.start = i
+				continue
+			}
+			line.width += g.advance
+			i++
+			continue
+		}
+		// measure the word starting at i
+		end := i
+		var wordWidth, wordHeight fixed.Int26_6
+		for ; end < len(glyphs); end++ {
+			wg := glyphs[end]
+			if wg.icon == nil && (wg.r == ' ' || wg.r == '\n') {
+				break
+			}
+			wordWidth += wg.advance
+			if wg.height > wordHeight {
+				wordHeight = wg.height
+			}
+		}
+		if rt.opts.Width > 0 && line.width+wordWidth > maxWidth && line.start != i {
+			trimmed := line.width
+			for j := i - 1; j >= line.start && glyphs[j].r == ' ' && glyphs[j].icon == nil; j-- {
+				trimmed -= glyphs[j].advance
+			}
+			if trimmed+wordWidth > maxWidth {
+				finish(i, false)
+				line = richLine{start: i}
+			}
+		}
+		if rt.opts.Width > 0 && wordWidth > maxWidth {
+			// words wider than a line are broken wherever they reach its end
+			for ; i < end; i++ {
+				if line.width+glyphs[i].advance > maxWidth && line.start != i {
+					finish(i, false)
+					line = richLine{start: i}
+				}
+				line.width += glyphs[i].advance
+				if glyphs[i].height > line.height {
+					line.height = glyphs[i].height
+				}
+			}
+			continue
+		}
+		line.width += wordWidth
+		if wordHeight > line.height {
+			line.height = wordHeight
+		}
+		i = end
+	}
+	finish(len(glyphs), true)
+	return lines
+}
+
+// WrapString splits s into the lines it would be drawn over by this font if wrapped at width
+// pixels. Lines break at newlines, and at spaces where they would otherwise be too wide; words
+// wider than width are given their own line.
+func (f *Font) WrapString(s string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for i, word := range strings.Split(paragraph, " ") {
+			if i == 0 {
+				line = word
+				continue
+			}
+			if candidate := line + " " + word; f.MeasureString(candidate).Ceil() <= width {
+				line = candidate
+				continue
+			}
+			lines = append(lines, line)
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
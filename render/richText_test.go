@@ -0,0 +1,173 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+)
+
+func TestRichText_Markup(t *testing.T) {
+	fnt := DefaultFont()
+	rt, err := fnt.NewRichText("a [color=red]b [size=20]c[/size][/color] [[d", 0, 0, RichTextOptions{})
+	if err != nil {
+		t.Fatalf("failed to create rich text: %v", err)
+	}
+	if rt.String() != "a b c [d" {
+		t.Fatalf("expected text without markup, got %q", rt.String())
+	}
+	if rt.glyphs[2].font == rt.glyphs[0].font || rt.glyphs[4].font == rt.glyphs[2].font {
+		t.Fatal("expected styled glyphs to use derived fonts")
+	}
+	if rt.glyphs[6].font != rt.glyphs[0].font {
+		t.Fatal("expected closed styles to return to the base font")
+	}
+	if rt.glyphs[4].height <= rt.glyphs[0].height {
+		t.Fatal("expected larger size to be taller")
+	}
+
+	invalid := []string{
+		"[bold]a",
+		"[color=red",
+		"[color=red]a[/size]",
+		"a[/color]",
+		"[color=notacolor]a",
+		"[color=#12345]a",
+		"[size=-1]a",
+		"[font=missing]a",
+		"[icon=missing]",
+	}
+	for _, markup := range invalid {
+		if _, err := fnt.NewRichText(markup, 0, 0, RichTextOptions{}); err == nil {
+			t.Fatalf("expected %q to be invalid", markup)
+		}
+	}
+	if err := rt.SetMarkup("[bold]"); err == nil || rt.Markup() != "a [color=red]b [size=20]c[/size][/color] [[d" {
+		t.Fatal("expected invalid markup to leave text unchanged")
+	}
+}
+
+func TestRichText_Draw(t *testing.T) {
+	icon := NewColorBox(4, 4, color.RGBA{0, 0, 255, 255})
+	rt, err := DefaultFont().NewRichText("[color=#ff0000]W[/color][icon=coin]", 0, 0, RichTextOptions{
+		Icons: map[string]*Sprite{"coin": icon},
+	})
+	if err != nil {
+		t.Fatalf("failed to create rich text: %v", err)
+	}
+	buff := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	rt.Draw(buff, 0, 0)
+	var red, blue bool
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 20; y++ {
+			c := buff.RGBAAt(x, y)
+			if c.R > 0 && c.G == 0 && c.B == 0 {
+				red = true
+			}
+			if c == (color.RGBA{0, 0, 255, 255}) {
+				blue = true
+			}
+		}
+	}
+	if !red {
+		t.Fatal("expected red text")
+	}
+	if !blue {
+		t.Fatal("expected icon")
+	}
+	// the icon sits on the baseline, after the text
+	ig := rt.glyphs[1]
+	if ig.pos.X != rt.glyphs[0].advance || ig.pos.Y+ig.height != rt.glyphs[0].pos.Y {
+		t.Fatalf("expected icon on the baseline after the text, got %v", ig.pos)
+	}
+}
+
+func TestRichText_Wrap(t *testing.T) {
+	fnt := DefaultFont()
+	width := fnt.MeasureString("hello there").Ceil()
+	rt, err := fnt.NewRichText("hello there world\nnew", 0, 0, RichTextOptions{Width: width})
+	if err != nil {
+		t.Fatalf("failed to create rich text: %v", err)
+	}
+	if rt.LineCount() != 3 {
+		t.Fatalf("expected 3 lines, got %v", rt.LineCount())
+	}
+	w, h := rt.GetDims()
+	if w != width || h != 3*int(fnt.Height()) {
+		t.Fatalf("expected dims %v, %v, got %v, %v", width, 3*int(fnt.Height()), w, h)
+	}
+	// the space the line wrapped at is dropped
+	if len(rt.glyphs) != len("hello thereworldnew") {
+		t.Fatalf("expected wrapped space and newline to be dropped, got %v glyphs", len(rt.glyphs))
+	}
+	if rt.glyphs[11].pos.X != 0 || rt.glyphs[11].pos.Y <= rt.glyphs[10].pos.Y {
+		t.Fatal("expected wrapped word to start the next line")
+	}
+
+	rt.SetLineSpacing(2)
+	if _, h2 := rt.GetDims(); h2 != 5*int(fnt.Height()) {
+		t.Fatalf("expected line spacing to stretch lines, got height %v", h2)
+	}
+
+	rt.SetWidth(0)
+	if rt.LineCount() != 2 {
+		t.Fatalf("expected unwrapped text to break at newlines, got %v lines", rt.LineCount())
+	}
+
+	t.Run("LongWord", func(t *testing.T) {
+		rt, err := fnt.NewRichText("abcdefghij", 0, 0, RichTextOptions{Width: fnt.MeasureString("abc").Ceil()})
+		if err != nil {
+			t.Fatalf("failed to create rich text: %v", err)
+		}
+		if rt.LineCount() < 3 {
+			t.Fatalf("expected word wider than a line to be broken, got %v lines", rt.LineCount())
+		}
+	})
+}
+
+func TestRichText_Align(t *testing.T) {
+	fnt := DefaultFont()
+	const width = 200
+	lineEnd := func(rt *RichText, last int) fixed.Int26_6 {
+		return rt.glyphs[last].pos.X + rt.glyphs[last].advance
+	}
+	rt, err := fnt.NewRichText("one two", 0, 0, RichTextOptions{Width: width, Align: AlignRight})
+	if err != nil {
+		t.Fatalf("failed to create rich text: %v", err)
+	}
+	if lineEnd(rt, 6) != fixed.I(width) {
+		t.Fatalf("expected right aligned text to end at its width, got %v", lineEnd(rt, 6))
+	}
+	rt.SetAlignment(AlignCenter)
+	if left, right := rt.glyphs[0].pos.X, fixed.I(width)-lineEnd(rt, 6); left-right > 1 || right-left > 1 {
+		t.Fatalf("expected centered text, got margins %v and %v", left, right)
+	}
+
+	// justified lines fill the width, except the last
+	justifyWidth := fnt.MeasureString("one two three").Ceil()
+	rt, err = fnt.NewRichText("one two three four", 0, 0, RichTextOptions{Width: justifyWidth, Align: AlignJustify})
+	if err != nil {
+		t.Fatalf("failed to create rich text: %v", err)
+	}
+	if rt.LineCount() != 2 {
+		t.Fatalf("expected 2 lines, got %v", rt.LineCount())
+	}
+	if end := lineEnd(rt, 12); fixed.I(justifyWidth)-end > fixed.I(1) {
+		t.Fatalf("expected justified line to fill the width, got %v", end)
+	}
+	if rt.glyphs[13].pos.X != 0 {
+		t.Fatal("expected last line to be aligned left")
+	}
+}
+
+func TestFont_WrapString(t *testing.T) {
+	fnt := DefaultFont()
+	width := fnt.MeasureString("aa bb").Ceil()
+	lines := fnt.WrapString("aa bb cc\ndddddddddd ee", width)
+	expected := []string{"aa bb", "cc", "dddddddddd", "ee"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("expected %v, got %v", expected, lines)
+	}
+}
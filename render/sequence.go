@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/oakerr"
 	"github.com/oakmound/oak/v4/render/mod"
 	"github.com/oakmound/oak/v4/timing"
 )
@@ -17,10 +18,49 @@ type Sequence struct {
 	pauseBool
 	InterruptBool
 	rs         []Modifiable
-	lastChange time.Time
+	lastChange time.Duration
+	clock      *timing.Clock
 	sheetPos   int
 	frameTime  int64
+	// frameTimes, if set, are the durations of each frame, overriding frameTime
+	frameTimes []time.Duration
+	playMode   PlayMode
+	speed      float64
+	// backward is whether a ping-pong sequence is playing from its last frame to its first
+	backward  bool
+	frameTags []frameTag
+	// tagged is whether the current frame's tags have been triggered, as they are not when a
+	// sequence is first drawn or after it seeks
+	tagged bool
 	event.CallerID
+	triggerHandler
+}
+
+// A PlayMode is the order a Sequence plays its frames in.
+type PlayMode uint8
+
+// PlayModes
+const (
+	// PlayLoop plays frames from first to last, then starts over.
+	PlayLoop PlayMode = iota
+	// PlayOnce plays frames from first to last, then stays on the last frame.
+	PlayOnce
+	// PlayPingPong plays frames from first to last, then back to the first, then starts over.
+	PlayPingPong
+	// PlayReverse plays frames from last to first, then starts over.
+	PlayReverse
+)
+
+// A frameTag names a range of a sequence's frames, inclusive.
+type frameTag struct {
+	name     string
+	from, to int
+}
+
+// FrameTagged is the payload of AnimationFrame.
+type FrameTagged struct {
+	Tag   string
+	Frame int
 }
 
 // NewSequence returns a new sequence from the input modifiables, playing at
@@ -36,17 +76,86 @@ func NewSequence(fps float64, mods ...Modifiable) *Sequence {
 		},
 		sheetPos:   0,
 		frameTime:  timing.FPSToNano(fps),
+		speed:      1,
 		rs:         mods,
-		lastChange: time.Now(),
+		clock:      timing.DefaultClock,
+		lastChange: timing.DefaultClock.Now(),
 	}
 }
 
+// SetClock sets the clock this sequence measures its frame times by. Sequences use
+// timing.DefaultClock unless told otherwise, e.g. by being drawn via a scene's context.
+func (sq *Sequence) SetClock(c *timing.Clock) {
+	sq.lastChange = c.Now() - sq.clock.Since(sq.lastChange)
+	sq.clock = c
+}
+
 // SetFPS sets the number of frames that should advance per second to be
 // the input fps
 func (sq *Sequence) SetFPS(fps float64) {
 	sq.frameTime = timing.FPSToNano(fps)
 }
 
+// SetFrameDurations sets how long each frame of this sequence is shown for, in order, overriding its
+// fps. If the number of durations does not match the number of frames, an error is returned.
+func (sq *Sequence) SetFrameDurations(durations ...time.Duration) error {
+	if len(durations) != len(sq.rs) {
+		return oakerr.InvalidInput{InputName: "durations"}
+	}
+	sq.frameTimes = durations
+	return nil
+}
+
+// frameDuration returns how long, in nanoseconds, the current frame is shown for.
+func (sq *Sequence) frameDuration() int64 {
+	if len(sq.frameTimes) == len(sq.rs) {
+		return sq.frameTimes[sq.sheetPos].Nanoseconds()
+	}
+	return sq.frameTime
+}
+
+// SetPlayMode sets the order this sequence plays its frames in. Sequences loop unless told
+// otherwise. The current frame is kept.
+func (sq *Sequence) SetPlayMode(mode PlayMode) {
+	sq.playMode = mode
+	sq.backward = false
+}
+
+// SetSpeed scales how quickly this sequence plays, e.g. 2 for twice as fast, or .5 for half as
+// fast. A speed of 0 or below holds the current frame.
+func (sq *Sequence) SetSpeed(speed float64) {
+	sq.speed = speed
+}
+
+// Seek shows the given frame from its beginning. Frame tags on the frame are triggered when this
+// sequence is next drawn.
+func (sq *Sequence) Seek(frame int) error {
+	if frame < 0 || frame >= len(sq.rs) {
+		return oakerr.InvalidInput{InputName: "frame"}
+	}
+	sq.sheetPos = frame
+	sq.backward = false
+	sq.tagged = false
+	sq.lastChange = sq.clock.Now()
+	return nil
+}
+
+// Frame returns the index of the frame this sequence is showing.
+func (sq *Sequence) Frame() int {
+	return sq.sheetPos
+}
+
+// TagFrames names the frames from through to, inclusive. Each time one of these frames is shown,
+// including the first frame drawn, AnimationFrame is triggered with the tag on this sequence's
+// trigger ID. A frame can have multiple tags.
+func (sq *Sequence) TagFrames(tag string, from, to int) error {
+	if from < 0 || to >= len(sq.rs) || from > to {
+		return oakerr.InvalidInput{InputName: "frames"}
+	}
+	sq.frameTags = append(sq.frameTags, frameTag{name: tag, from: from, to: to})
+	return nil
+}
+
 // GetDims of a Sequence returns the dims of the current Renderable for the sequence
 func (sq *Sequence) GetDims() (int, int) {
 	return sq.rs[sq.sheetPos].GetDims()
@@ -64,25 +173,93 @@ func (sq *Sequence) Copy() Modifiable {
 	}
 
 	newSq.rs = newRs
+	newSq.frameTimes = append([]time.Duration(nil), sq.frameTimes...)
+	newSq.frameTags = append([]frameTag(nil), sq.frameTags...)
+	newSq.tagged = false
 	newSq.LayeredPoint = sq.LayeredPoint.Copy()
 	return newSq
 }
 
-var AnimationEnd = event.RegisterEvent[struct{}]()
+var (
+	AnimationEnd = event.RegisterNamedEvent[struct{}]("render.AnimationEnd")
+	// AnimationFrame is triggered when a sequence shows a frame it has tagged.
+	AnimationFrame = event.RegisterNamedEvent[FrameTagged]("render.AnimationFrame")
+)
 
 // SetTriggerID sets the ID that AnimationEnd will be triggered on when this
-// sequence loops over from its last frame to its first
+// sequence shows the last frame of its animation: the last frame when looping
+// or playing once, and the first frame when reversed or returning in ping-pong.
+// AnimationFrame is also triggered on this ID. Both are triggered on the handler set by
+// SetTriggerHandler.
 func (sq *Sequence) SetTriggerID(id event.CallerID) {
 	sq.CallerID = id
 }
 
 func (sq *Sequence) update() {
-	if sq.playing && time.Since(sq.lastChange).Nanoseconds() > sq.frameTime {
-		sq.lastChange = time.Now()
+	if !sq.tagged {
+		sq.tagged = true
+		sq.triggerFrameTags()
+	}
+	if !sq.playing || sq.speed <= 0 {
+		return
+	}
+	elapsed := float64(sq.clock.Since(sq.lastChange).Nanoseconds()) * sq.speed
+	if elapsed <= float64(sq.frameDuration()) {
+		return
+	}
+	sq.lastChange = sq.clock.Now()
+	last := len(sq.rs) - 1
+	prev := sq.sheetPos
+	var ended bool
+	switch sq.playMode {
+	case PlayLoop:
 		sq.sheetPos = (sq.sheetPos + 1) % len(sq.rs)
-		if sq.sheetPos == (len(sq.rs)-1) && sq.CallerID != 0 {
-			// TODO: not default bus
-			event.TriggerForCallerOn(event.DefaultBus, sq.CallerID, AnimationEnd, struct{}{})
+		ended = sq.sheetPos == last
+	case PlayOnce:
+		if sq.sheetPos < last {
+			sq.sheetPos++
+		}
+		ended = sq.sheetPos == last
+	case PlayReverse:
+		sq.sheetPos = (sq.sheetPos - 1 + len(sq.rs)) % len(sq.rs)
+		ended = sq.sheetPos == 0
+	case PlayPingPong:
+		if sq.sheetPos == last {
+			sq.backward = true
+		} else if sq.sheetPos == 0 {
+			sq.backward = false
+		}
+		if sq.backward {
+			sq.sheetPos--
+		} else {
+			sq.sheetPos++
+		}
+		if sq.sheetPos < 0 || sq.sheetPos > last {
+			// a single frame
+			sq.sheetPos = 0
+		}
+		ended = sq.backward && sq.sheetPos == 0
+	}
+	if sq.CallerID == 0 || (sq.playMode == PlayOnce && prev == last) {
+		return
+	}
+	sq.triggerFrameTags()
+	if ended {
+		event.TriggerForCallerOn(sq.triggerOn(), sq.CallerID, AnimationEnd, struct{}{})
+	}
+}
+
+// triggerFrameTags triggers AnimationFrame for each tag on the current frame.
+func (sq *Sequence) triggerFrameTags() {
+	if sq.CallerID == 0 {
+		return
+	}
+	for _, tag := range sq.frameTags {
+		if sq.sheetPos >= tag.from && sq.sheetPos <= tag.to {
+			event.TriggerForCallerOn(sq.triggerOn(), sq.CallerID, AnimationFrame, FrameTagged{
+				Tag:   tag.name,
+				Frame: sq.sheetPos,
+			})
 		}
 	}
 }
@@ -99,6 +276,12 @@ func (sq *Sequence) Get(i int) Modifiable {
 // Draw draws this sequence at +xOff, +yOff
 func (sq *Sequence) Draw(buff draw.Image, xOff, yOff float64) {
 	sq.update()
+	if sq.hasDrawOptions() {
+		if rgba := sq.rs[sq.sheetPos].GetRGBA(); rgba != nil {
+			sq.drawRGBA(buff, rgba, sq.X()+xOff, sq.Y()+yOff)
+			return
+		}
+	}
 	sq.rs[sq.sheetPos].Draw(buff, sq.X()+xOff, sq.Y()+yOff)
 }
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/oakmound/oak/v4/event"
 	"github.com/oakmound/oak/v4/render/mod"
+	"github.com/oakmound/oak/v4/timing"
 )
 
 type Dummy struct {
@@ -129,3 +130,207 @@ func TestTweenSequence(t *testing.T) {
 	TweenSequence(start.GetRGBA(), end.GetRGBA(), 2, 5)
 	// Tween behavior is tested elsewhere, this is just a "this doesn't crash" test
 }
+
+func TestSequence_SetFrameDurations(t *testing.T) {
+	sq := NewSequence(1,
+		NewColorBox(1, 1, color.RGBA{255, 0, 0, 255}),
+		NewColorBox(1, 1, color.RGBA{0, 255, 0, 255}))
+	if err := sq.SetFrameDurations(time.Second); err == nil {
+		t.Fatal("expected mismatched durations to fail")
+	}
+	if err := sq.SetFrameDurations(time.Nanosecond, time.Hour); err != nil {
+		t.Fatalf("failed to set durations: %v", err)
+	}
+	clock := timing.NewClock()
+	sq.SetClock(clock)
+	time.Sleep(time.Millisecond)
+	clock.Pause()
+	sq.update()
+	if sq.sheetPos != 1 {
+		t.Fatal("expected short first frame to have ended")
+	}
+	clock.Resume()
+	time.Sleep(time.Millisecond)
+	clock.Pause()
+	sq.update()
+	if sq.sheetPos != 1 {
+		t.Fatal("expected long second frame to still be showing")
+	}
+}
+
+// stepSequence advances a sequence whose frames last a nanosecond by one frame.
+func stepSequence(sq *Sequence) int {
+	time.Sleep(time.Microsecond)
+	sq.update()
+	return sq.Frame()
+}
+
+func nanosecondSequence(frames int) *Sequence {
+	mods := make([]Modifiable, frames)
+	durations := make([]time.Duration, frames)
+	for i := range mods {
+		mods[i] = NewColorBox(1, 1, color.RGBA{uint8(i), 0, 0, 255})
+		durations[i] = time.Nanosecond
+	}
+	sq := NewSequence(1, mods...)
+	sq.SetFrameDurations(durations...)
+	return sq
+}
+
+func TestSequence_SetPlayMode(t *testing.T) {
+	modes := map[PlayMode][]int{
+		PlayLoop:     {1, 2, 0, 1, 2, 0},
+		PlayOnce:     {1, 2, 2, 2, 2, 2},
+		PlayPingPong: {1, 2, 1, 0, 1, 2},
+		PlayReverse:  {2, 1, 0, 2, 1, 0},
+	}
+	for mode, frames := range modes {
+		sq := nanosecondSequence(3)
+		sq.SetPlayMode(mode)
+		for i, want := range frames {
+			if got := stepSequence(sq); got != want {
+				t.Fatalf("mode %v: expected frame %v at step %v, got %v", mode, want, i, got)
+			}
+		}
+	}
+	sq := nanosecondSequence(1)
+	sq.SetPlayMode(PlayPingPong)
+	if stepSequence(sq) != 0 {
+		t.Fatal("expected single frame ping-pong sequence to stay on its frame")
+	}
+}
+
+func TestSequence_SetSpeed(t *testing.T) {
+	sq := nanosecondSequence(2)
+	sq.SetSpeed(0)
+	if stepSequence(sq) != 0 {
+		t.Fatal("expected stopped sequence to hold its frame")
+	}
+	sq.SetFrameDurations(time.Hour, time.Hour)
+	sq.SetSpeed(1e15)
+	if stepSequence(sq) != 1 {
+		t.Fatal("expected fast sequence to advance")
+	}
+}
+
+func TestSequence_Seek(t *testing.T) {
+	sq := nanosecondSequence(3)
+	if err := sq.Seek(2); err != nil {
+		t.Fatalf("failed to seek: %v", err)
+	}
+	if sq.Frame() != 2 {
+		t.Fatalf("expected frame 2, got %v", sq.Frame())
+	}
+	if sq.GetRGBA().RGBAAt(0, 0).R != 2 {
+		t.Fatal("expected sought frame to be shown")
+	}
+	if err := sq.Seek(3); err == nil {
+		t.Fatal("expected seeking past the last frame to fail")
+	}
+}
+
+func TestSequence_TagFrames(t *testing.T) {
+	sq := nanosecondSequence(4)
+	if err := sq.TagFrames("bad", 2, 4); err == nil {
+		t.Fatal("expected tag past the last frame to fail")
+	}
+	if err := sq.TagFrames("footstep", 1, 1); err != nil {
+		t.Fatalf("failed to tag frames: %v", err)
+	}
+	if err := sq.TagFrames("active", 1, 2); err != nil {
+		t.Fatalf("failed to tag frames: %v", err)
+	}
+	bus := event.NewBus(event.NewCallerMap())
+	d := Dummy{}
+	d.CallerID = bus.GetCallerMap().Register(d)
+	sq.SetTriggerID(d.CallerID)
+	sq.SetTriggerHandler(bus)
+	tagged := make(chan FrameTagged, 10)
+	b := event.Bind(bus, AnimationFrame, d, func(_ Dummy, ft FrameTagged) event.Response {
+		tagged <- ft
+		return 0
+	})
+	<-b.Bound
+	ended := make(chan struct{}, 10)
+	b = event.Bind(bus, AnimationEnd, d, func(_ Dummy, _ struct{}) event.Response {
+		ended <- struct{}{}
+		return 0
+	})
+	<-b.Bound
+
+	expectTags := func(want ...FrameTagged) {
+		t.Helper()
+		got := map[FrameTagged]bool{}
+		for range want {
+			select {
+			case ft := <-tagged:
+				got[ft] = true
+			case <-time.After(time.Second):
+				t.Fatalf("expected %v tagged frames, got %v", len(want), len(got))
+			}
+		}
+		for _, ft := range want {
+			if !got[ft] {
+				t.Fatalf("expected %v to be triggered", ft)
+			}
+		}
+	}
+	stepSequence(sq)
+	expectTags(FrameTagged{"footstep", 1}, FrameTagged{"active", 1})
+	stepSequence(sq)
+	expectTags(FrameTagged{"active", 2})
+	stepSequence(sq)
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		t.Fatal("expected animation end on the last frame")
+	}
+	stepSequence(sq)
+	stepSequence(sq)
+	expectTags(FrameTagged{"footstep", 1}, FrameTagged{"active", 1})
+	select {
+	case ft := <-tagged:
+		t.Fatalf("expected no other tagged frames, got %v", ft)
+	default:
+	}
+
+	sq2 := sq.Copy().(*Sequence)
+	if len(sq2.frameTags) != 2 {
+		t.Fatal("expected copy to keep frame tags")
+	}
+}
+
+func TestSequence_TagFirstFrame(t *testing.T) {
+	sq := nanosecondSequence(3)
+	sq.Pause()
+	if err := sq.TagFrames("start", 0, 0); err != nil {
+		t.Fatalf("failed to tag frames: %v", err)
+	}
+	if err := sq.TagFrames("end", 2, 2); err != nil {
+		t.Fatalf("failed to tag frames: %v", err)
+	}
+	bus := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+	d := Dummy{}
+	d.CallerID = bus.GetCallerMap().Register(d)
+	sq.SetTriggerID(d.CallerID)
+	sq.SetTriggerHandler(bus)
+	var tagged []FrameTagged
+	event.Bind(bus, AnimationFrame, d, func(_ Dummy, ft FrameTagged) event.Response {
+		tagged = append(tagged, ft)
+		return 0
+	})
+
+	sq.update()
+	sq.update()
+	if !reflect.DeepEqual(tagged, []FrameTagged{{"start", 0}}) {
+		t.Fatalf("expected the first frame's tag to be triggered once, got %v", tagged)
+	}
+	if err := sq.Seek(2); err != nil {
+		t.Fatalf("failed to seek: %v", err)
+	}
+	sq.update()
+	sq.update()
+	if !reflect.DeepEqual(tagged, []FrameTagged{{"start", 0}, {"end", 2}}) {
+		t.Fatalf("expected the sought frame's tag to be triggered once, got %v", tagged)
+	}
+}
@@ -72,7 +72,7 @@ func (s *Sprite) Set(x, y int, c color.Color) {
 
 // Draw draws this sprite at +xOff, +yOff
 func (s *Sprite) Draw(buff draw.Image, xOff, yOff float64) {
-	DrawImage(buff, s.r, int(s.X()+xOff), int(s.Y()+yOff))
+	s.drawRGBA(buff, s.r, s.X()+xOff, s.Y()+yOff)
 }
 
 // Copy returns a copy of this Sprite
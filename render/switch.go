@@ -9,6 +9,7 @@ import (
 	"github.com/oakmound/oak/v4/oakerr"
 	"github.com/oakmound/oak/v4/physics"
 	"github.com/oakmound/oak/v4/render/mod"
+	"github.com/oakmound/oak/v4/timing"
 )
 
 // The Switch type is intended for use to easily swap between multiple
@@ -128,7 +129,12 @@ func (c *Switch) Filter(fs ...mod.Filter) {
 //Draw draws the Switch at an offset from its logical location
 func (c *Switch) Draw(buff draw.Image, xOff float64, yOff float64) {
 	c.lock.RLock()
-	c.subRenderables[c.curRenderable].Draw(buff, c.X()+xOff, c.Y()+yOff)
+	r := c.subRenderables[c.curRenderable]
+	if rgba := r.GetRGBA(); c.hasDrawOptions() && rgba != nil {
+		c.drawRGBA(buff, rgba, c.X()+xOff, c.Y()+yOff)
+	} else {
+		r.Draw(buff, c.X()+xOff, c.Y()+yOff)
+	}
 	c.lock.RUnlock()
 }
 
@@ -197,6 +203,28 @@ func (c *Switch) SetTriggerID(cid event.CallerID) {
 	c.lock.RUnlock()
 }
 
+// SetTriggerHandler sets the handler of each of this switch's TriggerHandled sub renderables.
+func (c *Switch) SetTriggerHandler(h event.Handler) {
+	c.lock.RLock()
+	for _, r := range c.subRenderables {
+		if th, ok := r.(TriggerHandled); ok {
+			th.SetTriggerHandler(h)
+		}
+	}
+	c.lock.RUnlock()
+}
+
+// SetClock sets the clock of each of this switch's Clocked sub renderables.
+func (c *Switch) SetClock(clock *timing.Clock) {
+	c.lock.RLock()
+	for _, r := range c.subRenderables {
+		if cl, ok := r.(Clocked); ok {
+			cl.SetClock(clock)
+		}
+	}
+	c.lock.RUnlock()
+}
+
 // Revert will revert all parts of this Switch that can be reverted
 func (c *Switch) Revert(mod int) {
 	c.lock.RLock()
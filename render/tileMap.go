@@ -0,0 +1,320 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+	"sync"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/timing"
+)
+
+// EmptyTile marks a tile map cell with no tile.
+const EmptyTile = -1
+
+// DefaultTileChunkSize is the width and height, in tiles, of the chunks a TileMap pre-renders.
+const DefaultTileChunkSize = 16
+
+// A TileFrame is one frame of an animated tile.
+type TileFrame struct {
+	Tile     int
+	Duration time.Duration
+}
+
+// A TileMap is a grid of tiles from a Sheet, drawn in layers. Tiles are identified by their index
+// in the sheet, counting across each row of the sheet before moving to the next.
+//
+// Rather than drawing every tile each frame, a TileMap pre-renders its static tiles into chunks,
+// and only draws the chunks and animated tiles which intersect the viewport.
+type TileMap struct {
+	LayeredPoint
+
+	mu         sync.Mutex
+	sheet      *Sheet
+	tileW      int
+	tileH      int
+	w, h       int
+	layers     [][]int
+	animations map[int][]TileFrame
+	clock      *timing.Clock
+
+	chunkSize int
+	// chunks are the pre-rendered chunks of each layer, nil until rendered
+	chunks [][]*image.RGBA
+}
+
+// NewTileMap creates a tile map w by h tiles in size, with the given number of layers, drawing
+// tiles from sheet. All cells start empty.
+func NewTileMap(sheet *Sheet, w, h, layers int) (*TileMap, error) {
+	if sheet == nil || len(*sheet) == 0 || len((*sheet)[0]) == 0 {
+		return nil, oakerr.NilInput{InputName: "sheet"}
+	}
+	if w <= 0 || h <= 0 {
+		return nil, oakerr.InvalidInput{InputName: "w, h"}
+	}
+	if layers <= 0 {
+		return nil, oakerr.InvalidInput{InputName: "layers"}
+	}
+	bds := (*sheet)[0][0].Bounds()
+	tm := &TileMap{
+		LayeredPoint: NewLayeredPoint(0, 0, 0),
+		sheet:        sheet,
+		tileW:        bds.Dx(),
+		tileH:        bds.Dy(),
+		w:            w,
+		h:            h,
+		layers:       make([][]int, layers),
+		animations:   make(map[int][]TileFrame),
+		clock:        timing.DefaultClock,
+		chunkSize:    DefaultTileChunkSize,
+	}
+	for i := range tm.layers {
+		tm.layers[i] = make([]int, w*h)
+		for j := range tm.layers[i] {
+			tm.layers[i][j] = EmptyTile
+		}
+	}
+	tm.resetChunks()
+	return tm, nil
+}
+
+// resetChunks discards every pre-rendered chunk. It must be called while holding the lock.
+func (tm *TileMap) resetChunks() {
+	count := tm.chunksX() * tm.chunksY()
+	tm.chunks = make([][]*image.RGBA, len(tm.layers))
+	for i := range tm.chunks {
+		tm.chunks[i] = make([]*image.RGBA, count)
+	}
+}
+
+func (tm *TileMap) chunksX() int {
+	return (tm.w + tm.chunkSize - 1) / tm.chunkSize
+}
+
+func (tm *TileMap) chunksY() int {
+	return (tm.h + tm.chunkSize - 1) / tm.chunkSize
+}
+
+// SetChunkSize sets the width and height, in tiles, of the chunks this map pre-renders. Larger
+// chunks mean fewer draw calls, but more work when a tile changes.
+func (tm *TileMap) SetChunkSize(tiles int) {
+	if tiles <= 0 {
+		return
+	}
+	tm.mu.Lock()
+	tm.chunkSize = tiles
+	tm.resetChunks()
+	tm.mu.Unlock()
+}
+
+// SetClock sets the clock animated tiles measure their frame times by. Tile maps use
+// timing.DefaultClock unless told otherwise, e.g. by being drawn via a scene's context.
+func (tm *TileMap) SetClock(c *timing.Clock) {
+	tm.mu.Lock()
+	tm.clock = c
+	tm.mu.Unlock()
+}
+
+// TileSize returns the width and height of this map's tiles in pixels.
+func (tm *TileMap) TileSize() (int, int) {
+	return tm.tileW, tm.tileH
+}
+
+// Size returns the width and height of this map in tiles.
+func (tm *TileMap) Size() (int, int) {
+	return tm.w, tm.h
+}
+
+// Layers returns the number of layers in this map.
+func (tm *TileMap) Layers() int {
+	return len(tm.layers)
+}
+
+// GetDims returns the size of this map in pixels.
+func (tm *TileMap) GetDims() (int, int) {
+	return tm.w * tm.tileW, tm.h * tm.tileH
+}
+
+func (tm *TileMap) inBounds(layer, x, y int) bool {
+	return layer >= 0 && layer < len(tm.layers) && x >= 0 && y >= 0 && x < tm.w && y < tm.h
+}
+
+// Tile returns the tile at a cell of a layer, or EmptyTile if the cell is out of bounds.
+func (tm *TileMap) Tile(layer, x, y int) int {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if !tm.inBounds(layer, x, y) {
+		return EmptyTile
+	}
+	return tm.layers[layer][y*tm.w+x]
+}
+
+// SetTile sets the tile at a cell of a layer. Only the chunk containing the cell is re-rendered.
+func (tm *TileMap) SetTile(layer, x, y, tile int) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if !tm.inBounds(layer, x, y) {
+		return oakerr.InvalidInput{InputName: "layer, x, y"}
+	}
+	tm.layers[layer][y*tm.w+x] = tile
+	tm.chunks[layer][(y/tm.chunkSize)*tm.chunksX()+x/tm.chunkSize] = nil
+	return nil
+}
+
+// SetTiles sets every tile of a layer, from tiles in row-major order.
+func (tm *TileMap) SetTiles(layer int, tiles []int) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if layer < 0 || layer >= len(tm.layers) {
+		return oakerr.InvalidInput{InputName: "layer"}
+	}
+	if len(tiles) != tm.w*tm.h {
+		return oakerr.InvalidInput{InputName: "tiles"}
+	}
+	copy(tm.layers[layer], tiles)
+	for i := range tm.chunks[layer] {
+		tm.chunks[layer][i] = nil
+	}
+	return nil
+}
+
+// AnimateTile makes every cell containing tile cycle through frames instead. Animating a tile with
+// no frames stops its animation.
+func (tm *TileMap) AnimateTile(tile int, frames ...TileFrame) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if len(frames) == 0 {
+		delete(tm.animations, tile)
+	} else {
+		tm.animations[tile] = frames
+	}
+	// animated tiles are left out of chunks, so any chunk may have changed
+	tm.resetChunks()
+}
+
+// sheetTile returns the image of a tile, if it is in the sheet.
+func (tm *TileMap) sheetTile(tile int) (*image.RGBA, bool) {
+	sh := *tm.sheet
+	if tile < 0 {
+		return nil, false
+	}
+	x, y := tile%len(sh), tile/len(sh)
+	if y >= len(sh[x]) {
+		return nil, false
+	}
+	return sh[x][y], true
+}
+
+// frame returns the tile an animated tile is currently showing.
+func (tm *TileMap) frame(frames []TileFrame, now time.Duration) int {
+	var total time.Duration
+	for _, f := range frames {
+		total += f.Duration
+	}
+	if total <= 0 {
+		return frames[0].Tile
+	}
+	now %= total
+	for _, f := range frames {
+		if now < f.Duration {
+			return f.Tile
+		}
+		now -= f.Duration
+	}
+	return frames[len(frames)-1].Tile
+}
+
+// chunk returns the pre-rendered static tiles of a chunk of a layer, rendering it if needed.
+func (tm *TileMap) chunk(layer, cx, cy int) *image.RGBA {
+	i := cy*tm.chunksX() + cx
+	if c := tm.chunks[layer][i]; c != nil {
+		return c
+	}
+	c := image.NewRGBA(image.Rect(0, 0, tm.chunkSize*tm.tileW, tm.chunkSize*tm.tileH))
+	for y := cy * tm.chunkSize; y < (cy+1)*tm.chunkSize && y < tm.h; y++ {
+		for x := cx * tm.chunkSize; x < (cx+1)*tm.chunkSize && x < tm.w; x++ {
+			tile := tm.layers[layer][y*tm.w+x]
+			if _, animated := tm.animations[tile]; animated {
+				continue
+			}
+			img, ok := tm.sheetTile(tile)
+			if !ok {
+				continue
+			}
+			pt := image.Point{(x - cx*tm.chunkSize) * tm.tileW, (y - cy*tm.chunkSize) * tm.tileH}
+			draw.Draw(c, image.Rectangle{pt, pt.Add(img.Bounds().Size())}, img, img.Bounds().Min, draw.Src)
+		}
+	}
+	tm.chunks[layer][i] = c
+	return c
+}
+
+// Draw draws the tiles of this map which are visible within buff.
+func (tm *TileMap) Draw(buff draw.Image, xOff, yOff float64) {
+	// buff may be a sub image not starting at the origin; tiles drawn outside of it are clipped
+	bds := buff.Bounds()
+	tm.DrawToScreen(buff, &intgeom.Point2{int(-xOff), int(-yOff)}, bds.Max.X, bds.Max.Y)
+}
+
+// DrawToScreen draws the tiles of this map intersecting the w by h viewport at view, in world
+// coordinates, to world.
+func (tm *TileMap) DrawToScreen(world draw.Image, view *intgeom.Point2, w, h int) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	// the viewport, relative to the map
+	mapX, mapY := int(tm.X()), int(tm.Y())
+	minX, minY := view.X()-mapX, view.Y()-mapY
+	maxX, maxY := minX+w, minY+h
+	if maxX <= 0 || maxY <= 0 || minX >= tm.w*tm.tileW || minY >= tm.h*tm.tileH {
+		return
+	}
+	// screen position of the map's origin
+	originX, originY := mapX-view.X(), mapY-view.Y()
+
+	chunkW, chunkH := tm.chunkSize*tm.tileW, tm.chunkSize*tm.tileH
+	cx0, cy0 := clampInt(minX/chunkW, 0, tm.chunksX()-1), clampInt(minY/chunkH, 0, tm.chunksY()-1)
+	cx1, cy1 := clampInt((maxX-1)/chunkW, 0, tm.chunksX()-1), clampInt((maxY-1)/chunkH, 0, tm.chunksY()-1)
+	tx0, ty0 := clampInt(minX/tm.tileW, 0, tm.w-1), clampInt(minY/tm.tileH, 0, tm.h-1)
+	tx1, ty1 := clampInt((maxX-1)/tm.tileW, 0, tm.w-1), clampInt((maxY-1)/tm.tileH, 0, tm.h-1)
+	now := tm.clock.Now()
+
+	for layer := range tm.layers {
+		for cy := cy0; cy <= cy1; cy++ {
+			for cx := cx0; cx <= cx1; cx++ {
+				c := tm.chunk(layer, cx, cy)
+				pt := image.Point{originX + cx*chunkW, originY + cy*chunkH}
+				draw.Draw(world, image.Rectangle{pt, pt.Add(c.Bounds().Size())}, c, image.Point{}, draw.Over)
+			}
+		}
+		if len(tm.animations) == 0 {
+			continue
+		}
+		for y := ty0; y <= ty1; y++ {
+			for x := tx0; x <= tx1; x++ {
+				frames, ok := tm.animations[tm.layers[layer][y*tm.w+x]]
+				if !ok {
+					continue
+				}
+				img, ok := tm.sheetTile(tm.frame(frames, now))
+				if !ok {
+					continue
+				}
+				pt := image.Point{originX + x*tm.tileW, originY + y*tm.tileH}
+				draw.Draw(world, image.Rectangle{pt, pt.Add(img.Bounds().Size())}, img, img.Bounds().Min, draw.Over)
+			}
+		}
+	}
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
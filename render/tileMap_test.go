@@ -0,0 +1,107 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/timing"
+)
+
+func testTileSheet() *Sheet {
+	red := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	green := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for x := 0; x < 2; x++ {
+		for y := 0; y < 2; y++ {
+			red.Set(x, y, color.RGBA{255, 0, 0, 255})
+			green.Set(x, y, color.RGBA{0, 255, 0, 255})
+		}
+	}
+	return &Sheet{{red}, {green}}
+}
+
+func TestNewTileMap_Errors(t *testing.T) {
+	if _, err := NewTileMap(nil, 1, 1, 1); err == nil {
+		t.Fatal("expected error for nil sheet")
+	}
+	if _, err := NewTileMap(testTileSheet(), 0, 1, 1); err == nil {
+		t.Fatal("expected error for zero width")
+	}
+	if _, err := NewTileMap(testTileSheet(), 1, 1, 0); err == nil {
+		t.Fatal("expected error for zero layers")
+	}
+}
+
+func TestTileMap_Draw(t *testing.T) {
+	tm, err := NewTileMap(testTileSheet(), 5, 3, 2)
+	if err != nil {
+		t.Fatalf("new tile map failed: %v", err)
+	}
+	tm.SetChunkSize(2)
+	if w, h := tm.GetDims(); w != 10 || h != 6 {
+		t.Fatalf("expected dims 10x6, got %vx%v", w, h)
+	}
+	if err := tm.SetTile(0, 5, 0, 0); err == nil {
+		t.Fatal("expected out of bounds error")
+	}
+	tiles := make([]int, 15)
+	if err := tm.SetTiles(0, tiles); err != nil {
+		t.Fatalf("set tiles failed: %v", err)
+	}
+	tm.SetTile(1, 3, 1, 1)
+	if tm.Tile(1, 3, 1) != 1 || tm.Tile(1, 0, 0) != EmptyTile {
+		t.Fatal("unexpected tiles")
+	}
+
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	buff := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	// view the map from (5, 1), so tile (3, 1) is drawn at (1, 1)
+	tm.DrawToScreen(buff, &intgeom.Point2{5, 1}, 4, 4)
+	if buff.RGBAAt(1, 1) != green || buff.RGBAAt(0, 0) != red || buff.RGBAAt(3, 3) != red {
+		t.Fatalf("unexpected draw: %v %v %v", buff.RGBAAt(1, 1), buff.RGBAAt(0, 0), buff.RGBAAt(3, 3))
+	}
+
+	// updating a tile re-renders its chunk
+	tm.SetTile(1, 3, 1, EmptyTile)
+	buff = image.NewRGBA(image.Rect(0, 0, 4, 4))
+	tm.Draw(buff, -5, -1)
+	if buff.RGBAAt(1, 1) != red {
+		t.Fatalf("expected updated tile to be red, got %v", buff.RGBAAt(1, 1))
+	}
+
+	// nothing is drawn outside of the map
+	buff = image.NewRGBA(image.Rect(0, 0, 4, 4))
+	tm.DrawToScreen(buff, &intgeom.Point2{9, 5}, 4, 4)
+	if buff.RGBAAt(0, 0) != red || buff.RGBAAt(1, 1) != (color.RGBA{}) {
+		t.Fatalf("unexpected draw at map edge: %v %v", buff.RGBAAt(0, 0), buff.RGBAAt(1, 1))
+	}
+}
+
+func TestTileMap_AnimateTile(t *testing.T) {
+	tm, err := NewTileMap(testTileSheet(), 2, 2, 1)
+	if err != nil {
+		t.Fatalf("new tile map failed: %v", err)
+	}
+	clock := timing.NewClock()
+	tm.SetClock(clock)
+	tm.SetTile(0, 1, 1, 0)
+	tm.AnimateTile(0, TileFrame{Tile: 0, Duration: time.Nanosecond}, TileFrame{Tile: 1, Duration: time.Hour})
+	time.Sleep(time.Millisecond)
+	clock.Pause()
+
+	buff := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	tm.DrawToScreen(buff, &intgeom.Point2{}, 4, 4)
+	if buff.RGBAAt(2, 2) != (color.RGBA{0, 255, 0, 255}) {
+		t.Fatalf("expected animated tile on its second frame, got %v", buff.RGBAAt(2, 2))
+	}
+
+	tm.AnimateTile(0)
+	buff = image.NewRGBA(image.Rect(0, 0, 4, 4))
+	tm.DrawToScreen(buff, &intgeom.Point2{}, 4, 4)
+	if buff.RGBAAt(2, 2) != (color.RGBA{255, 0, 0, 255}) {
+		t.Fatalf("expected stopped tile to be red, got %v", buff.RGBAAt(2, 2))
+	}
+}
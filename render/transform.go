@@ -0,0 +1,215 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+// Sampling is how a transformed image chooses the colors of the pixels it covers.
+type Sampling uint8
+
+// Sampling types
+const (
+	// NearestNeighbor takes each pixel's color from the closest pixel of the source image. It
+	// is the fastest sampling, and keeps pixel art crisp.
+	NearestNeighbor Sampling = iota
+	// Bilinear blends each pixel's color from the four closest pixels of the source image,
+	// smoothing rotated and scaled edges.
+	Bilinear
+)
+
+// A Transform rotates, scales and flips a renderable's image as it is drawn, without changing
+// the image itself. Unlike mod.Rotate and mod.Scale, changing a transform every frame does not
+// allocate a new image every frame.
+type Transform struct {
+	// Rotation is counter-clockwise, in degrees, like mod.Rotate.
+	Rotation float64
+	// Scale multiplies the image's width and height. Zero components are treated as 1.
+	Scale floatgeom.Point2
+	// FlipX mirrors the image horizontally, and FlipY vertically.
+	FlipX, FlipY bool
+	// Pivot is the point the image is rotated, scaled and flipped around, as a fraction of its
+	// width and height. The zero pivot is the image's top left corner; {.5, .5} is its center.
+	// The pivot stays where it would be drawn without a transform.
+	Pivot    floatgeom.Point2
+	Sampling Sampling
+}
+
+// SetTransform sets a transform to apply when drawing. Transforms are honored by Sprites, Sequences
+// and Switches, and by RenderableHeaps and CompositeRs when culling renderables outside the view.
+func (ldp *LayeredPoint) SetTransform(t Transform) {
+	if ldp.transform == nil {
+		ldp.transform = new(Transform)
+	}
+	*ldp.transform = t
+}
+
+// GetTransform returns the transform set via SetTransform, if any.
+func (ldp *LayeredPoint) GetTransform() (Transform, bool) {
+	if ldp.transform == nil {
+		return Transform{}, false
+	}
+	return *ldp.transform, true
+}
+
+// ClearTransform removes any transform set via SetTransform.
+func (ldp *LayeredPoint) ClearTransform() {
+	ldp.transform = nil
+}
+
+type transformable interface {
+	GetTransform() (Transform, bool)
+}
+
+// matrix returns the linear part of a transform, mapping image positions relative to the pivot
+// to drawn positions relative to the pivot.
+func (t Transform) matrix() (a, b, c, d float64) {
+	sx, sy := t.Scale.X(), t.Scale.Y()
+	if sx == 0 {
+		sx = 1
+	}
+	if sy == 0 {
+		sy = 1
+	}
+	if t.FlipX {
+		sx = -sx
+	}
+	if t.FlipY {
+		sy = -sy
+	}
+	sin, cos := math.Sincos(t.Rotation * math.Pi / 180)
+	// y increases downwards, so a counter-clockwise rotation negates the usual sin terms
+	return cos * sx, sin * sy, -sin * sx, cos * sy
+}
+
+// bounds returns the area a w by h image covers when drawn with this transform, relative to
+// where the image would be drawn without it.
+func (t Transform) bounds(w, h int) floatgeom.Rect2 {
+	a, b, c, d := t.matrix()
+	px, py := t.Pivot.X()*float64(w), t.Pivot.Y()*float64(h)
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [4][2]float64{{0, 0}, {float64(w), 0}, {0, float64(h)}, {float64(w), float64(h)}} {
+		x, y := corner[0]-px, corner[1]-py
+		dx, dy := a*x+b*y+px, c*x+d*y+py
+		minX, maxX = math.Min(minX, dx), math.Max(maxX, dx)
+		minY, maxY = math.Min(minY, dy), math.Max(maxY, dy)
+	}
+	return floatgeom.NewRect2(minX, minY, maxX, maxY)
+}
+
+// drawBounds returns the area, relative to r's position, that r covers when drawn.
+func drawBounds(r Renderable) (x, y, w, h int) {
+	w, h = r.GetDims()
+	if tr, ok := r.(transformable); ok {
+		if t, ok := tr.GetTransform(); ok {
+			b := t.bounds(w, h)
+			minX, minY := int(math.Floor(b.Min.X())), int(math.Floor(b.Min.Y()))
+			return minX, minY, int(math.Ceil(b.Max.X())) - minX, int(math.Ceil(b.Max.Y())) - minY
+		}
+	}
+	return 0, 0, w, h
+}
+
+// inView reports whether r, drawn from viewPos, would be visible on a screenW by screenH screen.
+func inView(r Renderable, viewPos [2]int, screenW, screenH int) bool {
+	bx, by, w, h := drawBounds(r)
+	x2 := int(r.X()) + bx
+	y2 := int(r.Y()) + by
+	x := w + x2
+	y := h + y2
+	return x > viewPos[0] && y > viewPos[1] &&
+		x2 < viewPos[0]+screenW && y2 < viewPos[1]+screenH
+}
+
+// drawTransformed draws img to buff with its top left corner at x, y before t is applied.
+func drawTransformed(buff draw.Image, img *image.RGBA, x, y float64, t Transform, mode BlendMode) {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w == 0 || h == 0 {
+		return
+	}
+	a, b, c, d := t.matrix()
+	det := a*d - b*c
+	if det == 0 {
+		return
+	}
+	// the inverse matrix maps drawn positions back to image positions
+	ia, ib, ic, id := d/det, -b/det, -c/det, a/det
+	px, py := t.Pivot.X()*float64(w), t.Pivot.Y()*float64(h)
+
+	bds := t.bounds(w, h)
+	area := image.Rect(
+		int(math.Floor(x+bds.Min.X())), int(math.Floor(y+bds.Min.Y())),
+		int(math.Ceil(x+bds.Max.X())), int(math.Ceil(y+bds.Max.Y())),
+	).Intersect(buff.Bounds())
+	if area.Empty() {
+		return
+	}
+
+	min := img.Bounds().Min
+	blendArea(buff, area, func(dst *image.RGBA) {
+		for dy := area.Min.Y; dy < area.Max.Y; dy++ {
+			ry := float64(dy) + .5 - y - py
+			for dx := area.Min.X; dx < area.Max.X; dx++ {
+				rx := float64(dx) + .5 - x - px
+				sx := ia*rx + ib*ry + px
+				sy := ic*rx + id*ry + py
+				var src [4]uint8
+				if t.Sampling == Bilinear {
+					src = sampleBilinear(img, sx-.5, sy-.5)
+				} else {
+					ix, iy := int(math.Floor(sx)), int(math.Floor(sy))
+					if ix < 0 || iy < 0 || ix >= w || iy >= h {
+						continue
+					}
+					i := img.PixOffset(min.X+ix, min.Y+iy)
+					copy(src[:], img.Pix[i:i+4])
+				}
+				if src == ([4]uint8{}) {
+					continue
+				}
+				blendPixel(dst.Pix[dst.PixOffset(dx, dy):], src, mode)
+			}
+		}
+	})
+}
+
+// sampleBilinear blends the four pixels of img surrounding x, y. Positions outside of img are
+// transparent.
+func sampleBilinear(img *image.RGBA, x, y float64) [4]uint8 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	min := img.Bounds().Min
+	var out [4]float64
+	for _, s := range [4]struct {
+		dx, dy int
+		weight float64
+	}{
+		{0, 0, (1 - fx) * (1 - fy)},
+		{1, 0, fx * (1 - fy)},
+		{0, 1, (1 - fx) * fy},
+		{1, 1, fx * fy},
+	} {
+		ix, iy := int(x0)+s.dx, int(y0)+s.dy
+		if s.weight == 0 || ix < 0 || iy < 0 || ix >= w || iy >= h {
+			continue
+		}
+		i := img.PixOffset(min.X+ix, min.Y+iy)
+		for c := 0; c < 4; c++ {
+			out[c] += float64(img.Pix[i+c]) * s.weight
+		}
+	}
+	return [4]uint8{uint8(out[0] + .5), uint8(out[1] + .5), uint8(out[2] + .5), uint8(out[3] + .5)}
+}
+
+// blendOver draws the premultiplied color src over the pixel at the start of dst.
+func blendOver(dst []uint8, src [4]uint8) {
+	inv := 255 - uint32(src[3])
+	for c := 0; c < 4; c++ {
+		dst[c] = uint8(uint32(src[c]) + (uint32(dst[c])*inv+127)/255)
+	}
+}
@@ -0,0 +1,151 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+var (
+	transformRed  = color.RGBA{255, 0, 0, 255}
+	transformBlue = color.RGBA{0, 0, 255, 255}
+)
+
+// halvesSprite returns a 4x2 sprite, red on its left half and blue on its right.
+func halvesSprite() *Sprite {
+	rgba := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			if x < 2 {
+				rgba.SetRGBA(x, y, transformRed)
+			} else {
+				rgba.SetRGBA(x, y, transformBlue)
+			}
+		}
+	}
+	return NewSprite(0, 0, rgba)
+}
+
+func TestTransform_Draw(t *testing.T) {
+	t.Run("Flip", func(t *testing.T) {
+		s := halvesSprite()
+		s.SetTransform(Transform{FlipX: true, Pivot: floatgeom.Point2{.5, .5}})
+		buff := image.NewRGBA(image.Rect(0, 0, 4, 2))
+		s.Draw(buff, 0, 0)
+		if buff.RGBAAt(0, 0) != transformBlue || buff.RGBAAt(3, 1) != transformRed {
+			t.Fatal("expected sprite flipped in place")
+		}
+	})
+	t.Run("Rotate", func(t *testing.T) {
+		s := halvesSprite()
+		s.SetPos(0, 4)
+		s.SetTransform(Transform{Rotation: 90})
+		buff := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		s.Draw(buff, 0, 0)
+		// rotated counter-clockwise around its top left, the right half of the sprite is on top
+		if buff.RGBAAt(0, 0) != transformBlue || buff.RGBAAt(1, 1) != transformBlue ||
+			buff.RGBAAt(0, 2) != transformRed || buff.RGBAAt(1, 3) != transformRed {
+			t.Fatal("expected sprite rotated counter-clockwise")
+		}
+		if buff.RGBAAt(2, 0) != (color.RGBA{}) {
+			t.Fatal("expected rotated sprite to be two pixels wide")
+		}
+	})
+	t.Run("Scale", func(t *testing.T) {
+		s := halvesSprite()
+		s.SetTransform(Transform{Scale: floatgeom.Point2{2, 2}})
+		buff := image.NewRGBA(image.Rect(0, 0, 10, 10))
+		s.Draw(buff, 1, 1)
+		if buff.RGBAAt(1, 1) != transformRed || buff.RGBAAt(4, 4) != transformRed ||
+			buff.RGBAAt(5, 1) != transformBlue || buff.RGBAAt(8, 4) != transformBlue {
+			t.Fatal("expected sprite scaled by two")
+		}
+		if buff.RGBAAt(9, 4) != (color.RGBA{}) || buff.RGBAAt(8, 5) != (color.RGBA{}) {
+			t.Fatal("expected scaled sprite to be 8x4")
+		}
+	})
+	t.Run("Bilinear", func(t *testing.T) {
+		s := halvesSprite()
+		s.SetTransform(Transform{Scale: floatgeom.Point2{2, 1}, Sampling: Bilinear})
+		buff := image.NewRGBA(image.Rect(0, 0, 8, 2))
+		s.Draw(buff, 0, 0)
+		c := buff.RGBAAt(4, 0)
+		if c.R == 0 || c.B == 0 {
+			t.Fatalf("expected red and blue blended at the seam, got %v", c)
+		}
+	})
+	t.Run("Sequence", func(t *testing.T) {
+		sq := NewSequence(1, halvesSprite(), halvesSprite())
+		sq.SetTransform(Transform{FlipX: true, Pivot: floatgeom.Point2{.5, 0}})
+		buff := image.NewRGBA(image.Rect(0, 0, 4, 2))
+		sq.Draw(buff, 0, 0)
+		if buff.RGBAAt(0, 0) != transformBlue {
+			t.Fatal("expected sequence frame flipped")
+		}
+	})
+	t.Run("Switch", func(t *testing.T) {
+		sw := NewSwitch("a", map[string]Modifiable{"a": halvesSprite()})
+		sw.SetTransform(Transform{FlipX: true, Pivot: floatgeom.Point2{.5, 0}})
+		buff := image.NewRGBA(image.Rect(0, 0, 4, 2))
+		sw.Draw(buff, 0, 0)
+		if buff.RGBAAt(0, 0) != transformBlue {
+			t.Fatal("expected switch renderable flipped")
+		}
+	})
+}
+
+func TestTransform_NoAllocs(t *testing.T) {
+	s := halvesSprite()
+	buff := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	rotation := 0.0
+	allocs := testing.AllocsPerRun(100, func() {
+		rotation += 7
+		s.SetTransform(Transform{Rotation: rotation, Pivot: floatgeom.Point2{.5, .5}, Sampling: Bilinear})
+		s.Draw(buff, 8, 8)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected transformed draws not to allocate, got %v allocations", allocs)
+	}
+}
+
+func TestTransform_Culling(t *testing.T) {
+	s := halvesSprite()
+	s.SetPos(0, 10)
+	view := intgeom.Point2{10, 10}
+	if inView(s, view, 10, 10) {
+		t.Fatal("expected untransformed sprite to be out of view")
+	}
+	s.SetTransform(Transform{Scale: floatgeom.Point2{3, 3}})
+	if !inView(s, view, 10, 10) {
+		t.Fatal("expected scaled sprite to be in view")
+	}
+
+	heap := NewDynamicHeap()
+	heap.Add(s)
+	heap.PreDraw()
+	buff := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	heap.DrawToScreen(buff, &view, 10, 10)
+	if buff.RGBAAt(1, 0) != transformBlue {
+		t.Fatal("expected scaled sprite drawn by heap")
+	}
+}
+
+func TestLayeredPoint_TransformCopy(t *testing.T) {
+	ldp := NewLayeredPoint(0, 0, 0)
+	if _, ok := ldp.GetTransform(); ok {
+		t.Fatal("expected no transform by default")
+	}
+	ldp.SetTransform(Transform{Rotation: 45})
+	cp := ldp.Copy()
+	ldp.SetTransform(Transform{Rotation: 90})
+	if tr, ok := cp.GetTransform(); !ok || tr.Rotation != 45 {
+		t.Fatal("expected copied transform to be independent")
+	}
+	ldp.ClearTransform()
+	if _, ok := ldp.GetTransform(); ok {
+		t.Fatal("expected transform to be cleared")
+	}
+}
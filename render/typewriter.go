@@ -0,0 +1,402 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/timing"
+)
+
+var (
+	// TypewriterCharacter is triggered when a typewriter reveals a character.
+	TypewriterCharacter = event.RegisterNamedEvent[TypedCharacter]("render.TypewriterCharacter")
+	// TypewriterDone is triggered when a typewriter has revealed all of its characters.
+	TypewriterDone = event.RegisterNamedEvent[struct{}]("render.TypewriterDone")
+)
+
+// TypedCharacter is the payload of TypewriterCharacter.
+type TypedCharacter struct {
+	// Index is the index of the character in the typewriter's text, counting newlines and icons.
+	Index int
+	// Rune is the character revealed, or 0 for icons.
+	Rune rune
+}
+
+// TypewriterOptions are the optional settings of a Typewriter.
+type TypewriterOptions struct {
+	RichTextOptions
+	// Rate is how many characters are revealed each second. It defaults to 30.
+	Rate float64
+	// WaveHeight is how many pixels waving characters move up and down. It defaults to 2.
+	WaveHeight float64
+	// ShakeDistance is how many pixels shaking characters move. It defaults to 1.
+	ShakeDistance float64
+	// CycleColors colors cycling characters, from 0 to 1 over each cycle. It defaults to the
+	// colors of the rainbow.
+	CycleColors Colorer
+	// EffectPeriod is how long each wave and color cycle lasts. It defaults to one second.
+	EffectPeriod time.Duration
+}
+
+// A Typewriter is a RichText which reveals its characters over time, like the dialogue of an RPG.
+// In addition to RichText's tags, its markup controls how it is revealed and animated:
+//
+//	[pause=500ms]              waits before revealing the next character
+//	[speed=2]...[/speed]       scales how quickly characters are revealed
+//	[wave]...[/wave]           bobs characters up and down
+//	[shake]...[/shake]         jitters characters randomly
+//	[cycle]...[/cycle]         cycles the color of characters
+//
+// TypewriterCharacter is triggered on a typewriter's trigger ID as each character is revealed, and
+// TypewriterDone once every character has been revealed. Both are triggered on the handler set by
+// SetTriggerHandler, and their bindings may call back into the typewriter, e.g. calling SetMarkup to
+// show the next line of dialogue once done.
+type Typewriter struct {
+	*RichText
+	pauseBool
+	event.CallerID
+	triggerHandler
+
+	mu         sync.Mutex
+	opts       TypewriterOptions
+	clock      *timing.Clock
+	lastUpdate time.Duration
+	// budget is time which has passed but not yet been spent revealing characters
+	budget   time.Duration
+	revealed int
+	done     bool
+	chars    []typedChar
+	// endPause is how long after revealing every character this typewriter is done
+	endPause time.Duration
+
+	// spans and pauses are collected from tags while parsing markup
+	spans  []typedSpan
+	pauses map[int]time.Duration
+
+	// typed and finished are events waiting to be triggered once mu is unlocked, so bindings may
+	// call back into this typewriter
+	typed    []TypedCharacter
+	finished bool
+}
+
+type typeEffect uint8
+
+const (
+	effectWave typeEffect = 1 << iota
+	effectShake
+	effectCycle
+)
+
+var typeEffects = map[string]typeEffect{
+	"wave":  effectWave,
+	"shake": effectShake,
+	"cycle": effectCycle,
+}
+
+// A typedChar is how a typewriter reveals and draws one of its glyphs.
+type typedChar struct {
+	r       rune
+	pause   time.Duration
+	speed   float64
+	effects typeEffect
+}
+
+// A typedSpan is a range of glyphs within a paired typewriter tag.
+type typedSpan struct {
+	name       string
+	speed      float64
+	start, end int
+}
+
+// NewTypewriter creates a typewriter from markup, which starts revealing its characters
+// immediately. If the markup is invalid, an error is returned.
+func (f *Font) NewTypewriter(markup string, x, y float64, opts TypewriterOptions) (*Typewriter, error) {
+	if opts.Rate <= 0 {
+		opts.Rate = 30
+	}
+	if opts.WaveHeight == 0 {
+		opts.WaveHeight = 2
+	}
+	if opts.ShakeDistance == 0 {
+		opts.ShakeDistance = 1
+	}
+	if opts.CycleColors == nil {
+		opts.CycleColors = rainbow
+	}
+	if opts.EffectPeriod <= 0 {
+		opts.EffectPeriod = time.Second
+	}
+	tw := &Typewriter{
+		pauseBool: pauseBool{
+			playing: true,
+		},
+		opts:  opts,
+		clock: timing.DefaultClock,
+	}
+	tw.RichText = f.newRichText(x, y, opts.RichTextOptions)
+	tw.RichText.tags = tw.handleTag
+	if err := tw.SetMarkup(markup); err != nil {
+		return nil, err
+	}
+	return tw, nil
+}
+
+// SetMarkup replaces this typewriter's content, and starts revealing it from the beginning. If the
+// markup is invalid, an error is returned and the content is unchanged.
+func (tw *Typewriter) SetMarkup(markup string) error {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.spans = tw.spans[:0]
+	tw.pauses = make(map[int]time.Duration)
+	if err := tw.RichText.SetMarkup(markup); err != nil {
+		return err
+	}
+	tw.RichText.mu.Lock()
+	chars := make([]typedChar, len(tw.RichText.parsed))
+	for i, g := range tw.RichText.parsed {
+		chars[i] = typedChar{
+			pause: tw.pauses[i],
+			speed: 1,
+		}
+		if g.icon == nil {
+			chars[i].r = g.r
+		}
+	}
+	tw.RichText.mu.Unlock()
+	// spans are in the order they were opened, so nested spans take precedence
+	for _, sp := range tw.spans {
+		end := sp.end
+		if end == -1 {
+			end = len(chars)
+		}
+		for i := sp.start; i < end; i++ {
+			if sp.name == "speed" {
+				chars[i].speed = sp.speed
+			} else {
+				chars[i].effects |= typeEffects[sp.name]
+			}
+		}
+	}
+	tw.chars = chars
+	tw.endPause = tw.pauses[len(chars)]
+	tw.restart()
+	return nil
+}
+
+// handleTag parses a typewriter tag, as a richTagHandler.
+func (tw *Typewriter) handleTag(name, value string, glyph int, closing bool) (bool, error) {
+	if closing {
+		for i := len(tw.spans) - 1; i >= 0; i-- {
+			if tw.spans[i].name == name && tw.spans[i].end == -1 {
+				tw.spans[i].end = glyph
+				break
+			}
+		}
+		return false, nil
+	}
+	switch name {
+	case "pause":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return false, oakerr.InvalidInput{InputName: "markup: [pause=" + value + "]"}
+		}
+		tw.pauses[glyph] += d
+		return false, nil
+	case "speed":
+		speed, err := strconv.ParseFloat(value, 64)
+		if err != nil || speed <= 0 {
+			return false, oakerr.InvalidInput{InputName: "markup: [speed=" + value + "]"}
+		}
+		tw.spans = append(tw.spans, typedSpan{name: name, speed: speed, start: glyph, end: -1})
+		return true, nil
+	case "wave", "shake", "cycle":
+		tw.spans = append(tw.spans, typedSpan{name: name, start: glyph, end: -1})
+		return true, nil
+	}
+	return false, oakerr.InvalidInput{InputName: "markup: unknown tag [" + name + "]"}
+}
+
+// SetClock sets the clock this typewriter measures time by. Typewriters use timing.DefaultClock
+// unless told otherwise, e.g. by being drawn via a scene's context.
+func (tw *Typewriter) SetClock(c *timing.Clock) {
+	tw.mu.Lock()
+	tw.lastUpdate = c.Now() - tw.clock.Since(tw.lastUpdate)
+	tw.clock = c
+	tw.mu.Unlock()
+}
+
+// SetRate sets how many characters are revealed each second. Rates of zero or less are ignored.
+func (tw *Typewriter) SetRate(rate float64) {
+	if rate <= 0 {
+		return
+	}
+	tw.mu.Lock()
+	tw.opts.Rate = rate
+	tw.mu.Unlock()
+}
+
+// SetTriggerID sets the ID TypewriterCharacter and TypewriterDone are triggered on.
+func (tw *Typewriter) SetTriggerID(id event.CallerID) {
+	tw.CallerID = id
+}
+
+// Skip reveals every remaining character at once. TypewriterCharacter is not triggered for the
+// skipped characters, but TypewriterDone is triggered if this typewriter was not already done.
+func (tw *Typewriter) Skip() {
+	tw.mu.Lock()
+	tw.revealed = len(tw.chars)
+	tw.finish()
+	tw.unlockAndTrigger()
+}
+
+// Restart hides every character, and starts revealing them again.
+func (tw *Typewriter) Restart() {
+	tw.mu.Lock()
+	tw.restart()
+	tw.mu.Unlock()
+}
+
+func (tw *Typewriter) restart() {
+	tw.revealed = 0
+	tw.budget = 0
+	tw.done = false
+	tw.lastUpdate = tw.clock.Now()
+}
+
+// Done reports whether every character has been revealed.
+func (tw *Typewriter) Done() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.done
+}
+
+// Revealed returns how many characters have been revealed, counting newlines and icons.
+func (tw *Typewriter) Revealed() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.revealed
+}
+
+func (tw *Typewriter) finish() {
+	if tw.done {
+		return
+	}
+	tw.done = true
+	tw.finished = true
+}
+
+// unlockAndTrigger unlocks mu, then triggers the events collected while it was locked.
+func (tw *Typewriter) unlockAndTrigger() {
+	typed, finished := tw.typed, tw.finished
+	tw.typed, tw.finished = nil, false
+	h, cid := tw.triggerOn(), tw.CallerID
+	tw.mu.Unlock()
+	if cid == 0 {
+		return
+	}
+	for _, tc := range typed {
+		event.TriggerForCallerOn(h, cid, TypewriterCharacter, tc)
+	}
+	if finished {
+		event.TriggerForCallerOn(h, cid, TypewriterDone, struct{}{})
+	}
+}
+
+func (tw *Typewriter) update() {
+	now := tw.clock.Now()
+	elapsed := now - tw.lastUpdate
+	tw.lastUpdate = now
+	if !tw.playing || tw.done {
+		return
+	}
+	tw.budget += elapsed
+	for tw.revealed < len(tw.chars) {
+		c := tw.chars[tw.revealed]
+		cost := c.pause + time.Duration(float64(time.Second)/(tw.opts.Rate*c.speed))
+		if tw.budget < cost {
+			return
+		}
+		tw.budget -= cost
+		tw.typed = append(tw.typed, TypedCharacter{
+			Index: tw.revealed,
+			Rune:  c.r,
+		})
+		tw.revealed++
+	}
+	if tw.budget >= tw.endPause {
+		tw.finish()
+	}
+}
+
+// Draw draws the revealed characters of this typewriter at +xOff, +yOff
+func (tw *Typewriter) Draw(buff draw.Image, xOff, yOff float64) {
+	tw.mu.Lock()
+	tw.update()
+	tw.draw(buff, xOff, yOff)
+	tw.unlockAndTrigger()
+}
+
+// draw draws the revealed characters of this typewriter. It must be called while holding mu.
+func (tw *Typewriter) draw(buff draw.Image, xOff, yOff float64) {
+	rt := tw.RichText
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	origin := fixed.Point26_6{
+		X: fixed.Int26_6((rt.X() + xOff) * 64),
+		Y: fixed.Int26_6((rt.Y() + yOff) * 64),
+	}
+	// cycles is how many wave and color cycles have passed
+	cycles := float64(tw.clock.Now()) / float64(tw.opts.EffectPeriod)
+	for _, g := range rt.glyphs {
+		if g.index >= tw.revealed || g.index >= len(tw.chars) {
+			break
+		}
+		effects := tw.chars[g.index].effects
+		pos := origin
+		var src image.Image
+		// each character is a little further along its cycle than the last, so they move and change
+		// color in a wave
+		phase := cycles + float64(g.index)*.1
+		if effects&effectWave != 0 {
+			pos.Y += fixed.Int26_6(-tw.opts.WaveHeight * math.Sin(2*math.Pi*phase) * 64)
+		}
+		if effects&effectShake != 0 {
+			pos.X += fixed.Int26_6((rand.Float64()*2 - 1) * tw.opts.ShakeDistance * 64)
+			pos.Y += fixed.Int26_6((rand.Float64()*2 - 1) * tw.opts.ShakeDistance * 64)
+		}
+		if effects&effectCycle != 0 {
+			_, frac := math.Modf(phase)
+			src = image.NewUniform(tw.opts.CycleColors(frac))
+		}
+		g.draw(buff, pos, src)
+	}
+}
+
+// rainbow cycles through fully saturated hues.
+func rainbow(progress float64) color.Color {
+	h := progress * 6
+	x := uint8(255 * (1 - math.Abs(math.Mod(h, 2)-1)))
+	switch int(h) % 6 {
+	case 0:
+		return color.RGBA{255, x, 0, 255}
+	case 1:
+		return color.RGBA{x, 255, 0, 255}
+	case 2:
+		return color.RGBA{0, 255, x, 255}
+	case 3:
+		return color.RGBA{0, x, 255, 255}
+	case 4:
+		return color.RGBA{x, 0, 255, 255}
+	}
+	return color.RGBA{255, 0, x, 255}
+}
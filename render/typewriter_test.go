@@ -0,0 +1,245 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+// advanceTypewriter updates tw as if d had passed since it was last updated.
+func advanceTypewriter(tw *Typewriter, d time.Duration) int {
+	tw.mu.Lock()
+	tw.lastUpdate -= d
+	tw.update()
+	revealed := tw.revealed
+	tw.unlockAndTrigger()
+	return revealed
+}
+
+func TestTypewriter_Reveal(t *testing.T) {
+	fnt := DefaultFont()
+	tw, err := fnt.NewTypewriter("abc", 0, 0, TypewriterOptions{Rate: 10})
+	if err != nil {
+		t.Fatalf("failed to create typewriter: %v", err)
+	}
+	for i, want := range []int{1, 2, 3} {
+		if got := advanceTypewriter(tw, 100*time.Millisecond+time.Millisecond); got != want {
+			t.Fatalf("expected %v characters revealed at step %v, got %v", want, i, got)
+		}
+	}
+	if !tw.Done() {
+		t.Fatal("expected typewriter to be done")
+	}
+	tw.Restart()
+	if tw.Revealed() != 0 || tw.Done() {
+		t.Fatal("expected restart to hide characters")
+	}
+	tw.SetRate(0)
+	tw.SetRate(-1)
+	if got := advanceTypewriter(tw, 100*time.Millisecond+time.Millisecond); got != 1 {
+		t.Fatalf("expected rates of zero or less to be ignored, got %v characters revealed", got)
+	}
+
+	t.Run("Pause", func(t *testing.T) {
+		tw, err := fnt.NewTypewriter("a[pause=1s]b[pause=1s]", 0, 0, TypewriterOptions{Rate: 10})
+		if err != nil {
+			t.Fatalf("failed to create typewriter: %v", err)
+		}
+		if advanceTypewriter(tw, 500*time.Millisecond) != 1 {
+			t.Fatal("expected typewriter to pause after the first character")
+		}
+		if advanceTypewriter(tw, time.Second) != 2 {
+			t.Fatal("expected typewriter to continue after pausing")
+		}
+		if tw.Done() {
+			t.Fatal("expected typewriter to pause before finishing")
+		}
+		advanceTypewriter(tw, time.Second)
+		if !tw.Done() {
+			t.Fatal("expected typewriter to finish after pausing")
+		}
+	})
+	t.Run("Speed", func(t *testing.T) {
+		tw, err := fnt.NewTypewriter("[speed=2]ab[/speed]c", 0, 0, TypewriterOptions{Rate: 10})
+		if err != nil {
+			t.Fatalf("failed to create typewriter: %v", err)
+		}
+		if got := advanceTypewriter(tw, 101*time.Millisecond); got != 2 {
+			t.Fatalf("expected faster characters to be revealed twice as fast, got %v", got)
+		}
+		if got := advanceTypewriter(tw, 60*time.Millisecond); got != 2 {
+			t.Fatalf("expected characters after speed to be revealed at the normal rate, got %v", got)
+		}
+	})
+	t.Run("Paused", func(t *testing.T) {
+		tw, err := fnt.NewTypewriter("abc", 0, 0, TypewriterOptions{Rate: 10})
+		if err != nil {
+			t.Fatalf("failed to create typewriter: %v", err)
+		}
+		tw.Pause()
+		if advanceTypewriter(tw, time.Second) != 0 {
+			t.Fatal("expected paused typewriter to reveal nothing")
+		}
+		tw.Unpause()
+		if advanceTypewriter(tw, 101*time.Millisecond) != 1 {
+			t.Fatal("expected unpaused typewriter to continue")
+		}
+	})
+}
+
+func TestTypewriter_Markup(t *testing.T) {
+	fnt := DefaultFont()
+	tw, err := fnt.NewTypewriter("[wave]a[shake]b[/shake][/wave][cycle][color=red]c", 0, 0, TypewriterOptions{})
+	if err != nil {
+		t.Fatalf("failed to create typewriter: %v", err)
+	}
+	expected := []typeEffect{effectWave, effectWave | effectShake, effectCycle}
+	for i, want := range expected {
+		if tw.chars[i].effects != want {
+			t.Fatalf("expected character %v to have effects %v, got %v", i, want, tw.chars[i].effects)
+		}
+	}
+	if tw.String() != "abc" {
+		t.Fatalf("expected text without markup, got %q", tw.String())
+	}
+	for _, markup := range []string{"[pause=x]", "[pause=-1s]", "[speed=0]a", "[bogus]a", "[wave]a[/shake]"} {
+		if _, err := fnt.NewTypewriter(markup, 0, 0, TypewriterOptions{}); err == nil {
+			t.Fatalf("expected %q to be invalid", markup)
+		}
+	}
+	if _, err := fnt.NewRichText("[wave]a", 0, 0, RichTextOptions{}); err == nil {
+		t.Fatal("expected typewriter tags to be invalid in rich text")
+	}
+}
+
+func TestTypewriter_Draw(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	tw, err := DefaultFont().NewTypewriter("[cycle]W", 0, 0, TypewriterOptions{
+		Rate: 1,
+		CycleColors: func(float64) color.Color {
+			return red
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create typewriter: %v", err)
+	}
+	drawn := func() (visible, cycled bool) {
+		buff := image.NewRGBA(image.Rect(0, 0, 20, 20))
+		tw.Draw(buff, 0, 0)
+		for i := 0; i < len(buff.Pix); i += 4 {
+			if buff.Pix[i+3] != 0 {
+				visible = true
+				if buff.Pix[i+1] == 0 && buff.Pix[i+2] == 0 {
+					cycled = true
+				}
+			}
+		}
+		return visible, cycled
+	}
+	if visible, _ := drawn(); visible {
+		t.Fatal("expected unrevealed characters not to be drawn")
+	}
+	tw.Skip()
+	if visible, cycled := drawn(); !visible || !cycled {
+		t.Fatal("expected revealed character to be drawn in the cycle's color")
+	}
+}
+
+func TestTypewriter_Events(t *testing.T) {
+	tw, err := DefaultFont().NewTypewriter("ab", 0, 0, TypewriterOptions{Rate: 10})
+	if err != nil {
+		t.Fatalf("failed to create typewriter: %v", err)
+	}
+	bus := event.NewBus(event.NewCallerMap())
+	d := Dummy{}
+	d.CallerID = bus.GetCallerMap().Register(d)
+	tw.SetTriggerID(d.CallerID)
+	tw.SetTriggerHandler(bus)
+	typed := make(chan TypedCharacter, 10)
+	b := event.Bind(bus, TypewriterCharacter, d, func(_ Dummy, tc TypedCharacter) event.Response {
+		typed <- tc
+		return 0
+	})
+	<-b.Bound
+	done := make(chan struct{}, 10)
+	b = event.Bind(bus, TypewriterDone, d, func(_ Dummy, _ struct{}) event.Response {
+		done <- struct{}{}
+		return 0
+	})
+	<-b.Bound
+
+	advanceTypewriter(tw, 101*time.Millisecond)
+	select {
+	case tc := <-typed:
+		if tc != (TypedCharacter{Index: 0, Rune: 'a'}) {
+			t.Fatalf("expected first character, got %v", tc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected character event")
+	}
+	tw.Skip()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected done event on skip")
+	}
+	tw.Skip()
+	select {
+	case <-done:
+		t.Fatal("expected done to only trigger once")
+	case <-typed:
+		t.Fatal("expected skipped characters not to trigger")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTypewriter_SyncEvents(t *testing.T) {
+	tw, err := DefaultFont().NewTypewriter("ab", 0, 0, TypewriterOptions{Rate: 10})
+	if err != nil {
+		t.Fatalf("failed to create typewriter: %v", err)
+	}
+	bus := event.NewSyncBus(event.NewCallerMap(), time.Millisecond)
+	d := Dummy{}
+	d.CallerID = bus.GetCallerMap().Register(d)
+	tw.SetTriggerID(d.CallerID)
+	tw.SetTriggerHandler(bus)
+	lines := []string{"cd", "ef"}
+	event.Bind(bus, TypewriterCharacter, d, func(_ Dummy, tc TypedCharacter) event.Response {
+		// bindings may query the typewriter which triggered them
+		if tw.Revealed() <= tc.Index {
+			t.Errorf("expected character %v to be revealed, got %v revealed", tc.Index, tw.Revealed())
+		}
+		return 0
+	})
+	event.Bind(bus, TypewriterDone, d, func(_ Dummy, _ struct{}) event.Response {
+		if !tw.Done() {
+			t.Error("expected typewriter to be done")
+		}
+		if len(lines) != 0 {
+			if err := tw.SetMarkup(lines[0]); err != nil {
+				t.Errorf("failed to set markup: %v", err)
+			}
+			lines = lines[1:]
+		}
+		return 0
+	})
+
+	finished := make(chan struct{})
+	go func() {
+		advanceTypewriter(tw, time.Second)
+		tw.Skip()
+		tw.Draw(image.NewRGBA(image.Rect(0, 0, 1, 1)), 0, 0)
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("typewriter deadlocked triggering a binding which called back into it")
+	}
+	if tw.String() != "ef" || len(lines) != 0 {
+		t.Fatalf("expected done bindings to show each following line, got %q", tw.String())
+	}
+}
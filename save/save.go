@@ -0,0 +1,303 @@
+// Package save persists game state to snapshots which can later be restored into a scene.
+//
+// Callers opt in to being saved by implementing Saver, or by having a Saver attached to them
+// via Attach, for callers like entities.Entity which are registered on another type's behalf.
+// Each kind of saved state has a loader, registered via RegisterKind, which recreates a caller
+// from its state.
+package save
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/fileutil"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+// FormatVersion is the version of the snapshot format written by this package. Snapshots
+// with a newer version cannot be read.
+const FormatVersion = 1
+
+// binaryMagic prefixes snapshots written in the Binary format.
+const binaryMagic = "oaksave\x00"
+
+// A Saver is something whose state can be saved into a snapshot.
+type Saver interface {
+	// SaveState returns the kind of this caller, which identifies the loader that will recreate
+	// it, and the state to save. The state will be encoded with the snapshot's format.
+	SaveState() (kind string, state interface{}, err error)
+}
+
+// A Format is an encoding snapshots can be written in.
+type Format uint8
+
+// Formats
+const (
+	// JSON writes snapshots as human readable JSON. State is encoded with encoding/json.
+	JSON Format = iota
+	// Binary writes snapshots compactly. State is encoded with encoding/gob.
+	Binary
+)
+
+func (f Format) marshal(v interface{}) ([]byte, error) {
+	switch f {
+	case JSON:
+		return json.Marshal(v)
+	case Binary:
+		buf := new(bytes.Buffer)
+		err := gob.NewEncoder(buf).Encode(v)
+		return buf.Bytes(), err
+	}
+	return nil, oakerr.UnsupportedFormat{Format: fmt.Sprint(f)}
+}
+
+func (f Format) unmarshal(data []byte, v interface{}) error {
+	switch f {
+	case JSON:
+		return json.Unmarshal(data, v)
+	case Binary:
+		return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+	}
+	return oakerr.UnsupportedFormat{Format: fmt.Sprint(f)}
+}
+
+// A Snapshot is the saved state of a scene.
+type Snapshot struct {
+	// Version is the FormatVersion the snapshot was written with.
+	Version int
+	// DataVersion is free for games to set, to migrate their own state between releases.
+	DataVersion int
+	// Scene is free for games to set, e.g. to the name of the scene to restore the snapshot in.
+	Scene    string
+	Entities []Entity
+	// Input is the scene's input, encoded in the snapshot's format, or nil if the scene had none.
+	Input []byte
+	// Format is the format entity states and the input are encoded in.
+	Format Format
+}
+
+// An Entity is the saved state of a single caller.
+type Entity struct {
+	Kind string
+	Data []byte
+}
+
+// jsonSnapshot mirrors Snapshot, keeping already encoded states as raw JSON.
+type jsonSnapshot struct {
+	Version     int             `json:"version"`
+	DataVersion int             `json:"dataVersion,omitempty"`
+	Scene       string          `json:"scene,omitempty"`
+	Entities    []jsonEntity    `json:"entities"`
+	Input       json.RawMessage `json:"input,omitempty"`
+}
+
+type jsonEntity struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+type loader func(ctx *scene.Context, f Format, data []byte) error
+
+var (
+	kindsLock sync.RWMutex
+	kinds     = map[string]loader{}
+)
+
+// RegisterKind registers how to recreate callers of the given kind from their saved state. If f
+// creates a caller, it should register it with the context's caller map itself. Registering the
+// same kind twice will replace the first registration.
+func RegisterKind[T any](kind string, f func(ctx *scene.Context, state T) error) {
+	kindsLock.Lock()
+	defer kindsLock.Unlock()
+	kinds[kind] = func(ctx *scene.Context, format Format, data []byte) error {
+		var state T
+		if err := format.unmarshal(data, &state); err != nil {
+			return err
+		}
+		return f(ctx, state)
+	}
+}
+
+// Attach marks a caller to be saved by s.
+func Attach(cm *event.CallerMap, cid event.CallerID, s Saver) {
+	event.AddComponent(cm, cid, s)
+}
+
+// Take creates a snapshot of every caller in the context's caller map which is a Saver or has one
+// attached, in the order they were registered, and of the context's scene input, encoding their
+// state in the given format.
+func Take(ctx *scene.Context, f Format) (*Snapshot, error) {
+	snap := &Snapshot{
+		Version: FormatVersion,
+		Format:  f,
+	}
+	if ctx.SceneInput != nil {
+		input, err := f.marshal(ctx.SceneInput)
+		if err != nil {
+			return nil, err
+		}
+		snap.Input = input
+	}
+	for _, cid := range ctx.CallerMap.IDs() {
+		saver, ok := ctx.CallerMap.GetEntity(cid).(Saver)
+		if !ok {
+			attached, ok := event.GetComponent[Saver](ctx.CallerMap, cid)
+			if !ok {
+				continue
+			}
+			saver = *attached
+		}
+		kind, state, err := saver.SaveState()
+		if err != nil {
+			return nil, err
+		}
+		data, err := f.marshal(state)
+		if err != nil {
+			return nil, err
+		}
+		snap.Entities = append(snap.Entities, Entity{
+			Kind: kind,
+			Data: data,
+		})
+	}
+	return snap, nil
+}
+
+// Restore recreates the snapshot's entities into ctx, which should belong to a fresh scene.
+// Entities are recreated in the order they were saved. If the snapshot has an input and ctx's scene
+// input is a pointer, the input is decoded into it first; otherwise, see DecodeInput.
+func (s *Snapshot) Restore(ctx *scene.Context) error {
+	if s.Input != nil && ctx.SceneInput != nil && reflect.ValueOf(ctx.SceneInput).Kind() == reflect.Ptr {
+		if err := s.DecodeInput(ctx.SceneInput); err != nil {
+			return err
+		}
+	}
+	for _, e := range s.Entities {
+		kindsLock.RLock()
+		load, ok := kinds[e.Kind]
+		kindsLock.RUnlock()
+		if !ok {
+			return oakerr.NotFound{InputName: e.Kind}
+		}
+		if err := load(ctx, s.Format, e.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeInput decodes the snapshot's scene input into v, which should be a pointer to a value of
+// the input's type.
+func (s *Snapshot) DecodeInput(v interface{}) error {
+	if s.Input == nil {
+		return oakerr.NotFound{InputName: "input"}
+	}
+	return s.Format.unmarshal(s.Input, v)
+}
+
+// Write writes the snapshot to w in its format.
+func (s *Snapshot) Write(w io.Writer) error {
+	switch s.Format {
+	case JSON:
+		js := jsonSnapshot{
+			Version:     s.Version,
+			DataVersion: s.DataVersion,
+			Scene:       s.Scene,
+			Entities:    make([]jsonEntity, len(s.Entities)),
+			Input:       s.Input,
+		}
+		for i, e := range s.Entities {
+			js.Entities[i] = jsonEntity{
+				Kind: e.Kind,
+				Data: e.Data,
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(js)
+	case Binary:
+		if _, err := io.WriteString(w, binaryMagic); err != nil {
+			return err
+		}
+		return gob.NewEncoder(w).Encode(s)
+	}
+	return oakerr.UnsupportedFormat{Format: fmt.Sprint(s.Format)}
+}
+
+// Read reads a snapshot written by Write, in either format.
+func Read(r io.Reader) (*Snapshot, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(binaryMagic))
+	if err == nil && string(magic) == binaryMagic {
+		if _, err := br.Discard(len(binaryMagic)); err != nil {
+			return nil, err
+		}
+		s := new(Snapshot)
+		if err := gob.NewDecoder(br).Decode(s); err != nil {
+			return nil, err
+		}
+		s.Format = Binary
+		return s, checkVersion(s)
+	}
+	var js jsonSnapshot
+	if err := json.NewDecoder(br).Decode(&js); err != nil {
+		return nil, err
+	}
+	s := &Snapshot{
+		Version:     js.Version,
+		DataVersion: js.DataVersion,
+		Scene:       js.Scene,
+		Entities:    make([]Entity, len(js.Entities)),
+		Input:       js.Input,
+		Format:      JSON,
+	}
+	for i, e := range js.Entities {
+		s.Entities[i] = Entity{
+			Kind: e.Kind,
+			Data: e.Data,
+		}
+	}
+	return s, checkVersion(s)
+}
+
+func checkVersion(s *Snapshot) error {
+	if s.Version > FormatVersion || s.Version < 1 {
+		return oakerr.UnsupportedFormat{Format: fmt.Sprintf("snapshot version %d", s.Version)}
+	}
+	return nil
+}
+
+// Save takes a snapshot of ctx and writes it to file.
+func Save(file string, ctx *scene.Context, f Format) error {
+	snap, err := Take(ctx, f)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	if err := snap.Write(buf); err != nil {
+		return err
+	}
+	return fileutil.WriteFile(file, buf.Bytes())
+}
+
+// Load reads a snapshot from file and restores it into ctx.
+func Load(file string, ctx *scene.Context) (*Snapshot, error) {
+	r, err := fileutil.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	snap, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	return snap, snap.Restore(ctx)
+}
@@ -0,0 +1,153 @@
+package save_test
+
+import (
+	"bytes"
+	"image/color"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/entities"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/save"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+type coin struct {
+	event.CallerID
+	Value int
+}
+
+func (c *coin) SaveState() (string, interface{}, error) {
+	return "coin", c.Value, nil
+}
+
+type player struct {
+	*entities.Entity
+	Name string
+}
+
+type playerState struct {
+	Name   string
+	Entity entities.State
+}
+
+func (p *player) SaveState() (string, interface{}, error) {
+	return "player", playerState{Name: p.Name, Entity: p.Entity.State()}, nil
+}
+
+func newContext() *scene.Context {
+	cm := event.NewCallerMap()
+	return &scene.Context{
+		CallerMap:     cm,
+		Handler:       event.NewBus(cm),
+		DrawStack:     render.NewDrawStack(render.NewDynamicHeap()),
+		CollisionTree: collision.NewTree(),
+		MouseTree:     collision.NewTree(),
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	var coins []int
+	var players []*player
+	save.RegisterKind("coin", func(ctx *scene.Context, value int) error {
+		coins = append(coins, value)
+		return nil
+	})
+	save.RegisterKind("player", func(ctx *scene.Context, st playerState) error {
+		p := &player{
+			Entity: entities.New(ctx, append(st.Entity.Options(), entities.WithColor(color.White))...),
+			Name:   st.Name,
+		}
+		p.SetState(st.Entity)
+		save.Attach(ctx.CallerMap, p.CID(), p)
+		players = append(players, p)
+		return nil
+	})
+
+	ctx := newContext()
+	c := &coin{Value: 5}
+	c.CallerID = ctx.CallerMap.Register(c)
+	p := &player{
+		Entity: entities.New(ctx, entities.WithRect(floatgeom.NewRect2WH(1, 2, 3, 4)), entities.WithLabel(2), entities.WithColor(color.White)),
+		Name:   "hero",
+	}
+	p.Delta = floatgeom.Point2{1, 1}
+	p.SetMetadata("class", "knight")
+	save.Attach(ctx.CallerMap, p.CID(), p)
+	ctx.CallerMap.Register(event.CallerID(0))
+	type level struct {
+		Number int
+	}
+	ctx.SceneInput = level{Number: 4}
+
+	for _, format := range []save.Format{save.JSON, save.Binary} {
+		coins, players = nil, nil
+		snap, err := save.Take(ctx, format)
+		if err != nil {
+			t.Fatalf("take failed: %v", err)
+		}
+		snap.DataVersion = 3
+		buf := new(bytes.Buffer)
+		if err := snap.Write(buf); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		read, err := save.Read(buf)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if read.Format != format || read.DataVersion != 3 || len(read.Entities) != 2 {
+			t.Fatalf("unexpected snapshot read: %+v", read)
+		}
+		restored := newContext()
+		restored.SceneInput = &level{}
+		if err := read.Restore(restored); err != nil {
+			t.Fatalf("restore failed: %v", err)
+		}
+		if *restored.SceneInput.(*level) != (level{Number: 4}) {
+			t.Fatalf("expected restored scene input, got %v", restored.SceneInput)
+		}
+		var input level
+		if err := read.DecodeInput(&input); err != nil || input.Number != 4 {
+			t.Fatalf("expected decoded scene input, got %v: %v", input, err)
+		}
+		if !reflect.DeepEqual(coins, []int{5}) {
+			t.Fatalf("expected restored coin worth 5, got %v", coins)
+		}
+		if len(players) != 1 {
+			t.Fatalf("expected one restored player, got %d", len(players))
+		}
+		if !reflect.DeepEqual(players[0].State(), p.State()) || players[0].Name != "hero" {
+			t.Fatalf("expected restored player %+v, got %+v", p.State(), players[0].State())
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	save.RegisterKind("empty", func(*scene.Context, struct{}) error {
+		return nil
+	})
+	file := filepath.Join(t.TempDir(), "save.json")
+	if err := save.Save(file, newContext(), save.JSON); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	snap, err := save.Load(file, newContext())
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if snap.Version != save.FormatVersion {
+		t.Fatalf("expected version %d, got %d", save.FormatVersion, snap.Version)
+	}
+	if err := snap.DecodeInput(new(int)); err == nil {
+		t.Fatal("expected error decoding a missing scene input")
+	}
+	if _, err := save.Read(bytes.NewBufferString(`{"version": 99}`)); err == nil {
+		t.Fatal("expected error reading a future version")
+	}
+	if err := (&save.Snapshot{Entities: []save.Entity{{Kind: "unknown"}}}).Restore(newContext()); err == nil {
+		t.Fatal("expected error restoring an unknown kind")
+	}
+}
@@ -0,0 +1,161 @@
+package scene
+
+import (
+	"image"
+	"math"
+	"math/rand"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// A Blend draws a transition between the last frame of an ending scene and the live frames of
+// the scene following it. Each frame, it draws into dst a mix of from, the outgoing frame, and to,
+// the incoming frame, where progress moves from 0 (entirely from) to 1 (entirely to). All three
+// images share the same bounds.
+type Blend func(dst, from, to *image.RGBA, progress float64)
+
+// Crossfade blends by fading the outgoing frame into the incoming frame.
+func Crossfade() Blend {
+	return func(dst, from, to *image.RGBA, progress float64) {
+		p := clampProgress(progress)
+		for i := range dst.Pix {
+			dst.Pix[i] = uint8(float64(from.Pix[i])*(1-p) + float64(to.Pix[i])*p)
+		}
+	}
+}
+
+// Wipe blends by sweeping an edge across the screen in the given direction, revealing the
+// incoming frame behind it.
+func Wipe(dir intgeom.Dir2) Blend {
+	return func(dst, from, to *image.RGBA, progress float64) {
+		p := clampProgress(progress)
+		w, h := dst.Bounds().Dx(), dst.Bounds().Dy()
+		edgeX := int(p * float64(w))
+		edgeY := int(p * float64(h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				src := from
+				if revealed(x, w, edgeX, dir.X()) || revealed(y, h, edgeY, dir.Y()) {
+					src = to
+				}
+				copyPixel(dst, src, x, y)
+			}
+		}
+	}
+}
+
+// revealed reports whether a position along one axis has been passed by a wipe edge moving in
+// direction d along that axis.
+func revealed(v, size, edge, d int) bool {
+	switch {
+	case d > 0:
+		return v < edge
+	case d < 0:
+		return v >= size-edge
+	}
+	return false
+}
+
+// Slide blends by pushing the outgoing frame off screen in the given direction, while the incoming
+// frame follows it in from the opposite edge.
+func Slide(dir intgeom.Dir2) Blend {
+	return func(dst, from, to *image.RGBA, progress float64) {
+		p := clampProgress(progress)
+		w, h := dst.Bounds().Dx(), dst.Bounds().Dy()
+		offX := int(p * float64(w) * float64(sign(dir.X())))
+		offY := int(p * float64(h) * float64(sign(dir.Y())))
+		inX := offX - w*sign(dir.X())
+		inY := offY - h*sign(dir.Y())
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				// find which frame, if either, has been moved over this pixel
+				if fx, fy := x-offX, y-offY; fx >= 0 && fx < w && fy >= 0 && fy < h {
+					copyPixelFrom(dst, from, x, y, fx, fy)
+				} else if tx, ty := x-inX, y-inY; tx >= 0 && tx < w && ty >= 0 && ty < h {
+					copyPixelFrom(dst, to, x, y, tx, ty)
+				} else {
+					copyPixel(dst, to, x, y)
+				}
+			}
+		}
+	}
+}
+
+// Iris blends by growing a circle of the incoming frame, centered at some percentage-based
+// point of the screen, until it covers the outgoing frame.
+func Iris(xPerc, yPerc float64) Blend {
+	return func(dst, from, to *image.RGBA, progress float64) {
+		p := clampProgress(progress)
+		w, h := dst.Bounds().Dx(), dst.Bounds().Dy()
+		cx, cy := xPerc*float64(w), yPerc*float64(h)
+		// the radius must eventually reach the farthest corner
+		maxR := 0.0
+		for _, corner := range [][2]float64{{0, 0}, {float64(w), 0}, {0, float64(h)}, {float64(w), float64(h)}} {
+			maxR = math.Max(maxR, math.Hypot(corner[0]-cx, corner[1]-cy))
+		}
+		r := p * maxR
+		r2 := r * r
+		for y := 0; y < h; y++ {
+			dy := float64(y) + .5 - cy
+			for x := 0; x < w; x++ {
+				dx := float64(x) + .5 - cx
+				src := from
+				if dx*dx+dy*dy <= r2 {
+					src = to
+				}
+				copyPixel(dst, src, x, y)
+			}
+		}
+	}
+}
+
+// Dissolve blends by replacing pixels of the outgoing frame with pixels of the incoming frame in a
+// random order, determined by seed.
+func Dissolve(seed int64) Blend {
+	var thresholds []uint16
+	return func(dst, from, to *image.RGBA, progress float64) {
+		w, h := dst.Bounds().Dx(), dst.Bounds().Dy()
+		if len(thresholds) != w*h {
+			rng := rand.New(rand.NewSource(seed))
+			thresholds = make([]uint16, w*h)
+			for i := range thresholds {
+				thresholds[i] = uint16(rng.Intn(math.MaxUint16))
+			}
+		}
+		cutoff := uint16(clampProgress(progress) * math.MaxUint16)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				src := from
+				if thresholds[y*w+x] < cutoff {
+					src = to
+				}
+				copyPixel(dst, src, x, y)
+			}
+		}
+	}
+}
+
+func clampProgress(p float64) float64 {
+	return math.Max(0, math.Min(1, p))
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func copyPixel(dst, src *image.RGBA, x, y int) {
+	copyPixelFrom(dst, src, x, y, x, y)
+}
+
+// copyPixelFrom copies the pixel at (sx, sy) in src to (x, y) in dst, relative to each image's bounds.
+func copyPixelFrom(dst, src *image.RGBA, x, y, sx, sy int) {
+	di := dst.PixOffset(dst.Rect.Min.X+x, dst.Rect.Min.Y+y)
+	si := src.PixOffset(src.Rect.Min.X+sx, src.Rect.Min.Y+sy)
+	copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+}
@@ -0,0 +1,63 @@
+package scene
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+func TestBlends(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+	newImage := func(c color.RGBA) *image.RGBA {
+		rgba := image.NewRGBA(image.Rect(0, 0, 8, 6))
+		for x := 0; x < 8; x++ {
+			for y := 0; y < 6; y++ {
+				rgba.SetRGBA(x, y, c)
+			}
+		}
+		return rgba
+	}
+	blends := map[string]Blend{
+		"Crossfade": Crossfade(),
+		"Wipe":      Wipe(intgeom.Right),
+		"WipeUp":    Wipe(intgeom.Up),
+		"Slide":     Slide(intgeom.Left),
+		"SlideDiag": Slide(intgeom.DownRight),
+		"Iris":      Iris(.5, .5),
+		"Dissolve":  Dissolve(1),
+	}
+	for name, blend := range blends {
+		blend := blend
+		t.Run(name, func(t *testing.T) {
+			from, to := newImage(black), newImage(white)
+			dst := newImage(color.RGBA{})
+			blend(dst, from, to, 0)
+			if dst.RGBAAt(3, 3) != black || dst.RGBAAt(0, 0) != black {
+				t.Fatalf("expected outgoing frame at progress 0, got %v", dst.RGBAAt(3, 3))
+			}
+			blend(dst, from, to, .5)
+			whites := 0
+			for x := 0; x < 8; x++ {
+				for y := 0; y < 6; y++ {
+					if dst.RGBAAt(x, y) == white {
+						whites++
+					}
+				}
+			}
+			if name != "Crossfade" && (whites == 0 || whites == 48) {
+				t.Fatalf("expected a partial blend at progress .5, got %d incoming pixels", whites)
+			}
+			blend(dst, from, to, 1)
+			for x := 0; x < 8; x++ {
+				for y := 0; y < 6; y++ {
+					if dst.RGBAAt(x, y) != white {
+						t.Fatalf("expected incoming frame at progress 1, got %v at %d,%d", dst.RGBAAt(x, y), x, y)
+					}
+				}
+			}
+		})
+	}
+}
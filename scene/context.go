@@ -2,11 +2,13 @@ package scene
 
 import (
 	"context"
+	"sync"
 
 	"github.com/oakmound/oak/v4/collision"
 	"github.com/oakmound/oak/v4/event"
 	"github.com/oakmound/oak/v4/key"
 	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/timing"
 )
 
 // A Context contains all transient engine components used in a scene, including
@@ -28,6 +30,14 @@ type Context struct {
 
 	MouseTree     *collision.Tree
 	CollisionTree *collision.Tree
+
+	// Clock measures game time for this scene. Pausing or scaling it pauses or scales
+	// enter events, the delays of DoAfter and DrawForTime, and the animations of renderables
+	// drawn with Draw. If nil, timing.DefaultClock is used.
+	Clock *timing.Clock
+
+	schedulerOnce sync.Once
+	scheduler     *Scheduler
 }
 
 // DoEachFrame is a helper method to call a function on each frame for the duration of this scene.
@@ -37,3 +47,28 @@ func (ctx *Context) DoEachFrame(f func()) {
 		return 0
 	})
 }
+
+// Draw adds r to this scene's draw stack, as DrawStack.Draw does. If r is render.Clocked, it is
+// set to measure time by this scene's clock, and if r is render.TriggerHandled, it is set to trigger
+// its events on this scene's handler.
+func (ctx *Context) Draw(r render.Renderable, layers ...int) (render.Renderable, error) {
+	if cl, ok := r.(render.Clocked); ok {
+		cl.SetClock(ctx.clock())
+	}
+	if th, ok := r.(render.TriggerHandled); ok && ctx.Handler != nil {
+		th.SetTriggerHandler(ctx.Handler)
+	}
+	return ctx.DrawStack.Draw(r, layers...)
+}
+
+// GetClock returns the clock this scene measures game time by.
+func (ctx *Context) GetClock() *timing.Clock {
+	return ctx.clock()
+}
+
+func (ctx *Context) clock() *timing.Clock {
+	if ctx.Clock == nil {
+		return timing.DefaultClock
+	}
+	return ctx.Clock
+}
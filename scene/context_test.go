@@ -0,0 +1,42 @@
+package scene
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/timing"
+)
+
+func TestContextDraw(t *testing.T) {
+	clock := timing.NewClock()
+	clock.Pause()
+	ctx := &Context{
+		DrawStack: render.NewDrawStack(render.NewDynamicHeap()),
+		Clock:     clock,
+	}
+	sq := render.NewSequence(100, render.NewEmptySprite(0, 0, 1, 1), render.NewEmptySprite(0, 0, 1, 1))
+	sw := render.NewSwitch("a", map[string]render.Modifiable{
+		"a": render.NewSequence(100, render.NewEmptySprite(0, 0, 1, 1), render.NewEmptySprite(0, 0, 1, 1)),
+	})
+	if _, err := ctx.Draw(sq); err != nil {
+		t.Fatalf("draw failed: %v", err)
+	}
+	if _, err := ctx.Draw(sw); err != nil {
+		t.Fatalf("draw failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	buff := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	sq.Draw(buff, 0, 0)
+	sw.Draw(buff, 0, 0)
+	if sq.Frame() != 0 || sw.GetSub("a").(*render.Sequence).Frame() != 0 {
+		t.Fatal("expected sequences drawn while the scene's clock is paused not to advance")
+	}
+	clock.Resume()
+	time.Sleep(15 * time.Millisecond)
+	sq.Draw(buff, 0, 0)
+	if sq.Frame() == 0 {
+		t.Fatal("expected sequence to advance once the scene's clock resumed")
+	}
+}
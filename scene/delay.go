@@ -7,16 +7,12 @@ import (
 	"github.com/oakmound/oak/v4/render"
 )
 
-// DoAfter will execute the given function after some duration. When the scene
-// ends, DoAfter will exit without calling f. This call blocks until one of those
-// conditions is reached.
+// DoAfter will execute the given function after some duration of game time, as measured
+// by the context's clock. When the scene ends, DoAfter will exit without calling f. This
+// call blocks until one of those conditions is reached.
 func (c *Context) DoAfter(d time.Duration, f func()) {
-	t := time.NewTimer(d)
-	defer t.Stop()
-	select {
-	case <-t.C:
+	if c.clock().Wait(c.Done(), d) {
 		f()
-	case <-c.Done():
 	}
 }
 
@@ -31,9 +27,9 @@ func (c *Context) DoAfterContext(ctx context.Context, f func()) {
 	}
 }
 
-// DrawForTime draws, and after d, undraws an element
+// DrawForTime draws, as Draw does, and after d of game time, undraws an element
 func (c *Context) DrawForTime(r render.Renderable, d time.Duration, layers ...int) error {
-	_, err := c.DrawStack.Draw(r, layers...)
+	_, err := c.Draw(r, layers...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,34 @@
+package scene
+
+import "math"
+
+// An Easing maps progress through some change, from 0 to 1, onto how far the changing value
+// should be from its start to its end, also from 0 to 1.
+type Easing func(progress float64) float64
+
+// Easings for use with Tween.
+var (
+	Linear Easing = func(p float64) float64 { return p }
+
+	EaseInQuad    Easing = func(p float64) float64 { return p * p }
+	EaseOutQuad   Easing = func(p float64) float64 { return p * (2 - p) }
+	EaseInOutQuad Easing = func(p float64) float64 {
+		if p < .5 {
+			return 2 * p * p
+		}
+		return -1 + (4-2*p)*p
+	}
+
+	EaseInCubic    Easing = func(p float64) float64 { return p * p * p }
+	EaseOutCubic   Easing = func(p float64) float64 { return 1 - math.Pow(1-p, 3) }
+	EaseInOutCubic Easing = func(p float64) float64 {
+		if p < .5 {
+			return 4 * p * p * p
+		}
+		return 1 - math.Pow(-2*p+2, 3)/2
+	}
+
+	EaseInSine    Easing = func(p float64) float64 { return 1 - math.Cos(p*math.Pi/2) }
+	EaseOutSine   Easing = func(p float64) float64 { return math.Sin(p * math.Pi / 2) }
+	EaseInOutSine Easing = func(p float64) float64 { return -(math.Cos(math.Pi*p) - 1) / 2 }
+)
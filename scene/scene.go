@@ -1,6 +1,8 @@
 package scene
 
 import (
+	"time"
+
 	"github.com/oakmound/oak/v4/dlog"
 	"github.com/oakmound/oak/v4/oakerr"
 )
@@ -15,6 +17,9 @@ type Scene struct {
 	// End is a function returning the next scene and a SceneResult of
 	// input settings for the next scene.
 	End func() (nextScene string, result *Result)
+	// Assets lists image and audio files this scene uses. They will be loaded before the scene
+	// starts, if they were not already preloaded while an earlier scene was running.
+	Assets []string
 }
 
 // A Result is a set of options for what should be passed into the next
@@ -22,6 +27,12 @@ type Scene struct {
 type Result struct {
 	NextSceneInput interface{}
 	Transition
+	// Blend, if set, blends the last frame of the ending scene into the frames the next scene
+	// draws, over BlendDuration. Unlike Transition, it does not delay the next scene's Start.
+	Blend         Blend
+	BlendDuration time.Duration
+	// BlendEasing eases the progress passed to Blend. If nil, Linear is used.
+	BlendEasing Easing
 }
 
 // GoTo returns an End function that, without any other customization possible,
@@ -0,0 +1,375 @@
+package scene
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+// A Scheduler runs functions on future frames, as measured by enter events on an event handler.
+// While a scheduler is paused, frames do not count towards any of its tasks. All tasks are cancelled
+// when the scheduler's context is done.
+//
+// Scheduled functions are called on the goroutine triggering enter events, so they should not block.
+// Scripts are the exception: they may wait on frames, time, or conditions between their steps.
+type Scheduler struct {
+	mutex  sync.Mutex
+	paused bool
+	tasks  []*scheduledTask
+	ctx    context.Context
+}
+
+type scheduledTask struct {
+	*Task
+	// step advances this task by one frame and reports whether it has completed.
+	step func(event.EnterPayload) (done bool)
+}
+
+// A Task is a handle to some scheduled work.
+type Task struct {
+	cancelOnce sync.Once
+	cancel     chan struct{}
+	doneOnce   sync.Once
+	done       chan struct{}
+}
+
+func newTask() *Task {
+	return &Task{
+		cancel: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Cancel stops a task from running further. A cancelled script will exit the next time it waits.
+func (t *Task) Cancel() {
+	t.cancelOnce.Do(func() {
+		close(t.cancel)
+	})
+	t.finish()
+}
+
+// Done returns a channel which will be closed once this task completes or is cancelled.
+func (t *Task) Done() <-chan struct{} {
+	return t.done
+}
+
+func (t *Task) finish() {
+	t.doneOnce.Do(func() {
+		close(t.done)
+	})
+}
+
+func (t *Task) cancelled() bool {
+	select {
+	case <-t.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewScheduler creates a scheduler driven by enter events on h, whose tasks will be cancelled
+// once ctx is done.
+func NewScheduler(ctx context.Context, h event.Handler) *Scheduler {
+	s := &Scheduler{
+		ctx: ctx,
+	}
+	event.GlobalBind(h, event.Enter, s.enter)
+	if ctx != nil {
+		go func() {
+			<-ctx.Done()
+			s.CancelAll()
+		}()
+	}
+	return s
+}
+
+func (s *Scheduler) enter(ep event.EnterPayload) event.Response {
+	if s.ctx != nil && s.ctx.Err() != nil {
+		return event.ResponseUnbindThisBinding
+	}
+	s.mutex.Lock()
+	if s.paused {
+		s.mutex.Unlock()
+		return 0
+	}
+	// tasks scheduled while these tasks run will start on the next frame
+	tasks := make([]*scheduledTask, len(s.tasks))
+	copy(tasks, s.tasks)
+	s.mutex.Unlock()
+
+	for _, t := range tasks {
+		if t.cancelled() {
+			continue
+		}
+		if t.step(ep) {
+			t.finish()
+		}
+	}
+
+	s.mutex.Lock()
+	kept := s.tasks[:0]
+	for _, t := range s.tasks {
+		select {
+		case <-t.done:
+		default:
+			kept = append(kept, t)
+		}
+	}
+	for i := len(kept); i < len(s.tasks); i++ {
+		s.tasks[i] = nil
+	}
+	s.tasks = kept
+	s.mutex.Unlock()
+	return 0
+}
+
+func (s *Scheduler) schedule(step func(event.EnterPayload) bool) *Task {
+	return s.scheduleTask(newTask(), step)
+}
+
+func (s *Scheduler) scheduleTask(task *Task, step func(event.EnterPayload) bool) *Task {
+	t := &scheduledTask{
+		Task: task,
+		step: step,
+	}
+	s.mutex.Lock()
+	s.tasks = append(s.tasks, t)
+	s.mutex.Unlock()
+	return t.Task
+}
+
+// Pause stops frames from counting towards this scheduler's tasks until Resume is called.
+func (s *Scheduler) Pause() {
+	s.mutex.Lock()
+	s.paused = true
+	s.mutex.Unlock()
+}
+
+// Resume undoes a call to Pause.
+func (s *Scheduler) Resume() {
+	s.mutex.Lock()
+	s.paused = false
+	s.mutex.Unlock()
+}
+
+// Paused returns whether this scheduler is paused.
+func (s *Scheduler) Paused() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.paused
+}
+
+// CancelAll cancels all of this scheduler's pending tasks.
+func (s *Scheduler) CancelAll() {
+	s.mutex.Lock()
+	tasks := s.tasks
+	s.tasks = nil
+	s.mutex.Unlock()
+	for _, t := range tasks {
+		t.Cancel()
+	}
+}
+
+// After calls f once the given number of frames have passed. After(1, f) will call f on the next frame.
+func (s *Scheduler) After(frames int, f func()) *Task {
+	remaining := frames
+	return s.schedule(func(event.EnterPayload) bool {
+		remaining--
+		if remaining > 0 {
+			return false
+		}
+		f()
+		return true
+	})
+}
+
+// Every calls f each time the given number of frames have passed, until the returned task is cancelled.
+func (s *Scheduler) Every(frames int, f func()) *Task {
+	if frames < 1 {
+		frames = 1
+	}
+	elapsed := 0
+	return s.schedule(func(event.EnterPayload) bool {
+		elapsed++
+		if elapsed == frames {
+			elapsed = 0
+			f()
+		}
+		return false
+	})
+}
+
+// Tween calls f every frame with the eased progress through d, as measured by the time reported by
+// enter events. The final call to f will be passed easing(1). If easing is nil, Linear will be used.
+func (s *Scheduler) Tween(d time.Duration, easing Easing, f func(float64)) *Task {
+	return s.schedule(tweenStep(d, easing, f))
+}
+
+func tweenStep(d time.Duration, easing Easing, f func(float64)) func(event.EnterPayload) bool {
+	if easing == nil {
+		easing = Linear
+	}
+	var elapsed time.Duration
+	return func(ep event.EnterPayload) bool {
+		elapsed += ep.SinceLastFrame
+		progress := 1.0
+		if d > 0 && elapsed < d {
+			progress = float64(elapsed) / float64(d)
+		}
+		f(easing(progress))
+		return progress >= 1
+	}
+}
+
+// Script runs fn as a coroutine, starting on the next frame. Each time fn waits, it yields until
+// the condition it waited on is met on some later frame. Only one of the scheduler's tasks runs at a
+// time, so scripts may safely share state with other tasks. If the script's task is cancelled, the
+// script will exit the next time it waits.
+func (s *Scheduler) Script(fn func(*Script)) *Task {
+	sc := &Script{
+		task:   newTask(),
+		fn:     fn,
+		resume: make(chan struct{}),
+		yield:  make(chan struct{}),
+	}
+	return s.scheduleTask(sc.task, sc.step)
+}
+
+// A Script is a coroutine run by a Scheduler.
+type Script struct {
+	task *Task
+	fn   func(*Script)
+
+	resume chan struct{}
+	yield  chan struct{}
+
+	started  bool
+	finished bool
+	exiting  bool
+	// ready is checked each frame while the script is waiting
+	ready func(event.EnterPayload) bool
+}
+
+func (sc *Script) step(ep event.EnterPayload) bool {
+	if !sc.started {
+		sc.started = true
+		go sc.run()
+		return sc.awaitYield()
+	}
+	if !sc.ready(ep) {
+		return false
+	}
+	select {
+	case sc.resume <- struct{}{}:
+	case <-sc.task.cancel:
+		// the script has exited, or will the next time it waits
+		return true
+	}
+	return sc.awaitYield()
+}
+
+// awaitYield waits for the script to wait or finish, and returns whether it finished.
+func (sc *Script) awaitYield() bool {
+	select {
+	case <-sc.yield:
+		return sc.finished
+	case <-sc.task.cancel:
+		return true
+	}
+}
+
+func (sc *Script) run() {
+	defer func() {
+		// A script exiting from a cancelled wait has no step waiting on it
+		if !sc.exiting {
+			sc.finished = true
+			sc.yieldOrExit()
+		}
+	}()
+	sc.fn(sc)
+}
+
+// yieldOrExit yields control back to the scheduler, or exits the script if it has been cancelled.
+func (sc *Script) yieldOrExit() {
+	select {
+	case sc.yield <- struct{}{}:
+	case <-sc.task.cancel:
+		sc.exiting = true
+		runtime.Goexit()
+	}
+}
+
+// waitUntil yields control back to the scheduler until ready returns true on some frame.
+func (sc *Script) waitUntil(ready func(event.EnterPayload) bool) {
+	sc.ready = ready
+	sc.yieldOrExit()
+	select {
+	case <-sc.resume:
+	case <-sc.task.cancel:
+		sc.exiting = true
+		runtime.Goexit()
+	}
+}
+
+// Wait waits for the given number of frames to pass.
+func (sc *Script) Wait(frames int) {
+	remaining := frames
+	sc.waitUntil(func(event.EnterPayload) bool {
+		remaining--
+		return remaining <= 0
+	})
+}
+
+// WaitFor waits until d has passed, as measured by the time reported by enter events.
+func (sc *Script) WaitFor(d time.Duration) {
+	var elapsed time.Duration
+	sc.waitUntil(func(ep event.EnterPayload) bool {
+		elapsed += ep.SinceLastFrame
+		return elapsed >= d
+	})
+}
+
+// WaitUntil waits until cond returns true, checking once per frame.
+func (sc *Script) WaitUntil(cond func() bool) {
+	sc.waitUntil(func(event.EnterPayload) bool {
+		return cond()
+	})
+}
+
+// Tween acts like Scheduler.Tween, waiting until the tween completes.
+func (sc *Script) Tween(d time.Duration, easing Easing, f func(float64)) {
+	sc.waitUntil(tweenStep(d, easing, f))
+}
+
+// Scheduler returns a Scheduler driven by this context's enter events, which is created on first use
+// and whose tasks are cancelled when the scene ends.
+func (ctx *Context) Scheduler() *Scheduler {
+	ctx.schedulerOnce.Do(func() {
+		ctx.scheduler = NewScheduler(ctx.Context, ctx.Handler)
+	})
+	return ctx.scheduler
+}
+
+// After calls f after the given number of frames. See Scheduler.After.
+func (ctx *Context) After(frames int, f func()) *Task {
+	return ctx.Scheduler().After(frames, f)
+}
+
+// Every calls f each time the given number of frames have passed. See Scheduler.Every.
+func (ctx *Context) Every(frames int, f func()) *Task {
+	return ctx.Scheduler().Every(frames, f)
+}
+
+// Tween calls f every frame with eased progress through d. See Scheduler.Tween.
+func (ctx *Context) Tween(d time.Duration, easing Easing, f func(float64)) *Task {
+	return ctx.Scheduler().Tween(d, easing, f)
+}
+
+// Script runs fn as a coroutine. See Scheduler.Script.
+func (ctx *Context) Script(fn func(*Script)) *Task {
+	return ctx.Scheduler().Script(fn)
+}
@@ -0,0 +1,191 @@
+package scene
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+func newTestContext() (*Context, *event.SyncBus, context.CancelFunc) {
+	bus := event.NewSyncBus(event.NewCallerMap(), 10*time.Millisecond)
+	baseCtx, cancel := context.WithCancel(context.Background())
+	return &Context{
+		Context: baseCtx,
+		Handler: bus,
+	}, bus, cancel
+}
+
+func TestSchedulerAfter(t *testing.T) {
+	ctx, bus, cancel := newTestContext()
+	defer cancel()
+	calls := 0
+	task := ctx.After(3, func() {
+		calls++
+	})
+	bus.Step()
+	bus.Step()
+	if calls != 0 {
+		t.Fatalf("after called early")
+	}
+	bus.Step()
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %v", calls)
+	}
+	select {
+	case <-task.Done():
+	default:
+		t.Fatal("task was not done")
+	}
+	bus.Step()
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %v", calls)
+	}
+}
+
+func TestSchedulerEvery(t *testing.T) {
+	ctx, bus, cancel := newTestContext()
+	defer cancel()
+	calls := 0
+	task := ctx.Every(2, func() {
+		calls++
+	})
+	for i := 0; i < 6; i++ {
+		bus.Step()
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %v", calls)
+	}
+	task.Cancel()
+	bus.Step()
+	bus.Step()
+	if calls != 3 {
+		t.Fatalf("cancelled task was called")
+	}
+}
+
+func TestSchedulerTween(t *testing.T) {
+	ctx, bus, cancel := newTestContext()
+	defer cancel()
+	got := []float64{}
+	ctx.Tween(40*time.Millisecond, EaseInQuad, func(f float64) {
+		got = append(got, f)
+	})
+	for i := 0; i < 6; i++ {
+		bus.Step()
+	}
+	expected := []float64{.0625, .25, .5625, 1}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestSchedulerPause(t *testing.T) {
+	ctx, bus, cancel := newTestContext()
+	defer cancel()
+	calls := 0
+	ctx.After(2, func() {
+		calls++
+	})
+	bus.Step()
+	ctx.Scheduler().Pause()
+	if !ctx.Scheduler().Paused() {
+		t.Fatal("scheduler was not paused")
+	}
+	bus.Step()
+	bus.Step()
+	if calls != 0 {
+		t.Fatalf("paused scheduler progressed")
+	}
+	ctx.Scheduler().Resume()
+	bus.Step()
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %v", calls)
+	}
+}
+
+func TestSchedulerScript(t *testing.T) {
+	ctx, bus, cancel := newTestContext()
+	defer cancel()
+	steps := []int{}
+	flag := false
+	task := ctx.Script(func(s *Script) {
+		steps = append(steps, bus.FramesElapsed())
+		s.Wait(2)
+		steps = append(steps, bus.FramesElapsed())
+		s.WaitFor(30 * time.Millisecond)
+		steps = append(steps, bus.FramesElapsed())
+		s.WaitUntil(func() bool { return flag })
+		steps = append(steps, bus.FramesElapsed())
+	})
+	for i := 0; i < 8; i++ {
+		bus.Step()
+	}
+	flag = true
+	bus.Step()
+	expected := []int{1, 3, 6, 9}
+	if !reflect.DeepEqual(steps, expected) {
+		t.Fatalf("expected %v, got %v", expected, steps)
+	}
+	select {
+	case <-task.Done():
+	default:
+		t.Fatal("script was not done")
+	}
+}
+
+func TestSchedulerSceneEnd(t *testing.T) {
+	ctx, bus, cancel := newTestContext()
+	exited := make(chan struct{})
+	reached := false
+	task := ctx.Script(func(s *Script) {
+		defer close(exited)
+		s.Wait(5)
+		reached = true
+	})
+	bus.Step()
+	cancel()
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("script did not exit after context end")
+	}
+	select {
+	case <-task.Done():
+	default:
+		t.Fatal("cancelled task was not done")
+	}
+	for i := 0; i < 6; i++ {
+		bus.Step()
+	}
+	if reached {
+		t.Fatal("script continued after cancellation")
+	}
+}
+
+func TestSchedulerScriptCancelledWhileResuming(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		ctx, bus, cancel := newTestContext()
+		task := ctx.Script(func(s *Script) {
+			s.WaitUntil(func() bool {
+				ctx.Scheduler().CancelAll()
+				return true
+			})
+		})
+		stepped := make(chan struct{})
+		go func() {
+			bus.Step()
+			bus.Step()
+			close(stepped)
+		}()
+		select {
+		case <-stepped:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler blocked on a cancelled script")
+		}
+		<-task.Done()
+		cancel()
+	}
+}
@@ -0,0 +1,96 @@
+package oak
+
+import (
+	"image"
+	"time"
+
+	"github.com/oakmound/oak/v4/scene"
+	"github.com/oakmound/oak/v4/timing"
+)
+
+// an activeBlend is a scene.Blend in progress between two scenes.
+type activeBlend struct {
+	blend    scene.Blend
+	easing   scene.Easing
+	duration time.Duration
+	from     *image.RGBA
+	// to holds a copy of the incoming frame, as the frame itself is drawn over
+	to *image.RGBA
+	// clock is the incoming scene's clock, so pausing or scaling it pauses or scales the blend
+	clock *timing.Clock
+	// start is set when the incoming scene draws its first frame
+	start   time.Duration
+	started bool
+}
+
+// snapshotFrame copies the most recently drawn frame, for blending into the next scene. It must be
+// called from the draw loop.
+func (w *Window) snapshotFrame() {
+	buff := w.winBuffers[w.bufferIdx].RGBA()
+	if buff == nil {
+		return
+	}
+	snap := image.NewRGBA(buff.Bounds())
+	copy(snap.Pix, buff.Pix)
+	w.lastFrame = snap
+}
+
+// startBlend prepares a blend from the last frame of the ending scene, if the scene's result asks for one.
+func (w *Window) startBlend(result *scene.Result) {
+	from := w.lastFrame
+	w.lastFrame = nil
+	if result.Blend == nil || from == nil {
+		return
+	}
+	easing := result.BlendEasing
+	if easing == nil {
+		easing = scene.Linear
+	}
+	w.blendMutex.Lock()
+	w.blend = &activeBlend{
+		blend:    result.Blend,
+		easing:   easing,
+		duration: result.BlendDuration,
+		from:     from,
+		clock:    w.clock(),
+	}
+	w.blendMutex.Unlock()
+}
+
+// blendingFrom returns the outgoing frame of a pending blend, if there is one.
+func (w *Window) blendingFrom() *image.RGBA {
+	w.blendMutex.Lock()
+	defer w.blendMutex.Unlock()
+	if w.blend == nil {
+		return nil
+	}
+	return w.blend.from
+}
+
+// applyBlend blends the outgoing scene's last frame into buff, a frame drawn by the incoming scene.
+func (w *Window) applyBlend(buff *image.RGBA) {
+	w.blendMutex.Lock()
+	defer w.blendMutex.Unlock()
+	b := w.blend
+	if b == nil {
+		return
+	}
+	if !b.started {
+		b.start = b.clock.Now()
+		b.started = true
+	}
+	progress := 1.0
+	if b.duration > 0 {
+		progress = float64(b.clock.Since(b.start)) / float64(b.duration)
+	}
+	// a window resized mid blend cannot be blended
+	if progress >= 1 || b.from.Bounds() != buff.Bounds() {
+		w.blend = nil
+		return
+	}
+	if b.to == nil {
+		b.to = image.NewRGBA(buff.Bounds())
+	}
+	copy(b.to.Pix, buff.Pix)
+	b.blend(buff, b.from, b.to, b.easing(progress))
+}
@@ -0,0 +1,36 @@
+package oak
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/scene"
+)
+
+func TestApplyBlend_PausedClock(t *testing.T) {
+	w := NewWindow()
+	w.lastFrame = image.NewRGBA(image.Rect(0, 0, 4, 4))
+	var progresses []float64
+	w.startBlend(&scene.Result{
+		Blend: func(dst, from, to *image.RGBA, progress float64) {
+			progresses = append(progresses, progress)
+		},
+		BlendDuration: 10 * time.Millisecond,
+	})
+	w.Clock.Pause()
+	buff := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	w.applyBlend(buff)
+	time.Sleep(20 * time.Millisecond)
+	w.applyBlend(buff)
+	if len(progresses) != 2 || progresses[1] != progresses[0] {
+		t.Fatalf("expected blend to not progress while the clock is paused, got %v", progresses)
+	}
+
+	w.Clock.Resume()
+	time.Sleep(20 * time.Millisecond)
+	w.applyBlend(buff)
+	if len(progresses) != 2 || w.blendingFrom() != nil {
+		t.Fatal("expected blend to finish once the clock resumed")
+	}
+}
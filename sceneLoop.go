@@ -29,8 +29,12 @@ func (w *Window) sceneLoop(first string, trackingInputs bool) {
 	w.SceneMap.CurrentScene = oakLoadingScene
 
 	for {
+		w.SetCamera(nil)
+		w.SetSplitViews()
 		w.SetViewport(intgeom.Point2{0, 0})
 		w.RemoveViewportBounds()
+		w.clock().Resume()
+		w.clock().SetScale(1)
 
 		dlog.Info(dlog.SceneStarting, w.SceneMap.CurrentScene)
 		scen, ok := w.SceneMap.GetCurrent()
@@ -52,16 +56,23 @@ func (w *Window) sceneLoop(first string, trackingInputs bool) {
 			w.trackInputChanges()
 		}
 		gctx, cancel := context.WithCancel(w.ParentContext)
+		sceneName := w.SceneMap.CurrentScene
+		w.layerMutex.RLock()
+		handler, callerMap, mouseTree, collisionTree := w.eventHandler, w.CallerMap, w.MouseTree, w.CollisionTree
+		w.layerMutex.RUnlock()
 		go func() {
+			// the loading renderable is displayed while assets load
+			w.awaitPreload(sceneName, scen)
 			scen.Start(&scene.Context{
 				Context:       gctx,
 				PreviousScene: prevScene,
 				SceneInput:    result.NextSceneInput,
 				DrawStack:     w.DrawStack,
-				Handler:       w.eventHandler,
-				CallerMap:     w.CallerMap,
-				MouseTree:     w.MouseTree,
-				CollisionTree: w.CollisionTree,
+				Handler:       handler,
+				CallerMap:     callerMap,
+				MouseTree:     mouseTree,
+				CollisionTree: collisionTree,
+				Clock:         w.clock(),
 				Window:        w,
 				State:         &w.State,
 			})
@@ -77,7 +88,11 @@ func (w *Window) sceneLoop(first string, trackingInputs bool) {
 
 		dlog.Info(dlog.SceneLooping)
 
-		enterCancel := event.EnterLoop(w.eventHandler, timing.FPSToFrameDelay(w.FrameRate))
+		// Steppers have their enter events triggered by the user, not on a timer
+		enterCancel := func() {}
+		if _, ok := handler.(event.Stepper); !ok {
+			enterCancel = event.ClockEnterLoop(handler, timing.FPSToFrameDelay(w.FrameRate), w.clock())
+		}
 		nextSceneOverride := ""
 
 		select {
@@ -92,26 +107,30 @@ func (w *Window) sceneLoop(first string, trackingInputs bool) {
 		}
 		cancel()
 		dlog.Info(dlog.SceneEnding, w.SceneMap.CurrentScene)
+		// Scenes pushed on top of this scene end with it, restoring this scene's handlers
+		w.popAllScenes()
 
 		// We don't want enterFrames going off between scenes
 		enterCancel()
 		prevScene = w.SceneMap.CurrentScene
 
+		// Keep the last frame of this scene, in case the next scene blends from it
+		w.DoBetweenDraws(w.snapshotFrame)
 		// Send a signal to stop drawing
 		w.drawCh <- struct{}{}
 
 		// Reset transient portions of the engine
 		// We start by clearing the event bus to
 		// remove most ongoing code
-		w.eventHandler.Reset()
+		handler.Reset()
 		// We follow by clearing collision areas
 		// because otherwise collision function calls
 		// on non-entities (i.e. particles) can still
 		// be triggered and attempt to access an entity
-		w.CollisionTree.Clear()
-		w.MouseTree.Clear()
-		w.CallerMap.Clear()
-		w.eventHandler.SetCallerMap(w.CallerMap)
+		collisionTree.Clear()
+		mouseTree.Clear()
+		callerMap.Clear()
+		handler.SetCallerMap(callerMap)
 		w.DrawStack.Clear()
 		w.DrawStack.PreDraw()
 
@@ -127,5 +146,6 @@ func (w *Window) sceneLoop(first string, trackingInputs bool) {
 		if result == nil {
 			result = new(scene.Result)
 		}
+		w.startBlend(result)
 	}
 }
@@ -0,0 +1,200 @@
+package oak
+
+import (
+	"context"
+	"image"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/dlog"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+	"github.com/oakmound/oak/v4/timing"
+	"github.com/oakmound/oak/v4/window"
+)
+
+// a pushedScene is a scene running on top of the window's current scene.
+type pushedScene struct {
+	name        string
+	end         func() (string, *scene.Result)
+	cancel      context.CancelFunc
+	enterCancel func()
+	handler     event.Handler
+	drawStack   *render.DrawStack
+	clock       *timing.Clock
+
+	// the state of the layer beneath this scene, restored when this scene is popped
+	below struct {
+		handler       event.Handler
+		callerMap     *event.CallerMap
+		mouseTree     *collision.Tree
+		collisionTree *collision.Tree
+		clock         *timing.Clock
+		// frozen is whether pushing this scene paused the clock beneath it
+		frozen bool
+	}
+}
+
+// PushScene starts the named scene on top of the current scene, without ending it. The pushed scene
+// is given its own event handler, callers, collision trees, clock and draw stack; its draw stack is
+// drawn above the scenes beneath it. While it is on top, input is delivered only to the pushed scene.
+// If the window's handler is an event.Stepper, the pushed scene's handler is an event.SyncBus, and
+// its enter events are triggered by calling Step rather than on a timer.
+//
+// The pushed scene's Start is called before PushScene returns, and its End is called when it is popped;
+// the next scene and result End returns are ignored. Ending the current scene, e.g. via NextScene, pops
+// all pushed scenes.
+func (w *Window) PushScene(name string, opts window.PushOptions) error {
+	scen, ok := w.SceneMap.Get(name)
+	if !ok {
+		return oakerr.NotFound{InputName: name}
+	}
+
+	w.sceneStackMutex.Lock()
+	ps := &pushedScene{
+		name:      name,
+		end:       scen.End,
+		drawStack: render.NewDrawStack(render.NewDynamicHeap()),
+		clock:     timing.NewClock(),
+	}
+	ps.below.clock = w.clock()
+	prevScene := w.SceneMap.CurrentScene
+	if len(w.sceneStack) != 0 {
+		top := w.sceneStack[len(w.sceneStack)-1]
+		ps.below.clock = top.clock
+		prevScene = top.name
+	}
+	if !opts.KeepRunning && !ps.below.clock.Paused() {
+		ps.below.clock.Pause()
+		ps.below.frozen = true
+	}
+
+	callerMap := event.NewCallerMap()
+	mouseTree, collisionTree := collision.NewTree(), collision.NewTree()
+	w.layerMutex.Lock()
+	// a window driven by a Stepper gets Steppers for its pushed scenes too, so enter events
+	// are still only triggered by the user
+	_, stepped := w.eventHandler.(event.Stepper)
+	if stepped {
+		ps.handler = event.NewSyncBus(callerMap, timing.FPSToFrameDelay(w.FrameRate))
+	} else {
+		ps.handler = event.NewBus(callerMap)
+	}
+	ps.below.handler = w.eventHandler
+	ps.below.callerMap = w.CallerMap
+	ps.below.mouseTree = w.MouseTree
+	ps.below.collisionTree = w.CollisionTree
+	w.eventHandler = ps.handler
+	w.CallerMap = callerMap
+	w.MouseTree = mouseTree
+	w.CollisionTree = collisionTree
+	w.layerMutex.Unlock()
+
+	gctx, cancel := context.WithCancel(w.ParentContext)
+	ps.cancel = cancel
+	w.sceneStack = append(w.sceneStack, ps)
+	w.sceneStackMutex.Unlock()
+
+	dlog.Info("Pushing scene", name)
+	scen.Start(&scene.Context{
+		Context:       gctx,
+		PreviousScene: prevScene,
+		SceneInput:    opts.Input,
+		DrawStack:     ps.drawStack,
+		Handler:       ps.handler,
+		CallerMap:     callerMap,
+		MouseTree:     mouseTree,
+		CollisionTree: collisionTree,
+		Clock:         ps.clock,
+		Window:        w,
+		State:         &w.State,
+	})
+	w.sceneStackMutex.Lock()
+	// the scene may have been popped during its own Start
+	if gctx.Err() == nil && !stepped {
+		ps.enterCancel = event.ClockEnterLoop(ps.handler, timing.FPSToFrameDelay(w.FrameRate), ps.clock)
+	}
+	w.sceneStackMutex.Unlock()
+	return nil
+}
+
+// PopScene ends the most recently pushed scene, resuming the scene beneath it if it was frozen.
+func (w *Window) PopScene() error {
+	w.sceneStackMutex.Lock()
+	if len(w.sceneStack) == 0 {
+		w.sceneStackMutex.Unlock()
+		return oakerr.NotFound{InputName: "pushed scene"}
+	}
+	ps := w.popScene()
+	w.sceneStackMutex.Unlock()
+	ps.callEnd()
+	return nil
+}
+
+// popScene must be called while holding the scene stack lock. The popped scene's End should be called
+// once the lock is released.
+func (w *Window) popScene() *pushedScene {
+	ps := w.sceneStack[len(w.sceneStack)-1]
+	w.sceneStack[len(w.sceneStack)-1] = nil
+	w.sceneStack = w.sceneStack[:len(w.sceneStack)-1]
+
+	dlog.Info("Popping scene", ps.name)
+	ps.cancel()
+	w.layerMutex.Lock()
+	w.eventHandler = ps.below.handler
+	w.CallerMap = ps.below.callerMap
+	w.MouseTree = ps.below.mouseTree
+	w.CollisionTree = ps.below.collisionTree
+	w.layerMutex.Unlock()
+	if ps.below.frozen {
+		ps.below.clock.Resume()
+	}
+	// Q: Why stop the enter loop in a new goroutine?
+	// A: PopScene is commonly called from one of the popped scene's bindings. If that binding
+	//    is bound to enter events, the enter loop cannot stop until the binding returns.
+	go func() {
+		if ps.enterCancel != nil {
+			ps.enterCancel()
+		}
+		ps.handler.Reset()
+	}()
+	return ps
+}
+
+// callEnd calls a popped scene's End.
+// Q: Why not call End while popping?
+// A: End may push or pop scenes itself, which would deadlock on the scene stack lock.
+func (ps *pushedScene) callEnd() {
+	if ps.end != nil {
+		ps.end()
+	}
+}
+
+// popAllScenes pops every pushed scene, in reverse order.
+func (w *Window) popAllScenes() {
+	w.sceneStackMutex.Lock()
+	popped := make([]*pushedScene, 0, len(w.sceneStack))
+	for len(w.sceneStack) != 0 {
+		popped = append(popped, w.popScene())
+	}
+	w.sceneStackMutex.Unlock()
+	for _, ps := range popped {
+		ps.callEnd()
+	}
+}
+
+// drawPushedScenes draws the draw stacks of all pushed scenes, in the order they were pushed.
+func (w *Window) drawPushedScenes(buff *image.RGBA, p *intgeom.Point2) {
+	w.sceneStackMutex.Lock()
+	stacks := make([]*render.DrawStack, len(w.sceneStack))
+	for i, ps := range w.sceneStack {
+		stacks[i] = ps.drawStack
+	}
+	w.sceneStackMutex.Unlock()
+	for _, ds := range stacks {
+		ds.PreDraw()
+		ds.DrawToScreen(buff, p, w.ScreenWidth, w.ScreenHeight)
+	}
+}
@@ -0,0 +1,137 @@
+package oak
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/scene"
+	"github.com/oakmound/oak/v4/timing"
+	"github.com/oakmound/oak/v4/window"
+)
+
+func TestPushScene(t *testing.T) {
+	w := NewWindow()
+	w.Clock.Resume()
+	defer w.Clock.Resume()
+	var pushedCtx *scene.Context
+	ended := 0
+	err := w.SceneMap.AddScene("menu", scene.Scene{
+		Start: func(ctx *scene.Context) {
+			pushedCtx = ctx
+		},
+		End: func() (string, *scene.Result) {
+			ended++
+			return "", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scene Add failed: %v", err)
+	}
+	if err := w.PushScene("missing", window.PushOptions{}); err == nil {
+		t.Fatal("expected error pushing unknown scene")
+	}
+	if err := w.PopScene(); err == nil {
+		t.Fatal("expected error popping with no pushed scenes")
+	}
+
+	baseHandler := w.EventHandler()
+	baseMouseTree := w.MouseTree
+	if err := w.PushScene("menu", window.PushOptions{Input: 1}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if pushedCtx == nil || pushedCtx.SceneInput != 1 {
+		t.Fatal("expected pushed scene to start with its input")
+	}
+	if w.EventHandler() == baseHandler || pushedCtx.Handler != w.EventHandler() {
+		t.Fatal("expected pushed scene to receive its own handler")
+	}
+	if w.MouseTree == baseMouseTree {
+		t.Fatal("expected pushed scene to receive its own mouse tree")
+	}
+	if !w.Clock.Paused() {
+		t.Fatal("expected scene beneath to be frozen")
+	}
+
+	if err := w.PushScene("menu", window.PushOptions{KeepRunning: true}); err != nil {
+		t.Fatalf("second push failed: %v", err)
+	}
+	if pushedCtx.PreviousScene != "menu" {
+		t.Fatalf("expected previous scene to be menu, got %v", pushedCtx.PreviousScene)
+	}
+	if err := w.PopScene(); err != nil {
+		t.Fatalf("pop failed: %v", err)
+	}
+	if ended != 1 {
+		t.Fatalf("expected popped scene to end, got %v ends", ended)
+	}
+	if err := w.PopScene(); err != nil {
+		t.Fatalf("pop failed: %v", err)
+	}
+	if w.EventHandler() != baseHandler || w.MouseTree != baseMouseTree {
+		t.Fatal("expected popping to restore the base scene's handlers")
+	}
+	if w.Clock.Paused() {
+		t.Fatal("expected scene beneath to be resumed")
+	}
+	if pushedCtx.Err() == nil {
+		t.Fatal("expected popped scene's context to be canceled")
+	}
+}
+
+func TestPushScene_Stepper(t *testing.T) {
+	w := NewWindow()
+	defer w.Clock.Resume()
+	w.SetLogicHandler(event.NewSyncBus(w.CallerMap, 10*time.Millisecond))
+	var pushedCtx *scene.Context
+	err := w.SceneMap.AddScene("menu", scene.Scene{
+		Start: func(ctx *scene.Context) {
+			pushedCtx = ctx
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scene Add failed: %v", err)
+	}
+	if err := w.PushScene("menu", window.PushOptions{}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	defer w.PopScene()
+	bus, ok := pushedCtx.Handler.(*event.SyncBus)
+	if !ok {
+		t.Fatalf("expected pushed scene on a stepped window to get a SyncBus, got %T", pushedCtx.Handler)
+	}
+	entered := 0
+	event.GlobalBind(bus, event.Enter, func(event.EnterPayload) event.Response {
+		entered++
+		return 0
+	})
+	time.Sleep(50 * time.Millisecond)
+	if entered != 0 {
+		t.Fatalf("expected no enter events before stepping, got %v", entered)
+	}
+	bus.Step()
+	if entered != 1 {
+		t.Fatalf("expected one enter event after stepping, got %v", entered)
+	}
+}
+
+func TestPushScene_NilClock(t *testing.T) {
+	w := NewWindow()
+	w.Clock = nil
+	defer timing.DefaultClock.Resume()
+	if err := w.SceneMap.AddScene("menu", scene.Scene{}); err != nil {
+		t.Fatalf("Scene Add failed: %v", err)
+	}
+	if err := w.PushScene("menu", window.PushOptions{}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if !timing.DefaultClock.Paused() {
+		t.Fatal("expected the default clock to be frozen beneath the pushed scene")
+	}
+	if err := w.PopScene(); err != nil {
+		t.Fatalf("pop failed: %v", err)
+	}
+	if timing.DefaultClock.Paused() {
+		t.Fatal("expected the default clock to be resumed")
+	}
+}
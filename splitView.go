@@ -0,0 +1,112 @@
+package oak
+
+import (
+	"image"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// A SplitView draws the world into one area of the window from the point of view of its own
+// camera, e.g. for each player of a local multiplayer game.
+type SplitView struct {
+	// Rect is the area of the window this view is drawn to, in screen pixels.
+	Rect intgeom.Rect2
+	// Camera positions and zooms this view. If nil, the view stays at the origin of the world.
+	Camera *Camera
+
+	// mu guards view, which is read by View outside of the window's camera lock
+	mu sync.Mutex
+	// view is the position in the world at the top left corner of Rect, as of the last draw
+	view       intgeom.Point2
+	zoom       float64
+	zoomBuffer *image.RGBA
+}
+
+// View returns the position in the world at the top left corner of this view, as of the last
+// frame drawn.
+func (sv *SplitView) View() intgeom.Point2 {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.view
+}
+
+// SetSplitViews splits the window into separate views of the world, drawn in order. While a window
+// has split views, its own camera and viewport do not affect what is drawn, and relative mouse
+// events are positioned in the world of the view they occur in. Calling SetSplitViews with no
+// views returns the window to a single view. A window's split views are removed when a new scene
+// starts.
+func (w *Window) SetSplitViews(views ...*SplitView) {
+	w.cameraMutex.Lock()
+	for _, sv := range views {
+		sv.zoom = 1
+	}
+	w.splitViews = views
+	w.cameraUpdated = time.Time{}
+	w.cameraMutex.Unlock()
+}
+
+// SplitViews returns the window's split views, if it has any.
+func (w *Window) SplitViews() []*SplitView {
+	w.cameraMutex.Lock()
+	defer w.cameraMutex.Unlock()
+	return w.splitViews
+}
+
+// drawSplitViews updates the cameras of and draws each split view, returning false if the window
+// has no split views.
+func (w *Window) drawSplitViews(buff *image.RGBA) bool {
+	w.cameraMutex.Lock()
+	views := w.splitViews
+	if len(views) == 0 {
+		w.cameraMutex.Unlock()
+		return false
+	}
+	dt := w.cameraDelta()
+	bounds := w.worldBounds()
+	for _, sv := range views {
+		if sv.Camera != nil {
+			size := sv.Rect.Max.Sub(sv.Rect.Min)
+			view := sv.Camera.update(dt, floatgeom.Point2{float64(size.X()), float64(size.Y())}, bounds)
+			sv.mu.Lock()
+			sv.view = intgeom.Point2{int(math.Round(view.X())), int(math.Round(view.Y()))}
+			sv.mu.Unlock()
+			sv.zoom = sv.Camera.Zoom()
+		}
+	}
+	w.cameraMutex.Unlock()
+
+	for _, sv := range views {
+		rect := image.Rect(sv.Rect.Min.X(), sv.Rect.Min.Y(), sv.Rect.Max.X(), sv.Rect.Max.Y()).Intersect(buff.Bounds())
+		if rect.Empty() {
+			continue
+		}
+		w.drawView(buff, rect, sv.view, sv.zoom, &sv.zoomBuffer)
+	}
+	return true
+}
+
+// screenToWorld converts a position on screen to a position in the world, accounting for the
+// window's split views and camera zoom.
+func (w *Window) screenToWorld(pt floatgeom.Point2) floatgeom.Point2 {
+	w.cameraMutex.Lock()
+	defer w.cameraMutex.Unlock()
+	// later views are drawn over earlier views
+	for i := len(w.splitViews) - 1; i >= 0; i-- {
+		sv := w.splitViews[i]
+		if pt.X() < float64(sv.Rect.Min.X()) || pt.Y() < float64(sv.Rect.Min.Y()) ||
+			pt.X() >= float64(sv.Rect.Max.X()) || pt.Y() >= float64(sv.Rect.Max.Y()) {
+			continue
+		}
+		rel := pt.Sub(floatgeom.Point2{float64(sv.Rect.Min.X()), float64(sv.Rect.Min.Y())}).DivConst(sv.zoom)
+		return rel.Add(floatgeom.Point2{float64(sv.view.X()), float64(sv.view.Y())})
+	}
+	zoom := 1.0
+	if w.camera != nil {
+		zoom = w.camera.Zoom()
+	}
+	return pt.DivConst(zoom).Add(floatgeom.Point2{float64(w.viewPos.X()), float64(w.viewPos.Y())})
+}
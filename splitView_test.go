@@ -0,0 +1,97 @@
+package oak
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/mouse"
+	"github.com/oakmound/oak/v4/render"
+)
+
+func TestSplitViews(t *testing.T) {
+	w := NewWindow()
+	w.ScreenWidth, w.ScreenHeight = 40, 20
+	w.DrawStack = render.NewDrawStack(render.NewDynamicHeap())
+	red := color.RGBA{255, 0, 0, 255}
+	box := render.NewColorBox(4, 4, red)
+	box.SetPos(100, 100)
+	w.DrawStack.Draw(box)
+	w.DrawStack.PreDraw()
+
+	if w.drawSplitViews(image.NewRGBA(image.Rect(0, 0, 40, 20))) {
+		t.Fatal("expected no split views to draw")
+	}
+
+	left := NewCamera()
+	left.SetCenter(floatgeom.Point2{110, 110})
+	right := NewCamera()
+	right.SetCenter(floatgeom.Point2{104, 104})
+	right.SetZoom(2)
+	w.SetSplitViews(
+		&SplitView{Rect: intgeom.NewRect2(0, 0, 20, 20), Camera: left},
+		&SplitView{Rect: intgeom.NewRect2(20, 0, 40, 20), Camera: right},
+	)
+
+	buff := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	if !w.drawSplitViews(buff) {
+		t.Fatal("expected split views to draw")
+	}
+	views := w.SplitViews()
+	if views[0].View() != (intgeom.Point2{100, 100}) || views[1].View() != (intgeom.Point2{99, 99}) {
+		t.Fatalf("unexpected views: %v %v", views[0].View(), views[1].View())
+	}
+	// the left view shows the box at its top left corner
+	if buff.RGBAAt(0, 0) != red || buff.RGBAAt(3, 3) != red || buff.RGBAAt(4, 4) == red {
+		t.Fatal("expected box drawn in the top left of the left view")
+	}
+	// the right view shows the box zoomed in, one world pixel from its top left corner
+	if buff.RGBAAt(21, 1) == red || buff.RGBAAt(22, 2) != red || buff.RGBAAt(29, 9) != red || buff.RGBAAt(30, 10) == red {
+		t.Fatal("expected box drawn zoomed in the right view")
+	}
+	// the right view does not draw into the left view
+	if buff.RGBAAt(19, 19) == red {
+		t.Fatal("expected views to be clipped to their rects")
+	}
+
+	// relative mouse events are positioned in the world of the view they occur in
+	w.TriggerMouseEvent(mouse.NewEvent(5, 5, mouse.ButtonLeft, mouse.Press))
+	if rel := w.LastRelativeMouseEvent.Point2; rel != (floatgeom.Point2{105, 105}) {
+		t.Fatalf("expected relative mouse event at (105, 105), got %v", rel)
+	}
+	w.TriggerMouseEvent(mouse.NewEvent(30, 10, mouse.ButtonLeft, mouse.Press))
+	if rel := w.LastRelativeMouseEvent.Point2; rel != (floatgeom.Point2{104, 104}) {
+		t.Fatalf("expected relative mouse event at (104, 104), got %v", rel)
+	}
+
+	w.SetSplitViews()
+	if w.drawSplitViews(buff) {
+		t.Fatal("expected split views to be removed")
+	}
+}
+
+func TestSplitView_ViewWhileDrawing(t *testing.T) {
+	w := NewWindow()
+	w.ScreenWidth, w.ScreenHeight = 20, 20
+	w.DrawStack = render.NewDrawStack(render.NewDynamicHeap())
+	cam := NewCamera()
+	sv := &SplitView{Rect: intgeom.NewRect2(0, 0, 20, 20), Camera: cam}
+	w.SetSplitViews(sv)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buff := image.NewRGBA(image.Rect(0, 0, 20, 20))
+		for i := 0; i < 100; i++ {
+			cam.SetCenter(floatgeom.Point2{float64(i), float64(i)})
+			w.drawSplitViews(buff)
+		}
+	}()
+	// run with -race: reading a view must not race with drawing
+	for i := 0; i < 1000; i++ {
+		sv.View()
+	}
+	<-done
+}
@@ -0,0 +1,272 @@
+package tiled
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+type jsonMap struct {
+	Orientation string         `json:"orientation"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	TileWidth   int            `json:"tilewidth"`
+	TileHeight  int            `json:"tileheight"`
+	Infinite    bool           `json:"infinite"`
+	Properties  []jsonProperty `json:"properties"`
+	Tilesets    []jsonTileset  `json:"tilesets"`
+	Layers      []jsonLayer    `json:"layers"`
+}
+
+type jsonProperty struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonTileset struct {
+	FirstGID   uint32         `json:"firstgid"`
+	Source     string         `json:"source"`
+	Name       string         `json:"name"`
+	TileWidth  int            `json:"tilewidth"`
+	TileHeight int            `json:"tileheight"`
+	Spacing    int            `json:"spacing"`
+	Margin     int            `json:"margin"`
+	TileCount  int            `json:"tilecount"`
+	Columns    int            `json:"columns"`
+	Image      string         `json:"image"`
+	Tiles      []jsonTile     `json:"tiles"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+type jsonTile struct {
+	ID        uint32 `json:"id"`
+	Type      string `json:"type"`
+	Class     string `json:"class"`
+	Image     string `json:"image"`
+	Animation []struct {
+		TileID   uint32 `json:"tileid"`
+		Duration int    `json:"duration"`
+	} `json:"animation"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+type jsonLayer struct {
+	ID      int      `json:"id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+	Visible *bool    `json:"visible"`
+	Opacity *float64 `json:"opacity"`
+	OffsetX float64  `json:"offsetx"`
+	OffsetY float64  `json:"offsety"`
+	// Data is either an array of global IDs or an encoded string
+	Data        json.RawMessage `json:"data"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Objects     []jsonObject    `json:"objects"`
+	Image       string          `json:"image"`
+	Layers      []jsonLayer     `json:"layers"`
+	Properties  []jsonProperty  `json:"properties"`
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	Rotation   float64        `json:"rotation"`
+	GID        uint32         `json:"gid"`
+	Visible    *bool          `json:"visible"`
+	Ellipse    bool           `json:"ellipse"`
+	Point      bool           `json:"point"`
+	Polygon    []jsonPoint    `json:"polygon"`
+	Polyline   []jsonPoint    `json:"polyline"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+type jsonPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func parseJSON(data []byte, dir string) (*Map, error) {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, err
+	}
+	if err := checkMap(jm.Orientation, jm.Infinite); err != nil {
+		return nil, err
+	}
+	m := &Map{
+		Width:      jm.Width,
+		Height:     jm.Height,
+		TileWidth:  jm.TileWidth,
+		TileHeight: jm.TileHeight,
+		Properties: jsonProperties(jm.Properties),
+	}
+	for _, jts := range jm.Tilesets {
+		if jts.Source != "" {
+			ts, err := loadTileset(filepath.Join(dir, jts.Source), jts.FirstGID)
+			if err != nil {
+				return nil, err
+			}
+			m.Tilesets = append(m.Tilesets, ts)
+			continue
+		}
+		ts := jts.toTileset(dir)
+		ts.FirstGID = jts.FirstGID
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	layers, err := jsonLayers(jm.Layers, dir, floatgeom.Point2{}, true, 1)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+	return m, nil
+}
+
+func parseJSONTileset(data []byte, dir string) (*Tileset, error) {
+	var jts jsonTileset
+	if err := json.Unmarshal(data, &jts); err != nil {
+		return nil, err
+	}
+	return jts.toTileset(dir), nil
+}
+
+func (jts jsonTileset) toTileset(dir string) *Tileset {
+	ts := &Tileset{
+		Name:       jts.Name,
+		TileWidth:  jts.TileWidth,
+		TileHeight: jts.TileHeight,
+		Spacing:    jts.Spacing,
+		Margin:     jts.Margin,
+		TileCount:  jts.TileCount,
+		Columns:    jts.Columns,
+		Tiles:      make(map[uint32]*Tile, len(jts.Tiles)),
+		Properties: jsonProperties(jts.Properties),
+	}
+	if jts.Image != "" {
+		ts.Image = filepath.Join(dir, jts.Image)
+	}
+	for _, jt := range jts.Tiles {
+		t := &Tile{
+			ID:         jt.ID,
+			Type:       jt.Type,
+			Properties: jsonProperties(jt.Properties),
+		}
+		if jt.Class != "" {
+			t.Type = jt.Class
+		}
+		if jt.Image != "" {
+			t.Image = filepath.Join(dir, jt.Image)
+		}
+		for _, f := range jt.Animation {
+			t.Animation = append(t.Animation, Frame{
+				TileID:   f.TileID,
+				Duration: time.Duration(f.Duration) * time.Millisecond,
+			})
+		}
+		ts.Tiles[t.ID] = t
+	}
+	return ts
+}
+
+func jsonProperties(props []jsonProperty) Properties {
+	p := make(Properties, len(props))
+	for _, prop := range props {
+		// non-string values are kept as their JSON text, e.g. 1.5 or true
+		v := string(prop.Value)
+		if s, err := strconv.Unquote(v); err == nil {
+			v = s
+		}
+		p[prop.Name] = v
+	}
+	return p
+}
+
+// jsonLayers converts layers and flattens groups, given the offset, visibility and opacity of
+// the groups containing them.
+func jsonLayers(jls []jsonLayer, dir string, offset floatgeom.Point2, visible bool, opacity float64) ([]*Layer, error) {
+	var layers []*Layer
+	for _, jl := range jls {
+		l := &Layer{
+			ID:         jl.ID,
+			Name:       jl.Name,
+			Type:       jl.Type,
+			Visible:    visible && (jl.Visible == nil || *jl.Visible),
+			Opacity:    opacity,
+			Offset:     offset.Add(floatgeom.Point2{jl.OffsetX, jl.OffsetY}),
+			Properties: jsonProperties(jl.Properties),
+		}
+		if jl.Opacity != nil {
+			l.Opacity *= *jl.Opacity
+		}
+		switch jl.Type {
+		case TileLayer:
+			l.Width = jl.Width
+			l.Height = jl.Height
+			var encoded string
+			if err := json.Unmarshal(jl.Data, &encoded); err == nil {
+				if l.Tiles, err = decodeTiles(jl.Encoding, jl.Compression, encoded); err != nil {
+					return nil, err
+				}
+			} else if err := json.Unmarshal(jl.Data, &l.Tiles); err != nil {
+				return nil, err
+			}
+		case ObjectGroup:
+			for _, jo := range jl.Objects {
+				o := jo.toObject()
+				finishObject(&o, l.Offset)
+				l.Objects = append(l.Objects, o)
+			}
+		case ImageLayer:
+			if jl.Image != "" {
+				l.Image = filepath.Join(dir, jl.Image)
+			}
+		case "group":
+			children, err := jsonLayers(jl.Layers, dir, l.Offset, l.Visible, l.Opacity)
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, children...)
+			continue
+		default:
+			continue
+		}
+		layers = append(layers, l)
+	}
+	return layers, nil
+}
+
+func (jo jsonObject) toObject() Object {
+	o := Object{
+		ID:         jo.ID,
+		Name:       jo.Name,
+		Type:       jo.Type,
+		Rect:       floatgeom.NewRect2WH(jo.X, jo.Y, jo.Width, jo.Height),
+		Rotation:   jo.Rotation,
+		GID:        jo.GID,
+		Visible:    jo.Visible == nil || *jo.Visible,
+		Ellipse:    jo.Ellipse,
+		Point:      jo.Point,
+		Properties: jsonProperties(jo.Properties),
+	}
+	if jo.Class != "" {
+		o.Type = jo.Class
+	}
+	for _, p := range jo.Polygon {
+		o.Polygon = append(o.Polygon, floatgeom.Point2{p.X, p.Y})
+	}
+	for _, p := range jo.Polyline {
+		o.Polyline = append(o.Polyline, floatgeom.Point2{p.X, p.Y})
+	}
+	return o
+}
@@ -0,0 +1,66 @@
+package tiled
+
+import (
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/entities"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+// LabelProperty is the custom property objects may set to an integer collision.Label.
+const LabelProperty = "label"
+
+// Label returns an object's collision label: labels[o.Type] if present, otherwise the integer
+// value of its LabelProperty.
+func (o Object) Label(labels map[string]collision.Label) (collision.Label, bool) {
+	if l, ok := labels[o.Type]; ok {
+		return l, true
+	}
+	if l, ok := o.Properties.Int(LabelProperty); ok {
+		return collision.Label(l), true
+	}
+	return 0, false
+}
+
+// Spaces converts the objects of all object groups into collision spaces, labeled by their Label.
+// Objects without a label and point objects are skipped. Ellipses, polygons and polylines become
+// spaces covering their bounds.
+func (m *Map) Spaces(labels map[string]collision.Label) []*collision.Space {
+	var sps []*collision.Space
+	for _, o := range m.Objects() {
+		label, ok := o.Label(labels)
+		if !ok || o.Point {
+			continue
+		}
+		sps = append(sps, collision.NewLabeledSpace(o.Rect.Min.X(), o.Rect.Min.Y(), o.Rect.W(), o.Rect.H(), label))
+	}
+	return sps
+}
+
+// EntityOptions returns options creating an entity covering an object. If the object has a label
+// it is applied, and if it is a tile object its tile is used as the entity's renderable.
+func (m *Map) EntityOptions(o Object) []entities.Option {
+	opts := []entities.Option{
+		entities.WithRect(o.Rect),
+	}
+	if label, ok := o.Label(nil); ok {
+		opts = append(opts, entities.WithLabel(label))
+	}
+	if img, ok := m.TileImage(o.GID); ok {
+		opts = append(opts, entities.WithRenderable(render.NewSprite(0, 0, img)))
+	}
+	return opts
+}
+
+// A Spawner creates whatever an object of some type represents, given options from EntityOptions.
+type Spawner func(ctx *scene.Context, o Object, opts []entities.Option)
+
+// Spawn calls the spawner registered for each object's type, in layer order. Objects whose type has
+// no spawner are skipped.
+func (m *Map) Spawn(ctx *scene.Context, spawners map[string]Spawner) {
+	for _, o := range m.Objects() {
+		if spawn, ok := spawners[o.Type]; ok {
+			spawn(ctx, o, m.EntityOptions(o))
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package tiled
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+)
+
+// load loads the images of a tileset through render.DefaultCache.
+func (ts *Tileset) load() error {
+	if ts.Image == "" {
+		ts.images = make(map[uint32]*image.RGBA, len(ts.Tiles))
+		for id, t := range ts.Tiles {
+			if t.Image == "" {
+				continue
+			}
+			sp, err := render.DefaultCache.LoadSprite(t.Image)
+			if err != nil {
+				return err
+			}
+			ts.images[id] = sp.GetRGBA()
+		}
+		return nil
+	}
+	if ts.TileWidth <= 0 {
+		return oakerr.InvalidInput{InputName: "tilewidth"}
+	}
+	if ts.TileHeight <= 0 {
+		return oakerr.InvalidInput{InputName: "tileheight"}
+	}
+	sp, err := render.DefaultCache.LoadSprite(ts.Image)
+	if err != nil {
+		return err
+	}
+	rgba := sp.GetRGBA()
+	bounds := rgba.Bounds()
+	// Q: Why not render.MakeSheet?
+	// A: Tilesets may have margins around and spacing between their tiles.
+	cols := (bounds.Dx() - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+	rows := (bounds.Dy() - 2*ts.Margin + ts.Spacing) / (ts.TileHeight + ts.Spacing)
+	if ts.Columns == 0 {
+		ts.Columns = cols
+	}
+	sheet := make(render.Sheet, cols)
+	for x := range sheet {
+		sheet[x] = make([]*image.RGBA, rows)
+		for y := range sheet[x] {
+			minX := bounds.Min.X + ts.Margin + x*(ts.TileWidth+ts.Spacing)
+			minY := bounds.Min.Y + ts.Margin + y*(ts.TileHeight+ts.Spacing)
+			tile := image.NewRGBA(image.Rect(0, 0, ts.TileWidth, ts.TileHeight))
+			draw.Draw(tile, tile.Bounds(), rgba, image.Point{minX, minY}, draw.Src)
+			sheet[x][y] = tile
+		}
+	}
+	ts.Sheet = &sheet
+	return nil
+}
+
+// TileImage returns the image of a tile in this tileset by its local ID.
+func (ts *Tileset) TileImage(id uint32) (*image.RGBA, bool) {
+	if ts.Sheet == nil {
+		img, ok := ts.images[id]
+		return img, ok
+	}
+	if ts.Columns <= 0 {
+		return nil, false
+	}
+	x, y := int(id)%ts.Columns, int(id)/ts.Columns
+	sh := *ts.Sheet
+	if x >= len(sh) || y >= len(sh[x]) {
+		return nil, false
+	}
+	return sh[x][y], true
+}
+
+type flipCache struct {
+	sync.Mutex
+	images map[uint32]*image.RGBA
+}
+
+// TileImage returns the image of a tile by its global ID, flipped as its flags describe. Unflipped
+// images are shared with their tileset's Sheet and should not be modified.
+func (m *Map) TileImage(gid uint32) (*image.RGBA, bool) {
+	ts, id, ok := m.Tileset(gid)
+	if !ok {
+		return nil, false
+	}
+	img, ok := ts.TileImage(id)
+	if !ok || gid == GID(gid) {
+		return img, ok
+	}
+	m.flipped.Lock()
+	defer m.flipped.Unlock()
+	if flipped, ok := m.flipped.images[gid]; ok {
+		return flipped, true
+	}
+	if m.flipped.images == nil {
+		m.flipped.images = make(map[uint32]*image.RGBA)
+	}
+	flipped := flip(img, gid)
+	m.flipped.images[gid] = flipped
+	return flipped, true
+}
+
+// flip applies the flip flags of gid to img. Tiled applies the diagonal flip first, then the
+// horizontal flip, then the vertical flip.
+func flip(img *image.RGBA, gid uint32) *image.RGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	diagonal := gid&FlipDiagonal != 0
+	if diagonal {
+		w, h = h, w
+	}
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sx, sy := x, y
+			if gid&FlipVertical != 0 {
+				sy = h - 1 - sy
+			}
+			if gid&FlipHorizontal != 0 {
+				sx = w - 1 - sx
+			}
+			if diagonal {
+				sx, sy = sy, sx
+			}
+			si := img.PixOffset(img.Rect.Min.X+sx, img.Rect.Min.Y+sy)
+			copy(out.Pix[out.PixOffset(x, y):], img.Pix[si:si+4])
+		}
+	}
+	return out
+}
+
+// tilePos returns where a tile image should be drawn for the tile at (x, y) of a layer. Tiles
+// taller than the map's grid extend upwards from the bottom of their cell.
+func (m *Map) tilePos(l *Layer, x, y int, img *image.RGBA) (float64, float64) {
+	return l.Offset.X() + float64(x*m.TileWidth),
+		l.Offset.Y() + float64((y+1)*m.TileHeight-img.Bounds().Dy())
+}
+
+// TileSprites returns a sprite for each non-empty tile of a tile layer, positioned in the map.
+// The sprites share their images with their tilesets' Sheets.
+func (m *Map) TileSprites(l *Layer) []*render.Sprite {
+	var sps []*render.Sprite
+	for y := 0; y < l.Height; y++ {
+		for x := 0; x < l.Width; x++ {
+			img, ok := m.TileImage(l.TileAt(x, y))
+			if !ok {
+				continue
+			}
+			px, py := m.tilePos(l, x, y, img)
+			sps = append(sps, render.NewSprite(px, py, img))
+		}
+	}
+	return sps
+}
+
+// RenderLayer renders a whole tile layer, including its opacity, into a single sprite positioned
+// at the layer's offset.
+func (m *Map) RenderLayer(l *Layer) *render.Sprite {
+	rgba := image.NewRGBA(image.Rect(0, 0, m.Width*m.TileWidth, m.Height*m.TileHeight))
+	mask := image.NewUniform(color.Alpha{uint8(l.Opacity * 255)})
+	for y := 0; y < l.Height; y++ {
+		for x := 0; x < l.Width; x++ {
+			img, ok := m.TileImage(l.TileAt(x, y))
+			if !ok {
+				continue
+			}
+			px, py := m.tilePos(l, x, y, img)
+			min := image.Point{int(px - l.Offset.X()), int(py - l.Offset.Y())}
+			draw.DrawMask(rgba, image.Rectangle{min, min.Add(img.Bounds().Size())},
+				img, img.Bounds().Min, mask, image.Point{}, draw.Over)
+		}
+	}
+	return render.NewSprite(l.Offset.X(), l.Offset.Y(), rgba)
+}
+
+// AddTo renders each visible tile and image layer of the map into a sprite and adds it to rh, which
+// may be a dynamic heap for a map larger than the screen, or a static heap for a map which fits it.
+// Layers are added in order, starting at baseLayer. The added sprites are returned.
+func (m *Map) AddTo(rh *render.RenderableHeap, baseLayer int) ([]*render.Sprite, error) {
+	var sps []*render.Sprite
+	for _, l := range m.Layers {
+		if !l.Visible {
+			continue
+		}
+		var sp *render.Sprite
+		switch l.Type {
+		case TileLayer:
+			sp = m.RenderLayer(l)
+		case ImageLayer:
+			if l.Image == "" {
+				continue
+			}
+			var err error
+			sp, err = render.DefaultCache.LoadSprite(l.Image)
+			if err != nil {
+				return nil, err
+			}
+			sp.SetPos(l.Offset.X(), l.Offset.Y())
+		default:
+			continue
+		}
+		rh.Add(sp, baseLayer+len(sps))
+		sps = append(sps, sp)
+	}
+	return sps, nil
+}
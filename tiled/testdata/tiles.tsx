@@ -0,0 +1,15 @@
+<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" tiledversion="1.10.2" name="tiles" tilewidth="4" tileheight="4" spacing="1" margin="1" tilecount="4" columns="2">
+ <image source="tiles.png" width="11" height="11"/>
+ <tile id="1" class="grass">
+  <properties>
+   <property name="solid" type="bool" value="true"/>
+  </properties>
+ </tile>
+ <tile id="3">
+  <animation>
+   <frame tileid="3" duration="100"/>
+   <frame tileid="0" duration="200"/>
+  </animation>
+ </tile>
+</tileset>
@@ -0,0 +1,355 @@
+// Package tiled loads maps made with the Tiled map editor, in either its XML (.tmx) or JSON
+// (.json, .tmj) formats, and converts them into renderables, collision spaces and entities.
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/fileutil"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+)
+
+// Flags stored in the high bits of a tile's global ID
+const (
+	FlipHorizontal uint32 = 0x80000000
+	FlipVertical   uint32 = 0x40000000
+	FlipDiagonal   uint32 = 0x20000000
+	// rotateHexagonal is only meaningful for hexagonal maps, which are not supported
+	rotateHexagonal uint32 = 0x10000000
+
+	gidMask = ^(FlipHorizontal | FlipVertical | FlipDiagonal | rotateHexagonal)
+)
+
+// GID strips any flip flags from a tile's global ID. A GID of 0 is an empty tile.
+func GID(gid uint32) uint32 {
+	return gid & gidMask
+}
+
+// Layer types
+const (
+	TileLayer   = "tilelayer"
+	ObjectGroup = "objectgroup"
+	ImageLayer  = "imagelayer"
+)
+
+// A Map is a loaded Tiled map. Only orthogonal, finite maps are supported.
+type Map struct {
+	// Width and Height are the size of the map in tiles
+	Width, Height int
+	// TileWidth and TileHeight are the size of the map's grid in pixels
+	TileWidth, TileHeight int
+	// Tilesets are sorted by their FirstGID
+	Tilesets []*Tileset
+	// Layers are in draw order, bottom first. Group layers are flattened into their children.
+	Layers     []*Layer
+	Properties Properties
+
+	flipped flipCache
+}
+
+// A Tileset is a set of tiles used by a map.
+type Tileset struct {
+	// FirstGID is the global ID of this tileset's first tile in the map using it
+	FirstGID   uint32
+	Name       string
+	TileWidth  int
+	TileHeight int
+	Spacing    int
+	Margin     int
+	TileCount  int
+	Columns    int
+	// Image is the path of the tileset's image. It is empty for image collection tilesets,
+	// whose tiles each have their own Image.
+	Image string
+	// Sheet is Image split into tiles, indexed by column then row.
+	Sheet *render.Sheet
+	// Tiles holds the tiles of this tileset which have properties, types, animations or
+	// images of their own, keyed by their local ID.
+	Tiles      map[uint32]*Tile
+	Properties Properties
+
+	// images are the images of an image collection tileset's tiles
+	images map[uint32]*image.RGBA
+}
+
+// A Tile holds additional data for a single tile of a tileset.
+type Tile struct {
+	ID         uint32
+	Type       string
+	Image      string
+	Animation  []Frame
+	Properties Properties
+}
+
+// A Frame is one frame of an animated tile.
+type Frame struct {
+	// TileID is the local ID of the tile shown during this frame
+	TileID   uint32
+	Duration time.Duration
+}
+
+// A Layer is a tile, object or image layer of a map.
+type Layer struct {
+	ID      int
+	Name    string
+	Type    string
+	Visible bool
+	Opacity float64
+	// Offset is the layer's offset in pixels, including the offsets of any groups it was in
+	Offset floatgeom.Point2
+	// Width and Height are the size of a tile layer in tiles
+	Width, Height int
+	// Tiles are the global IDs, including flip flags, of a tile layer's tiles, in row-major order.
+	Tiles []uint32
+	// Objects are the objects of an object group
+	Objects []Object
+	// Image is the path of an image layer's image
+	Image      string
+	Properties Properties
+}
+
+// TileAt returns the global ID, including flip flags, of the tile at a given tile position of
+// a tile layer. Positions out of bounds return 0.
+func (l *Layer) TileAt(x, y int) uint32 {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height || y*l.Width+x >= len(l.Tiles) {
+		return 0
+	}
+	return l.Tiles[y*l.Width+x]
+}
+
+// An Object is a shape, point or tile placed in an object group.
+type Object struct {
+	ID   int
+	Name string
+	// Type is the object's type, or class in newer versions of Tiled
+	Type string
+	// Rect is the object's bounds in pixels, including its layer's offset. Unlike Tiled, the
+	// position of tile objects is their top left corner.
+	Rect     floatgeom.Rect2
+	Rotation float64
+	// GID is the global ID, including flip flags, of a tile object's tile
+	GID     uint32
+	Visible bool
+	Ellipse bool
+	Point   bool
+	// Polygon and Polyline are an object's points in pixels, including its position
+	Polygon    []floatgeom.Point2
+	Polyline   []floatgeom.Point2
+	Properties Properties
+}
+
+// Properties are the custom properties of a map, tileset, tile, layer or object. Values of
+// every type are stored as strings; colors are in Tiled's #AARRGGBB format, and file paths are
+// relative to the file they were defined in.
+type Properties map[string]string
+
+// Int returns the named property parsed as an integer.
+func (p Properties) Int(name string) (int, bool) {
+	v, err := strconv.Atoi(p[name])
+	return v, err == nil
+}
+
+// Float returns the named property parsed as a float.
+func (p Properties) Float(name string) (float64, bool) {
+	v, err := strconv.ParseFloat(p[name], 64)
+	return v, err == nil
+}
+
+// Bool returns the named property parsed as a bool.
+func (p Properties) Bool(name string) (bool, bool) {
+	v, err := strconv.ParseBool(p[name])
+	return v, err == nil
+}
+
+// Load loads a map from file, along with any external tilesets and tileset images it uses.
+// The format of the map is determined by its extension.
+func Load(file string) (*Map, error) {
+	data, err := fileutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(file)
+	var m *Map
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".tmx":
+		m, err = parseTMX(data, dir)
+	case ".json", ".tmj":
+		m, err = parseJSON(data, dir)
+	default:
+		return nil, oakerr.UnsupportedFormat{Format: filepath.Ext(file)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(m.Tilesets, func(i, j int) bool {
+		return m.Tilesets[i].FirstGID < m.Tilesets[j].FirstGID
+	})
+	for _, ts := range m.Tilesets {
+		if err := ts.load(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// loadTileset loads an external tileset by the format of its extension.
+func loadTileset(file string, firstGID uint32) (*Tileset, error) {
+	data, err := fileutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var ts *Tileset
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".tsx":
+		ts, err = parseTSX(data, filepath.Dir(file))
+	case ".json", ".tsj":
+		ts, err = parseJSONTileset(data, filepath.Dir(file))
+	default:
+		return nil, oakerr.UnsupportedFormat{Format: filepath.Ext(file)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	ts.FirstGID = firstGID
+	return ts, nil
+}
+
+// Tileset returns the tileset containing a global ID, and that ID local to the tileset.
+func (m *Map) Tileset(gid uint32) (*Tileset, uint32, bool) {
+	gid = GID(gid)
+	if gid == 0 {
+		return nil, 0, false
+	}
+	i := sort.Search(len(m.Tilesets), func(i int) bool {
+		return m.Tilesets[i].FirstGID > gid
+	}) - 1
+	if i < 0 {
+		return nil, 0, false
+	}
+	return m.Tilesets[i], gid - m.Tilesets[i].FirstGID, true
+}
+
+// Layer returns the first layer with the given name.
+func (m *Map) Layer(name string) (*Layer, bool) {
+	for _, l := range m.Layers {
+		if l.Name == name {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// Objects returns the objects of all object groups, in layer order.
+func (m *Map) Objects() []Object {
+	var objs []Object
+	for _, l := range m.Layers {
+		objs = append(objs, l.Objects...)
+	}
+	return objs
+}
+
+// decodeTiles decodes a tile layer's data, which is either comma separated or base64 encoded and
+// optionally compressed.
+func decodeTiles(encoding, compression, data string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		fields := strings.Split(strings.TrimSpace(data), ",")
+		tiles := make([]uint32, len(fields))
+		for i, f := range fields {
+			gid, err := strconv.ParseUint(strings.TrimSpace(f), 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			tiles[i] = uint32(gid)
+		}
+		return tiles, nil
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err != nil {
+			return nil, err
+		}
+		var r io.Reader = bytes.NewReader(raw)
+		switch compression {
+		case "":
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return nil, err
+			}
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, oakerr.UnsupportedFormat{Format: compression}
+		}
+		if raw, err = io.ReadAll(r); err != nil {
+			return nil, err
+		}
+		if len(raw)%4 != 0 {
+			return nil, oakerr.IndivisibleInput{InputName: "tile data", MustDivideBy: 4}
+		}
+		tiles := make([]uint32, len(raw)/4)
+		for i := range tiles {
+			tiles[i] = binary.LittleEndian.Uint32(raw[i*4:])
+		}
+		return tiles, nil
+	}
+	return nil, oakerr.UnsupportedFormat{Format: encoding}
+}
+
+// parsePoints parses a polygon's points from Tiled's "x1,y1 x2,y2" format.
+func parsePoints(s string) ([]floatgeom.Point2, error) {
+	var pts []floatgeom.Point2
+	for _, pair := range strings.Fields(s) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			return nil, oakerr.InvalidInput{InputName: "points"}
+		}
+		x, err := strconv.ParseFloat(xy[0], 64)
+		if err != nil {
+			return nil, err
+		}
+		y, err := strconv.ParseFloat(xy[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		pts = append(pts, floatgeom.Point2{x, y})
+	}
+	return pts, nil
+}
+
+// finishObject positions an object's shape in the map, adjusting for its layer's offset and
+// Tiled's placement of tile objects at their bottom left corner.
+func finishObject(o *Object, offset floatgeom.Point2) {
+	pos := o.Rect.Min.Add(offset)
+	if o.GID != 0 {
+		pos = pos.Sub(floatgeom.Point2{0, o.Rect.H()})
+	}
+	o.Rect = floatgeom.NewRect2WH(pos.X(), pos.Y(), o.Rect.W(), o.Rect.H())
+	for i, p := range o.Polygon {
+		o.Polygon[i] = p.Add(pos)
+	}
+	for i, p := range o.Polyline {
+		o.Polyline[i] = p.Add(pos)
+	}
+	pts := o.Polygon
+	if len(pts) == 0 {
+		pts = o.Polyline
+	}
+	if len(pts) != 0 {
+		o.Rect = floatgeom.NewBoundingRect2(pts...)
+	}
+}
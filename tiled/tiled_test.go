@@ -0,0 +1,169 @@
+package tiled_test
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/entities"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+	"github.com/oakmound/oak/v4/tiled"
+)
+
+func TestLoad(t *testing.T) {
+	for _, file := range []string{"testdata/map.tmx", "testdata/map.json"} {
+		file := file
+		t.Run(file, func(t *testing.T) {
+			m, err := tiled.Load(file)
+			if err != nil {
+				t.Fatalf("load failed: %v", err)
+			}
+			if m.Width != 3 || m.Height != 2 || m.TileWidth != 4 || m.TileHeight != 4 {
+				t.Fatalf("unexpected map dimensions: %v %v %v %v", m.Width, m.Height, m.TileWidth, m.TileHeight)
+			}
+			if g, ok := m.Properties.Float("gravity"); !ok || g != 9.8 {
+				t.Fatalf("expected gravity property 9.8, got %v", g)
+			}
+			if m.Properties["music"] != "level1.wav" {
+				t.Fatalf("expected music property, got %q", m.Properties["music"])
+			}
+
+			if len(m.Tilesets) != 1 {
+				t.Fatalf("expected 1 tileset, got %v", len(m.Tilesets))
+			}
+			ts := m.Tilesets[0]
+			if ts.FirstGID != 1 || ts.Columns != 2 {
+				t.Fatalf("unexpected tileset: %+v", ts)
+			}
+			grass := ts.Tiles[1]
+			if grass == nil || grass.Type != "grass" {
+				t.Fatalf("expected grass tile, got %+v", grass)
+			}
+			if solid, ok := grass.Properties.Bool("solid"); !ok || !solid {
+				t.Fatalf("expected grass to be solid")
+			}
+			anim := ts.Tiles[3].Animation
+			if len(anim) != 2 || anim[1].TileID != 0 || anim[1].Duration != 200*time.Millisecond {
+				t.Fatalf("unexpected animation: %+v", anim)
+			}
+
+			if len(m.Layers) != 2 {
+				t.Fatalf("expected 2 layers, got %v", len(m.Layers))
+			}
+			ground, ok := m.Layer("ground")
+			if !ok || ground.Type != tiled.TileLayer {
+				t.Fatalf("expected ground tile layer")
+			}
+			if ground.TileAt(1, 0) != 2 || ground.TileAt(2, 0) != 0 || tiled.GID(ground.TileAt(2, 1)) != 1 {
+				t.Fatalf("unexpected tiles: %v", ground.Tiles)
+			}
+
+			// tiles are split from the tileset around its margin and spacing
+			green, ok := m.TileImage(2)
+			if !ok || green.RGBAAt(0, 0) != (color.RGBA{0, 255, 0, 255}) || green.Bounds().Dx() != 4 {
+				t.Fatalf("unexpected tile image for gid 2")
+			}
+			red, _ := m.TileImage(1)
+			if red.RGBAAt(0, 0) != (color.RGBA{0, 0, 0, 255}) {
+				t.Fatalf("expected marked corner in gid 1")
+			}
+			flipped, _ := m.TileImage(ground.TileAt(2, 1))
+			if flipped.RGBAAt(3, 0) != (color.RGBA{0, 0, 0, 255}) || flipped.RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) {
+				t.Fatalf("expected horizontally flipped gid 1")
+			}
+
+			objs := m.Objects()
+			if len(objs) != 4 {
+				t.Fatalf("expected 4 objects, got %v", len(objs))
+			}
+			// objects are offset by their group
+			if objs[0].Rect != floatgeom.NewRect2WH(2, 0, 4, 4) {
+				t.Fatalf("unexpected wall rect: %v", objs[0].Rect)
+			}
+			if !objs[1].Point {
+				t.Fatalf("expected point object")
+			}
+			// tile objects are positioned by their top left corner
+			if objs[2].Rect != floatgeom.NewRect2WH(6, 4, 4, 4) || objs[2].GID != 2 {
+				t.Fatalf("unexpected coin: %+v", objs[2])
+			}
+			if objs[3].Rect != floatgeom.NewRect2(2, 0, 6, 4) || len(objs[3].Polygon) != 3 {
+				t.Fatalf("unexpected ramp: %+v", objs[3])
+			}
+		})
+	}
+}
+
+func TestMapSpaces(t *testing.T) {
+	m, err := tiled.Load("testdata/map.tmx")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	sps := m.Spaces(map[string]collision.Label{"wall": 1})
+	if len(sps) != 3 {
+		t.Fatalf("expected 3 spaces, got %v", len(sps))
+	}
+	if sps[0].Label != 1 || sps[1].Label != 3 || sps[2].Label != 1 {
+		t.Fatalf("unexpected labels: %v %v %v", sps[0].Label, sps[1].Label, sps[2].Label)
+	}
+	if sps[1].X() != 6 || sps[1].Y() != 4 || sps[1].W() != 4 || sps[1].H() != 4 {
+		t.Fatalf("unexpected coin space: %v", sps[1].Location)
+	}
+}
+
+func TestMapSpawn(t *testing.T) {
+	m, err := tiled.Load("testdata/map.json")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	var spawned []string
+	m.Spawn(nil, map[string]tiled.Spawner{
+		"coin": func(ctx *scene.Context, o tiled.Object, opts []entities.Option) {
+			spawned = append(spawned, o.Name)
+			// rect, label, and tile renderable
+			if len(opts) != 3 {
+				t.Fatalf("expected 3 options, got %v", len(opts))
+			}
+		},
+		"spawn": func(ctx *scene.Context, o tiled.Object, opts []entities.Option) {
+			spawned = append(spawned, o.Name)
+		},
+	})
+	if len(spawned) != 2 || spawned[0] != "start" || spawned[1] != "coin" {
+		t.Fatalf("unexpected spawns: %v", spawned)
+	}
+}
+
+func TestMapAddTo(t *testing.T) {
+	m, err := tiled.Load("testdata/map.tmx")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	sps, err := m.AddTo(render.NewDynamicHeap(), 2)
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if len(sps) != 1 {
+		t.Fatalf("expected 1 sprite, got %v", len(sps))
+	}
+	if sps[0].GetLayer() != 2 {
+		t.Fatalf("expected layer 2, got %v", sps[0].GetLayer())
+	}
+	rgba := sps[0].GetRGBA()
+	if rgba.Bounds().Dx() != 12 || rgba.Bounds().Dy() != 8 {
+		t.Fatalf("unexpected layer bounds: %v", rgba.Bounds())
+	}
+	// the ground layer is half transparent
+	if c := rgba.RGBAAt(4, 0); c.A != 127 || c.G != 127 {
+		t.Fatalf("unexpected layer color: %v", c)
+	}
+	if c := rgba.RGBAAt(8, 0); c.A != 0 {
+		t.Fatalf("expected empty tile, got %v", c)
+	}
+	if len(m.TileSprites(m.Layers[0])) != 5 {
+		t.Fatalf("expected 5 tile sprites")
+	}
+}
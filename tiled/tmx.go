@@ -0,0 +1,308 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+type tmxMap struct {
+	Orientation string        `xml:"orientation,attr"`
+	Width       int           `xml:"width,attr"`
+	Height      int           `xml:"height,attr"`
+	TileWidth   int           `xml:"tilewidth,attr"`
+	TileHeight  int           `xml:"tileheight,attr"`
+	Infinite    int           `xml:"infinite,attr"`
+	Properties  []tmxProperty `xml:"properties>property"`
+	Tilesets    []tmxTileset  `xml:"tileset"`
+	// Q: Why not a field per layer type?
+	// A: Layers of different types are interleaved, and their order is their draw order.
+	Layers []tmxLayer `xml:",any"`
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	// multiline string properties are stored as text rather than an attribute
+	Text string `xml:",chardata"`
+}
+
+type tmxTileset struct {
+	FirstGID   uint32        `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Image      tmxImage      `xml:"image"`
+	Tiles      []tmxTile     `xml:"tile"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+}
+
+type tmxTile struct {
+	ID         uint32        `xml:"id,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	Image      tmxImage      `xml:"image"`
+	Animation  []tmxFrame    `xml:"animation>frame"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxFrame struct {
+	TileID   uint32 `xml:"tileid,attr"`
+	Duration int    `xml:"duration,attr"`
+}
+
+type tmxLayer struct {
+	XMLName    xml.Name
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Visible    string        `xml:"visible,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Data       tmxData       `xml:"data"`
+	Objects    []tmxObject   `xml:"object"`
+	Image      tmxImage      `xml:"image"`
+	// Layers are the children of a group layer
+	Layers []tmxLayer `xml:",any"`
+}
+
+type tmxData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+	Text string `xml:",chardata"`
+}
+
+type tmxObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Rotation   float64       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Visible    string        `xml:"visible,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Point      *struct{}     `xml:"point"`
+	Polygon    *tmxPoints    `xml:"polygon"`
+	Polyline   *tmxPoints    `xml:"polyline"`
+}
+
+type tmxPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+func parseTMX(data []byte, dir string) (*Map, error) {
+	var tm tmxMap
+	if err := xml.Unmarshal(data, &tm); err != nil {
+		return nil, err
+	}
+	if err := checkMap(tm.Orientation, tm.Infinite != 0); err != nil {
+		return nil, err
+	}
+	m := &Map{
+		Width:      tm.Width,
+		Height:     tm.Height,
+		TileWidth:  tm.TileWidth,
+		TileHeight: tm.TileHeight,
+		Properties: tmxProperties(tm.Properties),
+	}
+	for _, tts := range tm.Tilesets {
+		if tts.Source != "" {
+			ts, err := loadTileset(filepath.Join(dir, tts.Source), tts.FirstGID)
+			if err != nil {
+				return nil, err
+			}
+			m.Tilesets = append(m.Tilesets, ts)
+			continue
+		}
+		ts := tts.toTileset(dir)
+		ts.FirstGID = tts.FirstGID
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	layers, err := tmxLayers(tm.Layers, dir, floatgeom.Point2{}, true, 1)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+	return m, nil
+}
+
+func parseTSX(data []byte, dir string) (*Tileset, error) {
+	var tts tmxTileset
+	if err := xml.Unmarshal(data, &tts); err != nil {
+		return nil, err
+	}
+	return tts.toTileset(dir), nil
+}
+
+func checkMap(orientation string, infinite bool) error {
+	if orientation != "orthogonal" {
+		return oakerr.UnsupportedFormat{Format: orientation}
+	}
+	if infinite {
+		return oakerr.UnsupportedFormat{Format: "infinite map"}
+	}
+	return nil
+}
+
+func (tts tmxTileset) toTileset(dir string) *Tileset {
+	ts := &Tileset{
+		Name:       tts.Name,
+		TileWidth:  tts.TileWidth,
+		TileHeight: tts.TileHeight,
+		Spacing:    tts.Spacing,
+		Margin:     tts.Margin,
+		TileCount:  tts.TileCount,
+		Columns:    tts.Columns,
+		Tiles:      make(map[uint32]*Tile, len(tts.Tiles)),
+		Properties: tmxProperties(tts.Properties),
+	}
+	if tts.Image.Source != "" {
+		ts.Image = filepath.Join(dir, tts.Image.Source)
+	}
+	for _, tt := range tts.Tiles {
+		t := &Tile{
+			ID:         tt.ID,
+			Type:       tt.Type,
+			Properties: tmxProperties(tt.Properties),
+		}
+		if tt.Class != "" {
+			t.Type = tt.Class
+		}
+		if tt.Image.Source != "" {
+			t.Image = filepath.Join(dir, tt.Image.Source)
+		}
+		for _, f := range tt.Animation {
+			t.Animation = append(t.Animation, Frame{
+				TileID:   f.TileID,
+				Duration: time.Duration(f.Duration) * time.Millisecond,
+			})
+		}
+		ts.Tiles[t.ID] = t
+	}
+	return ts
+}
+
+func tmxProperties(props []tmxProperty) Properties {
+	p := make(Properties, len(props))
+	for _, prop := range props {
+		if prop.Value == "" {
+			prop.Value = prop.Text
+		}
+		p[prop.Name] = prop.Value
+	}
+	return p
+}
+
+// tmxLayers converts layers and flattens groups, given the offset, visibility and opacity of the
+// groups containing them.
+func tmxLayers(tls []tmxLayer, dir string, offset floatgeom.Point2, visible bool, opacity float64) ([]*Layer, error) {
+	var layers []*Layer
+	for _, tl := range tls {
+		l := &Layer{
+			ID:         tl.ID,
+			Name:       tl.Name,
+			Type:       tl.XMLName.Local,
+			Visible:    visible && tl.Visible != "0",
+			Opacity:    opacity,
+			Offset:     offset.Add(floatgeom.Point2{tl.OffsetX, tl.OffsetY}),
+			Properties: tmxProperties(tl.Properties),
+		}
+		if tl.Opacity != nil {
+			l.Opacity *= *tl.Opacity
+		}
+		switch tl.XMLName.Local {
+		case "layer":
+			l.Type = TileLayer
+			l.Width = tl.Width
+			l.Height = tl.Height
+			var err error
+			if tl.Data.Encoding == "" {
+				// tiles are stored as one element per tile
+				l.Tiles = make([]uint32, len(tl.Data.Tiles))
+				for i, t := range tl.Data.Tiles {
+					l.Tiles[i] = t.GID
+				}
+			} else if l.Tiles, err = decodeTiles(tl.Data.Encoding, tl.Data.Compression, tl.Data.Text); err != nil {
+				return nil, err
+			}
+		case ObjectGroup:
+			for _, to := range tl.Objects {
+				o, err := to.toObject()
+				if err != nil {
+					return nil, err
+				}
+				finishObject(&o, l.Offset)
+				l.Objects = append(l.Objects, o)
+			}
+		case ImageLayer:
+			if tl.Image.Source != "" {
+				l.Image = filepath.Join(dir, tl.Image.Source)
+			}
+		case "group":
+			children, err := tmxLayers(tl.Layers, dir, l.Offset, l.Visible, l.Opacity)
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, children...)
+			continue
+		default:
+			// e.g. editor settings
+			continue
+		}
+		layers = append(layers, l)
+	}
+	return layers, nil
+}
+
+func (to tmxObject) toObject() (Object, error) {
+	o := Object{
+		ID:         to.ID,
+		Name:       to.Name,
+		Type:       to.Type,
+		Rect:       floatgeom.NewRect2WH(to.X, to.Y, to.Width, to.Height),
+		Rotation:   to.Rotation,
+		GID:        to.GID,
+		Visible:    to.Visible != "0",
+		Ellipse:    to.Ellipse != nil,
+		Point:      to.Point != nil,
+		Properties: tmxProperties(to.Properties),
+	}
+	if to.Class != "" {
+		o.Type = to.Class
+	}
+	var err error
+	if to.Polygon != nil {
+		if o.Polygon, err = parsePoints(to.Polygon.Points); err != nil {
+			return o, err
+		}
+	}
+	if to.Polyline != nil {
+		if o.Polyline, err = parsePoints(to.Polyline.Points); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
@@ -0,0 +1,149 @@
+package timing
+
+import (
+	"sync"
+	"time"
+)
+
+// A Clock measures game time: time which passes alongside real time, but which can be paused
+// and scaled to run slower or faster than real time. Game time is reported as the duration
+// of game time elapsed since the clock was created.
+type Clock struct {
+	mutex  sync.Mutex
+	paused bool
+	scale  float64
+
+	// elapsed is the game time that had passed as of lastReal
+	elapsed  time.Duration
+	lastReal time.Time
+
+	// changed is closed and replaced whenever the clock is paused, resumed, or rescaled,
+	// so waiters can recompute how long they need to wait.
+	changed chan struct{}
+}
+
+// DefaultClock is the clock used by the engine when no other clock is specified.
+var DefaultClock = NewClock()
+
+// NewClock returns an unpaused clock running at real time.
+func NewClock() *Clock {
+	return &Clock{
+		scale:    1,
+		lastReal: time.Now(),
+		changed:  make(chan struct{}),
+	}
+}
+
+// now must be called while holding the clock's lock.
+func (c *Clock) now() time.Duration {
+	if c.paused {
+		return c.elapsed
+	}
+	return c.elapsed + time.Duration(float64(time.Since(c.lastReal))*c.scale)
+}
+
+// update folds the game time passed so far into elapsed, so the clock's state can change
+// without affecting time that has already passed. It must be called while holding the
+// clock's lock.
+func (c *Clock) update() {
+	c.elapsed = c.now()
+	c.lastReal = time.Now()
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// Now returns how much game time has passed since this clock was created.
+func (c *Clock) Now() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now()
+}
+
+// Since returns how much game time has passed since t, a value previously returned by Now.
+func (c *Clock) Since(t time.Duration) time.Duration {
+	return c.Now() - t
+}
+
+// Pause stops game time from passing until Resume is called.
+func (c *Clock) Pause() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.paused {
+		return
+	}
+	c.update()
+	c.paused = true
+}
+
+// Resume undoes a call to Pause.
+func (c *Clock) Resume() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.paused {
+		return
+	}
+	c.update()
+	c.paused = false
+}
+
+// Paused returns whether this clock is paused.
+func (c *Clock) Paused() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.paused
+}
+
+// SetScale sets how quickly game time passes relative to real time. A scale of 0.5 runs at half
+// speed, and a scale of 2 runs at double speed. Negative scales are treated as 0.
+func (c *Clock) SetScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.update()
+	c.scale = scale
+}
+
+// Scale returns how quickly game time passes relative to real time.
+func (c *Clock) Scale() float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.scale
+}
+
+// Wait blocks until d of game time has passed, returning true, or until done is closed,
+// returning false. A nil done channel will never be closed.
+func (c *Clock) Wait(done <-chan struct{}, d time.Duration) bool {
+	target := c.Now() + d
+	for {
+		c.mutex.Lock()
+		remaining := target - c.now()
+		if remaining <= 0 {
+			c.mutex.Unlock()
+			return true
+		}
+		changed := c.changed
+		var timer *time.Timer
+		var timerCh <-chan time.Time
+		// while paused or stopped, only a change to the clock can end the wait
+		if !c.paused && c.scale > 0 {
+			timer = time.NewTimer(time.Duration(float64(remaining) / c.scale))
+			timerCh = timer.C
+		}
+		c.mutex.Unlock()
+
+		select {
+		case <-timerCh:
+		case <-changed:
+		case <-done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return false
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package timing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	t.Parallel()
+	t.Run("Paused", func(t *testing.T) {
+		t.Parallel()
+		c := NewClock()
+		c.Pause()
+		now := c.Now()
+		time.Sleep(20 * time.Millisecond)
+		if c.Now() != now {
+			t.Fatalf("paused clock advanced from %v to %v", now, c.Now())
+		}
+		c.Resume()
+		time.Sleep(20 * time.Millisecond)
+		if c.Since(now) < 20*time.Millisecond {
+			t.Fatalf("resumed clock advanced only %v", c.Since(now))
+		}
+	})
+	t.Run("Scaled", func(t *testing.T) {
+		t.Parallel()
+		c := NewClock()
+		c.SetScale(0.5)
+		start := c.Now()
+		time.Sleep(100 * time.Millisecond)
+		elapsed := c.Since(start)
+		if elapsed < 50*time.Millisecond || elapsed > 80*time.Millisecond {
+			t.Fatalf("half speed clock advanced %v in 100ms", elapsed)
+		}
+		c.SetScale(-1)
+		if c.Scale() != 0 {
+			t.Fatalf("expected negative scale to become 0, got %v", c.Scale())
+		}
+	})
+	t.Run("WaitResumes", func(t *testing.T) {
+		t.Parallel()
+		c := NewClock()
+		c.Pause()
+		done := make(chan bool)
+		go func() {
+			done <- c.Wait(nil, 10*time.Millisecond)
+		}()
+		select {
+		case <-done:
+			t.Fatal("wait completed while clock was paused")
+		case <-time.After(50 * time.Millisecond):
+		}
+		c.Resume()
+		select {
+		case ok := <-done:
+			if !ok {
+				t.Fatal("wait reported cancellation")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("wait did not complete after resuming")
+		}
+	})
+	t.Run("WaitCancelled", func(t *testing.T) {
+		t.Parallel()
+		c := NewClock()
+		cancel := make(chan struct{})
+		close(cancel)
+		if c.Wait(cancel, time.Hour) {
+			t.Fatal("cancelled wait reported success")
+		}
+	})
+}
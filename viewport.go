@@ -36,7 +36,7 @@ func (w *Window) SetViewport(pt intgeom.Point2) {
 	} else {
 		w.viewPos = pt
 	}
-	event.TriggerOn(w.eventHandler, ViewportUpdate, w.viewPos)
+	event.TriggerOn(w.handler(), ViewportUpdate, w.viewPos)
 }
 
 // ViewportBounds returns the boundary of this window's viewport, or the rectangle
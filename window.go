@@ -17,6 +17,7 @@ import (
 	"image"
 	"io"
 	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/oakmound/oak/v4/scene"
 	"github.com/oakmound/oak/v4/shiny/driver"
 	"github.com/oakmound/oak/v4/shiny/screen"
+	"github.com/oakmound/oak/v4/timing"
 	"github.com/oakmound/oak/v4/window"
 )
 
@@ -142,12 +144,43 @@ type Window struct {
 	// as well, it will fall back to panicking.
 	ErrorScene string
 
+	// eventHandler, CallerMap, MouseTree and CollisionTree belong to the scene on top of the scene stack.
+	// layerMutex guards them, as they are swapped when scenes are pushed and popped.
 	eventHandler  event.Handler
 	CallerMap     *event.CallerMap
 	MouseTree     *collision.Tree
 	CollisionTree *collision.Tree
+	layerMutex    sync.RWMutex
 	DrawStack     *render.DrawStack
 
+	// preloads tracks scenes whose assets are loading or loaded, by scene name
+	preloads     map[string]*Preload
+	preloadMutex sync.Mutex
+
+	// lastFrame is a snapshot of the last frame drawn by the previous scene, and blend
+	// is a blend in progress from that frame into the current scene
+	lastFrame  *image.RGBA
+	blend      *activeBlend
+	blendMutex sync.Mutex
+
+	// sceneStack holds scenes pushed on top of the current scene via PushScene
+	sceneStack      []*pushedScene
+	sceneStackMutex sync.Mutex
+
+	// camera controls the viewport, if set, and zoomBuffer holds the world drawn at the camera's zoom.
+	// splitViews, if set, replace the viewport with several views drawn to parts of the window.
+	camera        *Camera
+	splitViews    []*SplitView
+	cameraUpdated time.Time
+	cameraMutex   sync.Mutex
+	zoomBuffer    *image.RGBA
+
+	// Clock measures game time, driving enter events and scene delays. Its pause state
+	// and time scale are reset when each scene starts. Each window has its own clock,
+	// except the default window, which uses timing.DefaultClock, as does a window whose
+	// Clock is nil.
+	Clock *timing.Clock
+
 	// LastMouseEvent is the last triggered mouse event,
 	// tracked for continuous mouse responsiveness on events
 	// that don't take in a mouse event
@@ -202,6 +235,7 @@ func NewWindow() *Window {
 		CollisionTree: collision.DefaultTree,
 		CallerMap:     event.DefaultCallerMap,
 		DrawStack:     render.GlobalDrawStack,
+		Clock:         timing.NewClock(),
 		ControllerID:  atomic.AddInt32(nextControllerID, 1),
 		ParentContext: context.Background(),
 	}
@@ -209,12 +243,15 @@ func NewWindow() *Window {
 
 // Propagate triggers direct mouse events on entities which are clicked
 func (w *Window) Propagate(ev event.EventID[*mouse.Event], me mouse.Event) {
-	hits := w.MouseTree.SearchIntersect(me.ToSpace().Bounds())
+	w.layerMutex.RLock()
+	handler, mouseTree := w.eventHandler, w.MouseTree
+	w.layerMutex.RUnlock()
+	hits := mouseTree.SearchIntersect(me.ToSpace().Bounds())
 	sort.Slice(hits, func(i, j int) bool {
 		return hits[i].Location.Min.Z() > hits[j].Location.Max.Z()
 	})
 	for _, sp := range hits {
-		<-event.TriggerForCallerOn(w.eventHandler, sp.CID, ev, &me)
+		<-event.TriggerForCallerOn(handler, sp.CID, ev, &me)
 		if me.StopPropagation {
 			break
 		}
@@ -227,16 +264,16 @@ func (w *Window) Propagate(ev event.EventID[*mouse.Event], me mouse.Event) {
 		w.LastMousePress = me
 	} else if ev == mouse.ReleaseOn {
 		if me.Button == w.LastMousePress.Button {
-			event.TriggerOn(w.eventHandler, mouse.Click, &me)
+			event.TriggerOn(handler, mouse.Click, &me)
 
-			pressHits := w.MouseTree.SearchIntersect(w.LastMousePress.ToSpace().Bounds())
+			pressHits := mouseTree.SearchIntersect(w.LastMousePress.ToSpace().Bounds())
 			sort.Slice(pressHits, func(i, j int) bool {
 				return pressHits[i].Location.Min.Z() > pressHits[j].Location.Max.Z()
 			})
 			for _, sp1 := range pressHits {
 				for _, sp2 := range hits {
 					if sp1.CID == sp2.CID {
-						<-event.TriggerForCallerOn(w.eventHandler, sp1.CID, mouse.ClickOn, &me)
+						<-event.TriggerForCallerOn(handler, sp1.CID, mouse.ClickOn, &me)
 						if me.StopPropagation {
 							return
 						}
@@ -246,14 +283,14 @@ func (w *Window) Propagate(ev event.EventID[*mouse.Event], me mouse.Event) {
 		}
 	} else if ev == mouse.RelativeReleaseOn {
 		if me.Button == w.lastRelativePress.Button {
-			pressHits := w.MouseTree.SearchIntersect(w.lastRelativePress.ToSpace().Bounds())
+			pressHits := mouseTree.SearchIntersect(w.lastRelativePress.ToSpace().Bounds())
 			sort.Slice(pressHits, func(i, j int) bool {
 				return pressHits[i].Location.Min.Z() > pressHits[j].Location.Max.Z()
 			})
 			for _, sp1 := range pressHits {
 				for _, sp2 := range hits {
 					if sp1.CID == sp2.CID {
-						<-event.TriggerForCallerOn(w.eventHandler, sp1.CID, mouse.RelativeClickOn, &me)
+						<-event.TriggerForCallerOn(handler, sp1.CID, mouse.RelativeClickOn, &me)
 						if me.StopPropagation {
 							return
 						}
@@ -297,9 +334,13 @@ func (w *Window) GetBackgroundImage() image.Image {
 }
 
 // SetLogicHandler swaps the logic system of the engine with some other
-// implementation. If this is never called, it will use event.DefaultBus
+// implementation. If this is never called, it will use event.DefaultBus.
+// If the handler is an event.Stepper, enter events will not be triggered on
+// a timer; the handler's Step method must be called to advance frames.
 func (w *Window) SetLogicHandler(h event.Handler) {
+	w.layerMutex.Lock()
 	w.eventHandler = h
+	w.layerMutex.Unlock()
 }
 
 // NextScene  causes this window to immediately end the current scene.
@@ -321,6 +362,13 @@ func (w *Window) InFocus() bool {
 
 // EventHandler returns this window's event handler.
 func (w *Window) EventHandler() event.Handler {
+	return w.handler()
+}
+
+// handler returns the event handler of the scene on top of this window's scene stack.
+func (w *Window) handler() event.Handler {
+	w.layerMutex.RLock()
+	defer w.layerMutex.RUnlock()
 	return w.eventHandler
 }
 
@@ -340,3 +388,11 @@ func (w *Window) debugConsole(input io.Reader, output io.Writer) {
 	debugstream.AttachToStream(w.ParentContext, input, output)
 	debugstream.AddDefaultsForScope(w.ControllerID, w)
 }
+
+// clock returns this window's clock, or timing.DefaultClock if it has none.
+func (w *Window) clock() *timing.Clock {
+	if w.Clock == nil {
+		return timing.DefaultClock
+	}
+	return w.Clock
+}
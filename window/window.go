@@ -57,6 +57,11 @@ type App interface {
 	NextScene()
 	// GoToScene causes the End function to be triggered for the current scene, overriding the next scene to start.
 	GoToScene(string)
+	// PushScene starts a scene on top of the current scene, with its own event handler, callers, collision trees,
+	// and draw stack, without ending the scene beneath it.
+	PushScene(name string, opts PushOptions) error
+	// PopScene ends the most recently pushed scene, returning control to the scene beneath it.
+	PopScene() error
 
 	// InFocus returns whether the application is currently focused on, by whatever definition the OS has for an
 	// application being in focus. For example, on linux/osx/windows a window is in focus once it is clicked on
@@ -68,3 +73,13 @@ type App interface {
 	// EventHandler returns this app's active event handler.
 	EventHandler() event.Handler
 }
+
+// PushOptions configure how a scene is pushed on top of another via PushScene.
+type PushOptions struct {
+	// Input is passed to the pushed scene as its SceneInput.
+	Input interface{}
+	// KeepRunning causes the scene beneath to keep receiving enter events and its game time to keep
+	// passing. Otherwise, it is frozen until the pushed scene is popped. In either case, it keeps drawing
+	// beneath the pushed scene, but stops receiving input.
+	KeepRunning bool
+}